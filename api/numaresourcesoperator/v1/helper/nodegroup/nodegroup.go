@@ -19,6 +19,7 @@ package nodegroup
 import (
 	"fmt"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/klog/v2"
@@ -28,11 +29,52 @@ import (
 	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
 )
 
+const (
+	masterPoolName = "master"
+	workerPoolName = "worker"
+)
+
+// Tree represents a node group and the MachineConfigPools it selects.
+// Node groups defined by NodeSelector select no MachineConfigPools, but their trees may hold
+// the MachineConfigPools their nodes belong to, see FindNodeSelectorMachineConfigPools.
 type Tree struct {
 	NodeGroup          *nropv1.NodeGroup
 	MachineConfigPools []*mcov1.MachineConfigPool
 }
 
+// Pool is a set of nodes which share a single RTE daemonset.
+// A Pool maps either to a MachineConfigPool or to a node group defined by NodeSelector.
+type Pool struct {
+	// Name is the MachineConfigPool name or the node group name
+	Name string
+	// MachineConfigPool is nil for node groups defined by NodeSelector
+	MachineConfigPool *mcov1.MachineConfigPool
+	// NodeSelector selects the nodes belonging to this pool
+	NodeSelector *metav1.LabelSelector
+}
+
+// Pools returns the Pools of this Tree: one for each MachineConfigPool,
+// or a single one if the node group is defined by NodeSelector.
+func (ttr Tree) Pools() []Pool {
+	if ttr.NodeGroup != nil && ttr.NodeGroup.NodeSelector != nil {
+		return []Pool{
+			{
+				Name:         ttr.NodeGroup.Name,
+				NodeSelector: ttr.NodeGroup.NodeSelector,
+			},
+		}
+	}
+	pools := make([]Pool, 0, len(ttr.MachineConfigPools))
+	for _, mcp := range ttr.MachineConfigPools {
+		pools = append(pools, Pool{
+			Name:              mcp.Name,
+			MachineConfigPool: mcp,
+			NodeSelector:      mcp.Spec.NodeSelector,
+		})
+	}
+	return pools
+}
+
 func (ttr Tree) Clone() Tree {
 	ret := Tree{
 		NodeGroup:          ttr.NodeGroup.DeepCopy(),
@@ -49,6 +91,13 @@ func FindTrees(mcps *mcov1.MachineConfigPoolList, nodeGroups []nropv1.NodeGroup)
 	for idx := range nodeGroups {
 		nodeGroup := &nodeGroups[idx] // shortcut
 
+		if nodeGroup.NodeSelector != nil {
+			result = append(result, Tree{
+				NodeGroup: nodeGroup,
+			})
+			continue
+		}
+
 		if nodeGroup.MachineConfigPoolSelector == nil {
 			continue
 		}
@@ -81,6 +130,87 @@ func FindTrees(mcps *mcov1.MachineConfigPoolList, nodeGroups []nropv1.NodeGroup)
 	return result, nil
 }
 
+// FindNodeSelectorMachineConfigPools sets the MachineConfigPools of the trees of the node groups defined by NodeSelector
+// to the MachineConfigPools their nodes belong to, which must carry the RTE machine configuration as well.
+// Each MachineConfigPool is set only in the first tree which needs it, and never in the trees of the node
+// groups defined by MachineConfigPoolSelector, which already own theirs.
+func FindNodeSelectorMachineConfigPools(trees []Tree, mcps *mcov1.MachineConfigPoolList, nodes []corev1.Node) error {
+	seen := make(map[string]bool)
+	for _, tree := range trees {
+		for _, mcp := range tree.MachineConfigPools {
+			seen[mcp.Name] = true
+		}
+	}
+
+	for idx := range trees {
+		tree := &trees[idx] // shortcut
+		if tree.NodeGroup == nil || tree.NodeGroup.NodeSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(tree.NodeGroup.NodeSelector)
+		if err != nil {
+			return err
+		}
+		tree.MachineConfigPools = nil
+		for nodeIdx := range nodes {
+			node := &nodes[nodeIdx] // shortcut
+			if !selector.Matches(labels.Set(node.Labels)) {
+				continue
+			}
+			mcp, err := findNodeMachineConfigPool(mcps, node)
+			if err != nil {
+				return fmt.Errorf("the node group %q: %w", tree.NodeGroup.Name, err)
+			}
+			if seen[mcp.Name] {
+				continue
+			}
+			seen[mcp.Name] = true
+			tree.MachineConfigPools = append(tree.MachineConfigPools, mcp)
+		}
+	}
+	return nil
+}
+
+// findNodeMachineConfigPool returns the MachineConfigPool the node belongs to, picking it
+// like the machine config operator does: the master pool wins, then the custom pools win
+// over the worker pool.
+func findNodeMachineConfigPool(mcps *mcov1.MachineConfigPoolList, node *corev1.Node) (*mcov1.MachineConfigPool, error) {
+	var matched []*mcov1.MachineConfigPool
+	var worker *mcov1.MachineConfigPool
+	for i := range mcps.Items {
+		mcp := &mcps.Items[i] // shortcut
+		if mcp.Spec.NodeSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(mcp.Spec.NodeSelector)
+		if err != nil {
+			return nil, fmt.Errorf("bad MachineConfigPool %q node selector: %w", mcp.Name, err)
+		}
+		if !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		switch mcp.Name {
+		case masterPoolName:
+			return mcp, nil
+		case workerPoolName:
+			worker = mcp
+		default:
+			matched = append(matched, mcp)
+		}
+	}
+
+	if len(matched) > 1 {
+		return nil, fmt.Errorf("the node %q belongs to more than one MachineConfigPool", node.Name)
+	}
+	if len(matched) == 1 {
+		return matched[0], nil
+	}
+	if worker != nil {
+		return worker, nil
+	}
+	return nil, fmt.Errorf("the node %q belongs to no MachineConfigPool, so it can't get the RTE machine configuration", node.Name)
+}
+
 func FindMachineConfigPools(mcps *mcov1.MachineConfigPoolList, nodeGroups []nropv1.NodeGroup) ([]*mcov1.MachineConfigPool, error) {
 	trees, err := FindTrees(mcps, nodeGroups)
 	if err != nil {
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/klog/v2"
@@ -366,6 +367,304 @@ func TestFindMachineConfigPools(t *testing.T) {
 	}
 }
 
+func TestTreePools(t *testing.T) {
+	mcpList := mcov1.MachineConfigPoolList{
+		Items: []mcov1.MachineConfigPool{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "mcp1",
+					Labels: map[string]string{
+						"mcp-label-1": "test1",
+					},
+				},
+				Spec: mcov1.MachineConfigPoolSpec{
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"node-role.kubernetes.io/mcp1": "",
+						},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "mcp2",
+					Labels: map[string]string{
+						"mcp-label-1": "test1",
+					},
+				},
+				Spec: mcov1.MachineConfigPoolSpec{
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"node-role.kubernetes.io/mcp2": "",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		ngs           []nropv1.NodeGroup
+		expectedPools []string
+		expectedMCPs  []string
+	}{
+		{
+			name: "no-node-groups",
+		},
+		{
+			name: "mcp-selector",
+			ngs: []nropv1.NodeGroup{
+				{
+					MachineConfigPoolSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"mcp-label-1": "test1",
+						},
+					},
+				},
+			},
+			expectedPools: []string{"mcp1", "mcp2"},
+			expectedMCPs:  []string{"mcp1", "mcp2"},
+		},
+		{
+			name: "node-selector",
+			ngs: []nropv1.NodeGroup{
+				{
+					Name: "ng-nodes",
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"node-label": "test",
+						},
+					},
+				},
+			},
+			expectedPools: []string{"ng-nodes"},
+		},
+		{
+			name: "mixed",
+			ngs: []nropv1.NodeGroup{
+				{
+					Name: "ng-nodes",
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"node-label": "test",
+						},
+					},
+				},
+				{
+					MachineConfigPoolSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"mcp-label-1": "test1",
+						},
+					},
+				},
+			},
+			expectedPools: []string{"ng-nodes", "mcp1", "mcp2"},
+			expectedMCPs:  []string{"mcp1", "mcp2"},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			trees, err := FindTrees(&mcpList, tt.ngs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(trees) != len(tt.ngs) {
+				t.Fatalf("expected one tree per node group: got=%d expected=%d", len(trees), len(tt.ngs))
+			}
+
+			var gotPools []string
+			for _, tree := range trees {
+				for _, pool := range tree.Pools() {
+					if pool.NodeSelector == nil {
+						t.Errorf("pool %q has no node selector", pool.Name)
+					}
+					if pool.MachineConfigPool == nil && tree.NodeGroup.NodeSelector == nil {
+						t.Errorf("pool %q has no MachineConfigPool", pool.Name)
+					}
+					gotPools = append(gotPools, pool.Name)
+				}
+			}
+			if !reflect.DeepEqual(gotPools, tt.expectedPools) {
+				t.Errorf("pools mismatch: got=%v expected=%v", gotPools, tt.expectedPools)
+			}
+
+			gotMCPs := mcpNamesFromTrees(trees)
+			if !reflect.DeepEqual(gotMCPs, tt.expectedMCPs) {
+				t.Errorf("MCPs mismatch: got=%v expected=%v", gotMCPs, tt.expectedMCPs)
+			}
+		})
+	}
+}
+
+func TestFindNodeSelectorMachineConfigPools(t *testing.T) {
+	newMCP := func(name string) mcov1.MachineConfigPool {
+		return mcov1.MachineConfigPool{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					"mcp": name,
+				},
+			},
+			Spec: mcov1.MachineConfigPoolSpec{
+				NodeSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"node-role.kubernetes.io/" + name: "",
+					},
+				},
+			},
+		}
+	}
+	newNode := func(name string, roles ...string) corev1.Node {
+		node := corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					"numa-" + name: "true",
+				},
+			},
+		}
+		for _, role := range roles {
+			node.Labels["node-role.kubernetes.io/"+role] = ""
+		}
+		return node
+	}
+	// nodeSelectorGroup selects all the nodes except the given ones, which keeps the test cases short
+	nodeSelectorGroup := func(name string, excludedNodeNames ...string) nropv1.NodeGroup {
+		sel := &metav1.LabelSelector{}
+		for _, nodeName := range excludedNodeNames {
+			sel.MatchExpressions = append(sel.MatchExpressions, metav1.LabelSelectorRequirement{
+				Key:      "numa-" + nodeName,
+				Operator: metav1.LabelSelectorOpDoesNotExist,
+			})
+		}
+		return nropv1.NodeGroup{
+			Name:         name,
+			NodeSelector: sel,
+		}
+	}
+
+	mcps := mcov1.MachineConfigPoolList{
+		Items: []mcov1.MachineConfigPool{
+			newMCP("master"),
+			newMCP("worker"),
+			newMCP("worker-cnf"),
+			newMCP("worker-rt"),
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		ngs           []nropv1.NodeGroup
+		nodes         []corev1.Node
+		expectedMCPs  [][]string
+		expectedError bool
+	}{
+		{
+			name:         "no-nodes",
+			ngs:          []nropv1.NodeGroup{nodeSelectorGroup("ng")},
+			expectedMCPs: [][]string{nil},
+		},
+		{
+			name: "worker",
+			ngs:  []nropv1.NodeGroup{nodeSelectorGroup("ng")},
+			nodes: []corev1.Node{
+				newNode("node-0", "worker"),
+				newNode("node-1", "worker"),
+			},
+			expectedMCPs: [][]string{{"worker"}},
+		},
+		{
+			name: "custom-pool-wins-over-worker",
+			ngs:  []nropv1.NodeGroup{nodeSelectorGroup("ng")},
+			nodes: []corev1.Node{
+				newNode("node-0", "worker", "worker-cnf"),
+			},
+			expectedMCPs: [][]string{{"worker-cnf"}},
+		},
+		{
+			name: "master-wins",
+			ngs:  []nropv1.NodeGroup{nodeSelectorGroup("ng")},
+			nodes: []corev1.Node{
+				newNode("node-0", "master", "worker"),
+			},
+			expectedMCPs: [][]string{{"master"}},
+		},
+		{
+			name: "shared-pool-only-once",
+			ngs: []nropv1.NodeGroup{
+				nodeSelectorGroup("ng-0", "node-1"),
+				nodeSelectorGroup("ng-1", "node-0"),
+			},
+			nodes: []corev1.Node{
+				newNode("node-0", "worker"),
+				newNode("node-1", "worker"),
+			},
+			expectedMCPs: [][]string{{"worker"}, nil},
+		},
+		{
+			name: "mcp-selector-pool-untouched",
+			ngs: []nropv1.NodeGroup{
+				{
+					MachineConfigPoolSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"mcp": "worker-cnf",
+						},
+					},
+				},
+				nodeSelectorGroup("ng"),
+			},
+			nodes: []corev1.Node{
+				newNode("node-0", "worker", "worker-cnf"),
+				newNode("node-1", "worker"),
+			},
+			expectedMCPs: [][]string{{"worker-cnf"}, {"worker"}},
+		},
+		{
+			name: "no-pool",
+			ngs:  []nropv1.NodeGroup{nodeSelectorGroup("ng")},
+			nodes: []corev1.Node{
+				newNode("node-0"),
+			},
+			expectedError: true,
+		},
+		{
+			name: "ambiguous-pools",
+			ngs:  []nropv1.NodeGroup{nodeSelectorGroup("ng")},
+			nodes: []corev1.Node{
+				newNode("node-0", "worker-cnf", "worker-rt"),
+			},
+			expectedError: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			trees, err := FindTrees(&mcps, tt.ngs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = FindNodeSelectorMachineConfigPools(trees, &mcps, tt.nodes)
+			if tt.expectedError {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var gotMCPs [][]string
+			for _, tree := range trees {
+				gotMCPs = append(gotMCPs, mcpNamesFromList(tree.MachineConfigPools))
+			}
+			if !reflect.DeepEqual(gotMCPs, tt.expectedMCPs) {
+				t.Errorf("MCPs mismatch: got=%v expected=%v", gotMCPs, tt.expectedMCPs)
+			}
+		})
+	}
+}
+
 func mcpNamesFromTrees(trees []Tree) []string {
 	var result []string
 	for _, tree := range trees {
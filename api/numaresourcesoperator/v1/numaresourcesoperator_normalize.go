@@ -49,6 +49,13 @@ func (current NodeGroupConfig) Merge(updated NodeGroupConfig) NodeGroupConfig {
 	if updated.InfoRefreshPause != nil {
 		conf.InfoRefreshPause = updated.InfoRefreshPause
 	}
+	if updated.ResourceExcludes != nil {
+		conf.ResourceExcludes = SortedResourceExcludes(updated.ResourceExcludes)
+	}
+	if updated.PodExcludes != nil {
+		// order matters, so we must not sort
+		conf.PodExcludes = append([]NamespacedName{}, updated.PodExcludes...)
+	}
 	return conf
 }
 
@@ -77,3 +84,23 @@ func SortedTolerations(tols []corev1.Toleration) []corev1.Toleration {
 	})
 	return ret
 }
+
+// SortedResourceExcludes return a clone of the provided resource excludes, with
+// the resource names of each node pattern sorted and deduplicated
+func SortedResourceExcludes(resExcludes map[string][]string) map[string][]string {
+	ret := make(map[string][]string, len(resExcludes))
+	for nodePattern, resNames := range resExcludes {
+		names := make([]string, 0, len(resNames))
+		seen := make(map[string]struct{}, len(resNames))
+		for _, resName := range resNames {
+			if _, ok := seen[resName]; ok {
+				continue
+			}
+			seen[resName] = struct{}{}
+			names = append(names, resName)
+		}
+		sort.Strings(names)
+		ret[nodePattern] = names
+	}
+	return ret
+}
@@ -97,6 +97,41 @@ func TestNodeGroupConfigMerge(t *testing.T) {
 				InfoRefreshPause: ptrToRTEMode(InfoRefreshPauseEnabled),
 			},
 		},
+		{
+			description: "override resource excludes from default",
+			current:     DefaultNodeGroupConfig(),
+			updated: NodeGroupConfig{
+				ResourceExcludes: map[string][]string{
+					"*": {"memory", "hugepages-2Mi"},
+				},
+			},
+			expected: NodeGroupConfig{
+				PodsFingerprinting: &podsFp,
+				InfoRefreshMode:    &refMode,
+				InfoRefreshPeriod: &metav1.Duration{
+					Duration: 10 * time.Second,
+				},
+				InfoRefreshPause: ptrToRTEMode(InfoRefreshPauseDisabled),
+				ResourceExcludes: map[string][]string{
+					"*": {"hugepages-2Mi", "memory"},
+				},
+			},
+		},
+		{
+			description: "override pod excludes keeps the order",
+			updated: NodeGroupConfig{
+				PodExcludes: []NamespacedName{
+					{Namespace: "ns-b", Name: "foo"},
+					{Namespace: "ns-a", Name: "bar"},
+				},
+			},
+			expected: NodeGroupConfig{
+				PodExcludes: []NamespacedName{
+					{Namespace: "ns-b", Name: "foo"},
+					{Namespace: "ns-a", Name: "bar"},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -285,3 +320,47 @@ func TestSortedTolerations(t *testing.T) {
 		})
 	}
 }
+
+func TestSortedResourceExcludes(t *testing.T) {
+	type testCase struct {
+		name     string
+		resExcl  map[string][]string
+		expected map[string][]string
+	}
+
+	testCases := []testCase{
+		{
+			name:     "nil",
+			expected: map[string][]string{},
+		},
+		{
+			name: "sorted",
+			resExcl: map[string][]string{
+				"*":        {"cpu"},
+				"worker-*": {"hugepages-1Gi", "memory"},
+			},
+			expected: map[string][]string{
+				"*":        {"cpu"},
+				"worker-*": {"hugepages-1Gi", "memory"},
+			},
+		},
+		{
+			name: "unsorted with duplicates",
+			resExcl: map[string][]string{
+				"worker-*": {"memory", "hugepages-1Gi", "memory"},
+			},
+			expected: map[string][]string{
+				"worker-*": {"hugepages-1Gi", "memory"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := SortedResourceExcludes(tc.resExcl)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("mismatched resource excludes:\ngot=%+v\nexpected=%+v", got, tc.expected)
+			}
+		})
+	}
+}
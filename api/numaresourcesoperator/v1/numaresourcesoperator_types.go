@@ -41,12 +41,59 @@ type NUMAResourcesOperatorSpec struct {
 	// +kubebuilder:default=Normal
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="RTE log verbosity"
 	LogLevel operatorv1.LogLevel `json:"logLevel,omitempty"`
-	// Optional Namespace/Name glob patterns of pod to ignore at node level
+	// Optional Namespace/Name glob patterns of pod to ignore at node level.
+	// The patterns are evaluated in order, and more patterns can share the same namespace.
 	// +optional
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Optional ignore pod namespace/name glob patterns"
 	PodExcludes []NamespacedName `json:"podExcludes,omitempty"`
+	// StaleNodeTopologyPolicy defines how to handle the nodes whose NodeResourceTopology data is missing or stale.
+	// Valid values are: "Ignore", "Exclude".
+	// Defaults to "Ignore".
+	// +optional
+	// +kubebuilder:default=Ignore
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Handling of the nodes with stale topology data",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	StaleNodeTopologyPolicy StaleNodeTopologyPolicy `json:"staleNodeTopologyPolicy,omitempty"`
+	// RTEMetrics defines if the resource topology exporters serve their metrics.
+	// When enabled, the metrics are served over TLS using a service-serving certificate, and
+	// the operator creates the Service, the ServiceMonitor and the NetworkPolicy to let the monitoring stack scrape them.
+	// Valid values are: "Disabled", "Enabled".
+	// Defaults to "Disabled".
+	// +optional
+	// +kubebuilder:default=Disabled
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Serve the RTE metrics",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	RTEMetrics RTEMetricsMode `json:"rteMetrics,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=Disabled;Enabled
+type RTEMetricsMode string
+
+const (
+	// RTEMetricsDisabled does not serve the RTE metrics. It is the default.
+	RTEMetricsDisabled RTEMetricsMode = "Disabled"
+
+	// RTEMetricsEnabled serves the RTE metrics over TLS, and makes them available to the monitoring stack.
+	// Supported only on OpenShift, whose service-ca and monitoring stack it relies on.
+	RTEMetricsEnabled RTEMetricsMode = "Enabled"
+)
+
+// +kubebuilder:validation:Enum=Ignore;Exclude
+type StaleNodeTopologyPolicy string
+
+const (
+	// StaleNodeTopologyIgnore only reports the nodes with stale topology data. It is the default.
+	StaleNodeTopologyIgnore StaleNodeTopologyPolicy = "Ignore"
+
+	// StaleNodeTopologyExclude labels the nodes with stale topology data using StaleNodeTopologyLabel,
+	// so the NUMA-aware scheduler filters them out. The label is removed once the data is fresh again.
+	// The data is stale when the resource topology exporter published no update for more than three
+	// refresh periods; it stamps every update, so the nodes which are just idle are never excluded.
+	StaleNodeTopologyExclude StaleNodeTopologyPolicy = "Exclude"
+)
+
+// StaleNodeTopologyLabel marks the nodes the NUMA-aware scheduler must not consider, because their
+// topology data is missing or stale. Only the NUMA-aware scheduler profile filters on this label.
+const StaleNodeTopologyLabel = "numaresourcesoperator.nodetopology.openshift.io/stale-topology"
+
 // +kubebuilder:validation:Enum=Disabled;Enabled;EnabledExclusiveResources
 type PodsFingerprintingMode string
 
@@ -109,14 +156,34 @@ type NodeGroupConfig struct {
 	// +optional
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Extra tolerations for the topology updater daemonset",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// ResourceExcludes defines the resources which should not be reported in the topology info, per node.
+	// The keys are node name glob patterns, use "*" to match all the nodes; the values are the resource names to exclude.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Optional resources to exclude from the topology info, per node name glob pattern"
+	ResourceExcludes map[string][]string `json:"resourceExcludes,omitempty"`
+	// PodExcludes defines the Namespace/Name glob patterns of the pods to ignore on the machines belonging to this group.
+	// These are appended to the global PodExcludes.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Optional ignore pod namespace/name glob patterns for this node group"
+	PodExcludes []NamespacedName `json:"podExcludes,omitempty"`
 }
 
 // NodeGroup defines group of nodes that will run resource topology exporter daemon set
 // You can choose the group of node by MachineConfigPoolSelector or by NodeSelector
 type NodeGroup struct {
+	// Name identifies this node group. Required when the group is defined using NodeSelector,
+	// because it is used to name the objects the operator creates for the group.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Node group name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Name string `json:"name,omitempty"`
 	// MachineConfigPoolSelector defines label selector for the machine config pool
 	// +optional
 	MachineConfigPoolSelector *metav1.LabelSelector `json:"machineConfigPoolSelector,omitempty"`
+	// NodeSelector defines label selector for the nodes belonging to this node group.
+	// Use this to define node groups without MachineConfigPools. Mutually exclusive with MachineConfigPoolSelector.
+	// On OpenShift, the MachineConfigPools the selected nodes belong to still get the RTE machine configuration.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
 	// Config defines the RTE behavior for this NodeGroup
 	// +optional
 	Config *NodeGroupConfig `json:"config,omitempty"`
@@ -124,12 +191,20 @@ type NodeGroup struct {
 
 // NUMAResourcesOperatorStatus defines the observed state of NUMAResourcesOperator
 type NUMAResourcesOperatorStatus struct {
+	// ObservedGeneration is the most recent generation of the spec observed by the operator
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Observed generation"
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// DaemonSets of the configured RTEs, one per node group
 	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="RTE DaemonSets"
 	DaemonSets []NamespacedName `json:"daemonsets,omitempty"`
 	// MachineConfigPools resolved from configured node groups
 	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="RTE MCPs from node groups"
 	MachineConfigPools []MachineConfigPool `json:"machineconfigpools,omitempty"`
+	// NodeGroups reports the observed state of each node group
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Node groups observed state"
+	NodeGroups []NodeGroupStatus `json:"nodeGroups,omitempty"`
 	// Conditions show the current state of the NUMAResourcesOperator Operator
 	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Condition reported"
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
@@ -152,6 +227,35 @@ type MachineConfigPool struct {
 	Config *NodeGroupConfig `json:"config,omitempty"`
 }
 
+// NodeGroupStatus defines the observed state of the RTE deployment on a group of nodes.
+// Node groups defined using MachineConfigPoolSelector have an entry per matching MachineConfigPool.
+type NodeGroupStatus struct {
+	// Name identifies the node group: the MachineConfigPool name or the node group name
+	Name string `json:"name"`
+	// MachineConfigPool is the name of the MachineConfigPool backing this node group, if any
+	// +optional
+	MachineConfigPool string `json:"machineConfigPool,omitempty"`
+	// DaemonSet is the RTE DaemonSet serving this node group
+	// +optional
+	DaemonSet NamespacedName `json:"daemonset,omitempty"`
+	// DesiredPods is the number of nodes which should be running the RTE pod
+	DesiredPods int32 `json:"desiredPods"`
+	// ReadyPods is the number of nodes running a ready RTE pod
+	ReadyPods int32 `json:"readyPods"`
+	// UpdatedPods is the number of nodes running the updated RTE pod
+	UpdatedPods int32 `json:"updatedPods"`
+	// SelectedNodes is the number of nodes belonging to this node group
+	SelectedNodes int32 `json:"selectedNodes"`
+	// NodesWithTopology is the number of nodes belonging to this node group which have a NodeResourceTopology object
+	NodesWithTopology int32 `json:"nodesWithTopology"`
+	// Config is the effective configuration of this node group
+	// +optional
+	Config *NodeGroupConfig `json:"config,omitempty"`
+	// Conditions represents the latest available observations of the node group state
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
 //+genclient
 //+genclient:nonNamespaced
 //+kubebuilder:object:root=true
@@ -185,7 +289,7 @@ func init() {
 func (ngc *NodeGroupConfig) ToString() string {
 	if ngc != nil {
 		ngc.SetDefaults()
-		return fmt.Sprintf("PodsFingerprinting mode: %s InfoRefreshMode: %s InfoRefreshPeriod: %s InfoRefreshPause: %s", *ngc.PodsFingerprinting, *ngc.InfoRefreshMode, *ngc.InfoRefreshPeriod, *ngc.InfoRefreshPause)
+		return fmt.Sprintf("PodsFingerprinting mode: %s InfoRefreshMode: %s InfoRefreshPeriod: %s InfoRefreshPause: %s ResourceExcludes: %v PodExcludes: %v", *ngc.PodsFingerprinting, *ngc.InfoRefreshMode, *ngc.InfoRefreshPeriod, *ngc.InfoRefreshPause, ngc.ResourceExcludes, ngc.PodExcludes)
 	}
 	return ""
 }
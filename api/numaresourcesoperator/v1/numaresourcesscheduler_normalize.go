@@ -29,6 +29,11 @@ const (
 	defaultSchedulerInformer    = SchedulerInformerDedicated
 	defaultCacheResyncDetection = CacheResyncDetectionRelaxed
 	defaultScoringStrategy      = LeastAllocated
+	defaultReplicas             = int32(1)
+
+	defaultCacheDesyncRemediationMode        = CacheDesyncRemediationNone
+	defaultCacheDesyncRemediationThreshold   = 5 * time.Minute
+	defaultCacheDesyncRemediationMinInterval = 15 * time.Minute
 )
 
 func SetDefaults_NUMAResourcesSchedulerSpec(spec *NUMAResourcesSchedulerSpec) {
@@ -54,6 +59,52 @@ func SetDefaults_NUMAResourcesSchedulerSpec(spec *NUMAResourcesSchedulerSpec) {
 			Type: defaultScoringStrategy,
 		}
 	}
+	if spec.CacheDesyncRemediation == nil {
+		spec.CacheDesyncRemediation = &CacheDesyncRemediation{}
+	}
+	SetDefaults_CacheDesyncRemediation(spec.CacheDesyncRemediation)
+	if spec.Replicas == nil {
+		replicas := defaultReplicas
+		spec.Replicas = &replicas
+	}
+	for idx := range spec.Profiles {
+		SetDefaults_SchedulerProfile(&spec.Profiles[idx], spec)
+	}
+}
+
+// SetDefaults_SchedulerProfile sets the unset fields of the profile from the main profile, which must be already defaulted.
+func SetDefaults_SchedulerProfile(profile *SchedulerProfile, spec *NUMAResourcesSchedulerSpec) {
+	if profile.CacheResyncPeriod == nil {
+		profile.CacheResyncPeriod = spec.CacheResyncPeriod.DeepCopy()
+	}
+	if profile.SchedulerInformer == nil {
+		infMode := *spec.SchedulerInformer
+		profile.SchedulerInformer = &infMode
+	}
+	if profile.CacheResyncDetection == nil {
+		resyncDetection := *spec.CacheResyncDetection
+		profile.CacheResyncDetection = &resyncDetection
+	}
+	if profile.ScoringStrategy == nil {
+		profile.ScoringStrategy = spec.ScoringStrategy.DeepCopy()
+	}
+}
+
+func SetDefaults_CacheDesyncRemediation(rem *CacheDesyncRemediation) {
+	if rem.Mode == nil {
+		mode := defaultCacheDesyncRemediationMode
+		rem.Mode = &mode
+	}
+	if rem.Threshold == nil {
+		rem.Threshold = &metav1.Duration{
+			Duration: defaultCacheDesyncRemediationThreshold,
+		}
+	}
+	if rem.MinInterval == nil {
+		rem.MinInterval = &metav1.Duration{
+			Duration: defaultCacheDesyncRemediationMinInterval,
+		}
+	}
 }
 
 func (current NUMAResourcesSchedulerSpec) Normalize() NUMAResourcesSchedulerSpec {
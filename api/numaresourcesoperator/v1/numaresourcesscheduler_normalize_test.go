@@ -32,6 +32,13 @@ func TestNUMAResourcesSchedulerSpecNormalize(t *testing.T) {
 	cacheResyncDetection := defaultCacheResyncDetection
 	schedInformer := defaultSchedulerInformer
 	scoringStrategyType := defaultScoringStrategy
+	cacheDesyncRemediationMode := defaultCacheDesyncRemediationMode
+	replicas := defaultReplicas
+	cacheDesyncRemediation := CacheDesyncRemediation{
+		Mode:        &cacheDesyncRemediationMode,
+		Threshold:   &metav1.Duration{Duration: defaultCacheDesyncRemediationThreshold},
+		MinInterval: &metav1.Duration{Duration: defaultCacheDesyncRemediationMinInterval},
+	}
 
 	cacheResyncPeriodCustom := 42 * time.Second
 	cacheResyncDebugCustom := CacheResyncDebugDisabled
@@ -41,6 +48,8 @@ func TestNUMAResourcesSchedulerSpecNormalize(t *testing.T) {
 		Type:      MostAllocated,
 		Resources: []ResourceSpecParams{{Name: "cpu", Weight: 10}, {Name: "memory", Weight: 5}},
 	}
+	cacheDesyncRemediationModeCustom := CacheDesyncRemediationRestartPod
+	replicasCustom := int32(3)
 
 	type testCase struct {
 		description string
@@ -61,6 +70,8 @@ func TestNUMAResourcesSchedulerSpecNormalize(t *testing.T) {
 				ScoringStrategy: &ScoringStrategyParams{
 					Type: scoringStrategyType,
 				},
+				CacheDesyncRemediation: &cacheDesyncRemediation,
+				Replicas:               &replicas,
 			},
 		},
 		{
@@ -81,6 +92,8 @@ func TestNUMAResourcesSchedulerSpecNormalize(t *testing.T) {
 				ScoringStrategy: &ScoringStrategyParams{
 					Type: scoringStrategyType,
 				},
+				CacheDesyncRemediation: &cacheDesyncRemediation,
+				Replicas:               &replicas,
 			},
 		},
 		{
@@ -104,6 +117,8 @@ func TestNUMAResourcesSchedulerSpecNormalize(t *testing.T) {
 				ScoringStrategy: &ScoringStrategyParams{
 					Type: scoringStrategyType,
 				},
+				CacheDesyncRemediation: &cacheDesyncRemediation,
+				Replicas:               &replicas,
 			},
 		},
 		{
@@ -128,6 +143,8 @@ func TestNUMAResourcesSchedulerSpecNormalize(t *testing.T) {
 				ScoringStrategy: &ScoringStrategyParams{
 					Type: scoringStrategyType,
 				},
+				CacheDesyncRemediation: &cacheDesyncRemediation,
+				Replicas:               &replicas,
 			},
 		},
 		{
@@ -152,6 +169,8 @@ func TestNUMAResourcesSchedulerSpecNormalize(t *testing.T) {
 				ScoringStrategy: &ScoringStrategyParams{
 					Type: scoringStrategyType,
 				},
+				CacheDesyncRemediation: &cacheDesyncRemediation,
+				Replicas:               &replicas,
 			},
 		},
 
@@ -171,10 +190,12 @@ func TestNUMAResourcesSchedulerSpecNormalize(t *testing.T) {
 				CacheResyncPeriod: &metav1.Duration{
 					Duration: cacheResyncPeriodCustom,
 				},
-				CacheResyncDebug:     &cacheResyncDebug,
-				SchedulerInformer:    &schedInformer,
-				CacheResyncDetection: &cacheResyncDetection,
-				ScoringStrategy:      &scoringStrategyCustom,
+				CacheResyncDebug:       &cacheResyncDebug,
+				SchedulerInformer:      &schedInformer,
+				CacheResyncDetection:   &cacheResyncDetection,
+				ScoringStrategy:        &scoringStrategyCustom,
+				CacheDesyncRemediation: &cacheDesyncRemediation,
+				Replicas:               &replicas,
 			},
 		},
 
@@ -193,10 +214,12 @@ func TestNUMAResourcesSchedulerSpecNormalize(t *testing.T) {
 				CacheResyncPeriod: &metav1.Duration{
 					Duration: cacheResyncPeriodCustom,
 				},
-				CacheResyncDebug:     &cacheResyncDebugCustom,
-				SchedulerInformer:    &schedInformerCustom,
-				CacheResyncDetection: &cacheResyncDetectionCustom,
-				ScoringStrategy:      &scoringStrategyCustom,
+				CacheResyncDebug:       &cacheResyncDebugCustom,
+				SchedulerInformer:      &schedInformerCustom,
+				CacheResyncDetection:   &cacheResyncDetectionCustom,
+				ScoringStrategy:        &scoringStrategyCustom,
+				CacheDesyncRemediation: &cacheDesyncRemediation,
+				Replicas:               &replicas,
 			},
 		},
 		{
@@ -219,6 +242,8 @@ func TestNUMAResourcesSchedulerSpecNormalize(t *testing.T) {
 				ScoringStrategy: &ScoringStrategyParams{
 					Type: scoringStrategyType,
 				},
+				CacheDesyncRemediation: &cacheDesyncRemediation,
+				Replicas:               &replicas,
 			},
 		},
 		{
@@ -234,6 +259,7 @@ func TestNUMAResourcesSchedulerSpecNormalize(t *testing.T) {
 				SchedulerInformer:    &schedInformerCustom,
 				CacheResyncDetection: &cacheResyncDetectionCustom,
 				ScoringStrategy:      &scoringStrategyCustom,
+				Replicas:             &replicasCustom,
 			},
 			expected: NUMAResourcesSchedulerSpec{
 				SchedulerImage: "quay.io/openshift-kni/fake-image-for:test",
@@ -242,10 +268,90 @@ func TestNUMAResourcesSchedulerSpecNormalize(t *testing.T) {
 				CacheResyncPeriod: &metav1.Duration{
 					Duration: cacheResyncPeriodCustom,
 				},
-				CacheResyncDebug:     &cacheResyncDebugCustom,
+				CacheResyncDebug:       &cacheResyncDebugCustom,
+				SchedulerInformer:      &schedInformerCustom,
+				CacheResyncDetection:   &cacheResyncDetectionCustom,
+				ScoringStrategy:        &scoringStrategyCustom,
+				CacheDesyncRemediation: &cacheDesyncRemediation,
+				Replicas:               &replicasCustom,
+			},
+		},
+		{
+			description: "partially set cache desync remediation",
+			current: NUMAResourcesSchedulerSpec{
+				CacheDesyncRemediation: &CacheDesyncRemediation{
+					Mode: &cacheDesyncRemediationModeCustom,
+				},
+			},
+			expected: NUMAResourcesSchedulerSpec{
+				CacheResyncPeriod: &metav1.Duration{
+					Duration: cacheResyncPeriod,
+				},
+				CacheResyncDebug:     &cacheResyncDebug,
+				SchedulerInformer:    &schedInformer,
+				CacheResyncDetection: &cacheResyncDetection,
+				ScoringStrategy: &ScoringStrategyParams{
+					Type: scoringStrategyType,
+				},
+				CacheDesyncRemediation: &CacheDesyncRemediation{
+					Mode:        &cacheDesyncRemediationModeCustom,
+					Threshold:   cacheDesyncRemediation.Threshold,
+					MinInterval: cacheDesyncRemediation.MinInterval,
+				},
+				Replicas: &replicas,
+			},
+		},
+		{
+			description: "profiles inherit the main profile settings",
+			current: NUMAResourcesSchedulerSpec{
+				SchedulerInformer: &schedInformerCustom,
+				Profiles: []SchedulerProfile{
+					{
+						SchedulerName: "inherit-all",
+					},
+					{
+						SchedulerName: "custom",
+						CacheResyncPeriod: &metav1.Duration{
+							Duration: cacheResyncPeriodCustom,
+						},
+						ScoringStrategy: &scoringStrategyCustom,
+					},
+				},
+			},
+			expected: NUMAResourcesSchedulerSpec{
+				CacheResyncPeriod: &metav1.Duration{
+					Duration: cacheResyncPeriod,
+				},
+				CacheResyncDebug:     &cacheResyncDebug,
 				SchedulerInformer:    &schedInformerCustom,
-				CacheResyncDetection: &cacheResyncDetectionCustom,
-				ScoringStrategy:      &scoringStrategyCustom,
+				CacheResyncDetection: &cacheResyncDetection,
+				ScoringStrategy: &ScoringStrategyParams{
+					Type: scoringStrategyType,
+				},
+				CacheDesyncRemediation: &cacheDesyncRemediation,
+				Replicas:               &replicas,
+				Profiles: []SchedulerProfile{
+					{
+						SchedulerName: "inherit-all",
+						CacheResyncPeriod: &metav1.Duration{
+							Duration: cacheResyncPeriod,
+						},
+						SchedulerInformer:    &schedInformerCustom,
+						CacheResyncDetection: &cacheResyncDetection,
+						ScoringStrategy: &ScoringStrategyParams{
+							Type: scoringStrategyType,
+						},
+					},
+					{
+						SchedulerName: "custom",
+						CacheResyncPeriod: &metav1.Duration{
+							Duration: cacheResyncPeriodCustom,
+						},
+						SchedulerInformer:    &schedInformerCustom,
+						CacheResyncDetection: &cacheResyncDetection,
+						ScoringStrategy:      &scoringStrategyCustom,
+					},
+				},
 			},
 		},
 	}
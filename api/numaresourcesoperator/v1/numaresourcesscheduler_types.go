@@ -17,13 +17,14 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	configv1 "github.com/openshift/api/config/v1"
 	operatorv1 "github.com/openshift/api/operator/v1"
 )
 
-// +kubebuilder:validation:Enum=Disabled;DumpJSONFile
+// +kubebuilder:validation:Enum=Disabled;DumpJSONFile;ServeHTTP
 type CacheResyncDebugMode string
 
 const (
@@ -32,6 +33,12 @@ const (
 
 	// CacheResyncDumpJSONFile makes the scheduler cache dump its internal state as JSON at each failed resync. Default.
 	CacheResyncDebugDumpJSONFile CacheResyncDebugMode = "DumpJSONFile"
+
+	// CacheResyncDebugServeHTTP makes the scheduler cache dump its internal state like DumpJSONFile, and serves it over HTTP
+	// from a sidecar container. The state is meant to be fetched through the API server proxy, which authenticates and
+	// authorizes the clients, so reading it requires neither pods/exec nor any tool in the scheduler image.
+	// Supported only on OpenShift, whose service-ca provides the certificate of the sidecar.
+	CacheResyncDebugServeHTTP CacheResyncDebugMode = "ServeHTTP"
 )
 
 // +kubebuilder:validation:Enum=Shared;Dedicated
@@ -45,7 +52,7 @@ const (
 	SchedulerInformerDedicated SchedulerInformerMode = "Dedicated"
 )
 
-// +kubebuilder:validation:Enum=MostAllocated;BalancedAllocation;LeastAllocated
+// +kubebuilder:validation:Enum=MostAllocated;BalancedAllocation;LeastAllocated;RequestedToCapacityRatio
 type ScoringStrategyType string
 
 const (
@@ -55,8 +62,29 @@ const (
 	BalancedAllocation ScoringStrategyType = "BalancedAllocation"
 	// LeastAllocated strategy favors node with the most amount of available resource
 	LeastAllocated ScoringStrategyType = "LeastAllocated"
+	// RequestedToCapacityRatio strategy scores nodes using the configured function of the resource utilization
+	RequestedToCapacityRatio ScoringStrategyType = "RequestedToCapacityRatio"
 )
 
+// UtilizationShapePoint is a point of the function which maps the resource utilization to the node score.
+type UtilizationShapePoint struct {
+	// Utilization (x axis) in percentage. Valid values are 0 to 100.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Utilization int32 `json:"utilization"`
+	// Score (y axis) assigned to the given utilization. Valid values are 0 to 10.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	Score int32 `json:"score"`
+}
+
+type RequestedToCapacityRatioParams struct {
+	// Shape is the list of points defining the scoring function, sorted by increasing utilization.
+	// The score between the points is linearly interpolated.
+	// +kubebuilder:validation:MinItems=1
+	Shape []UtilizationShapePoint `json:"shape"`
+}
+
 type ResourceSpecParams struct {
 	// Name of the resource.
 	Name string `json:"name"`
@@ -67,6 +95,9 @@ type ResourceSpecParams struct {
 type ScoringStrategyParams struct {
 	Type      ScoringStrategyType  `json:"type,omitempty"`
 	Resources []ResourceSpecParams `json:"resources,omitempty"`
+	// RequestedToCapacityRatio sets the scoring function. Required if, and only if, Type is RequestedToCapacityRatio.
+	// +optional
+	RequestedToCapacityRatio *RequestedToCapacityRatioParams `json:"requestedToCapacityRatio,omitempty"`
 }
 
 // +kubebuilder:validation:Enum=Relaxed;Aggressive
@@ -80,6 +111,67 @@ const (
 	CacheResyncDetectionAggressive CacheResyncDetectionMode = "Aggressive"
 )
 
+// +kubebuilder:validation:Enum=None;RestartPod;ForceResync
+type CacheDesyncRemediationMode string
+
+const (
+	// CacheDesyncRemediationNone only reports the scheduler cache desync. Default.
+	CacheDesyncRemediationNone CacheDesyncRemediationMode = "None"
+
+	// CacheDesyncRemediationRestartPod deletes the scheduler replicas whose cache is desynced, so they restart with a fresh cache.
+	CacheDesyncRemediationRestartPod CacheDesyncRemediationMode = "RestartPod"
+
+	// CacheDesyncRemediationForceResync annotates the scheduler pod template, so all the replicas are rolled out
+	// with a fresh cache following the deployment strategy.
+	CacheDesyncRemediationForceResync CacheDesyncRemediationMode = "ForceResync"
+)
+
+// CacheDesyncRemediation defines how to remediate a scheduler cache desync which persists too long
+type CacheDesyncRemediation struct {
+	// Mode sets the remediation action. Defaults to None.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scheduler cache desync remediation action",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Mode *CacheDesyncRemediationMode `json:"mode,omitempty"`
+	// Threshold sets how long the cache desync must persist before being remediated. Defaults to 5 minutes.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scheduler cache desync remediation threshold",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Threshold *metav1.Duration `json:"threshold,omitempty"`
+	// MinInterval sets the minimum time between two remediation actions. Defaults to 15 minutes.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scheduler cache desync remediation minimum interval",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	MinInterval *metav1.Duration `json:"minInterval,omitempty"`
+}
+
+// CacheDesyncRemediationStatus reports the last remediation action taken
+type CacheDesyncRemediationStatus struct {
+	// Mode is the remediation action taken
+	Mode CacheDesyncRemediationMode `json:"mode"`
+	// Time is when the remediation action was taken
+	Time metav1.Time `json:"time"`
+	// Nodes are the nodes whose cache was desynced
+	// +optional
+	Nodes []string `json:"nodes,omitempty"`
+}
+
+// SchedulerProfile defines an additional scheduler profile, served by the same scheduler replicas.
+// The unset fields inherit the settings of the main profile.
+type SchedulerProfile struct {
+	// SchedulerName is the scheduler name to be used in pod templates to select this profile. Must be unique.
+	SchedulerName string `json:"schedulerName"`
+	// Set the cache resync period. Use explicit 0 to disable.
+	// +optional
+	CacheResyncPeriod *metav1.Duration `json:"cacheResyncPeriod,omitempty"`
+	// Set the informer type to be used by the scheduler to connect to the apiserver.
+	// +optional
+	SchedulerInformer *SchedulerInformerMode `json:"schedulerInformer,omitempty"`
+	// Set the cache resync detection mode.
+	// +optional
+	CacheResyncDetection *CacheResyncDetectionMode `json:"cacheResyncDetection,omitempty"`
+	// ScoringStrategy a scoring model that determine how the plugin will score the nodes.
+	// +optional
+	ScoringStrategy *ScoringStrategyParams `json:"scoringStrategy,omitempty"`
+}
+
 // NUMAResourcesSchedulerSpec defines the desired state of NUMAResourcesScheduler
 type NUMAResourcesSchedulerSpec struct {
 	// Scheduler container image URL
@@ -114,10 +206,80 @@ type NUMAResourcesSchedulerSpec struct {
 	// +optional
 	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scheduler scoring strategy setting",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
 	ScoringStrategy *ScoringStrategyParams `json:"scoringStrategy,omitempty"`
+	// CacheDesyncRemediation sets how to remediate a scheduler cache desync which persists too long.
+	// Requires CacheResyncDebug to be DumpJSONFile or ServeHTTP. Defaults to report only.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scheduler cache desync remediation setting"
+	CacheDesyncRemediation *CacheDesyncRemediation `json:"cacheDesyncRemediation,omitempty"`
+	// Replicas sets the number of scheduler replicas. When more than one, the replicas elect a leader
+	// and a PodDisruptionBudget keeps at least one of them running. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scheduler replicas",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:podCount"}
+	Replicas *int32 `json:"replicas,omitempty"`
+	// NodeSelector overrides the node selector of the scheduler replicas. If either NodeSelector or Affinity
+	// are set, they replace the default placement on the control plane nodes.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scheduler node selector",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations overrides the tolerations of the scheduler replicas. If not empty, the tolerations will be the one set here.
+	// Leave empty to make the system use the default tolerations.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scheduler tolerations",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity overrides the affinity of the scheduler replicas. If either NodeSelector or Affinity
+	// are set, they replace the default placement on the control plane nodes.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scheduler affinity",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// PriorityClassName sets the priority class of the scheduler replicas.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scheduler priority class name",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:text"}
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Resources overrides the compute resources of the scheduler container. Leave empty to use the default requests and limits.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scheduler resource requirements",xDescriptors={"urn:alm:descriptor:com.tectonic.ui:resourceRequirements"}
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Profiles defines additional scheduler profiles, each served with its own scheduler name. The top level
+	// settings define the main profile.
+	// +optional
+	// +listType=map
+	// +listMapKey=schedulerName
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Additional scheduler profiles"
+	Profiles []SchedulerProfile `json:"profiles,omitempty"`
+	// ConfigOverlayRef references a ConfigMap in the operator namespace whose "config.yaml" key holds a partial
+	// KubeSchedulerConfiguration, merged on top of the configuration generated by the operator. The overlay can
+	// tune the settings not exposed in this spec, but it cannot change the fields managed by the operator.
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Scheduler configuration overlay",xDescriptors={"urn:alm:descriptor:io.kubernetes:ConfigMap"}
+	ConfigOverlayRef *corev1.LocalObjectReference `json:"configOverlayRef,omitempty"`
+}
+
+// SchedulerDeploymentSettings reports the effective placement and resources of the scheduler replicas
+type SchedulerDeploymentSettings struct {
+	// NodeSelector of the scheduler replicas
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Tolerations of the scheduler replicas
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity of the scheduler replicas
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// PriorityClassName of the scheduler replicas
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// Resources of the scheduler container
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // NUMAResourcesSchedulerStatus defines the observed state of NUMAResourcesScheduler
 type NUMAResourcesSchedulerStatus struct {
+	// ObservedGeneration is the most recent generation of the spec observed by the operator
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Observed generation"
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// Deployment of the secondary scheduler, namespaced name
 	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Scheduler deployment"
 	Deployment NamespacedName `json:"deployment,omitempty"`
@@ -133,6 +295,41 @@ type NUMAResourcesSchedulerStatus struct {
 	// RelatedObjects list of objects of interest for this operator
 	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Related Objects"
 	RelatedObjects []configv1.ObjectReference `json:"relatedObjects,omitempty"`
+	// LastCacheDesyncRemediation reports the last action taken to remediate a scheduler cache desync
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Last scheduler cache desync remediation"
+	LastCacheDesyncRemediation *CacheDesyncRemediationStatus `json:"lastCacheDesyncRemediation,omitempty"`
+	// DeploymentSettings shows the effective placement and resources of the scheduler replicas
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Scheduler deployment settings"
+	DeploymentSettings *SchedulerDeploymentSettings `json:"deploymentSettings,omitempty"`
+	// Profiles shows the effective settings of all the scheduler profiles, starting with the main profile
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Scheduler profiles"
+	Profiles []SchedulerProfile `json:"profiles,omitempty"`
+	// Config shows the effective configuration, the spec with all the defaults applied
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Scheduler effective configuration"
+	Config *NUMAResourcesSchedulerSpec `json:"config,omitempty"`
+	// ConfigHash is the hash of the rendered scheduler configuration, annotated on the scheduler deployment
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Scheduler configuration hash"
+	ConfigHash string `json:"configHash,omitempty"`
+	// Replicas reports the observed state of each scheduler replica
+	// +optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status,displayName="Scheduler replicas observed state"
+	Replicas []SchedulerReplicaStatus `json:"replicas,omitempty"`
+}
+
+// SchedulerReplicaStatus reports the observed state of a scheduler replica
+type SchedulerReplicaStatus struct {
+	// Name of the replica pod
+	Name string `json:"name"`
+	// NodeName is the node running the replica, if scheduled
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+	// Ready is true if the replica is ready to schedule pods
+	Ready bool `json:"ready"`
 }
 
 //+genclient
@@ -28,6 +28,57 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheDesyncRemediation) DeepCopyInto(out *CacheDesyncRemediation) {
+	*out = *in
+	if in.Mode != nil {
+		in, out := &in.Mode, &out.Mode
+		*out = new(CacheDesyncRemediationMode)
+		**out = **in
+	}
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MinInterval != nil {
+		in, out := &in.MinInterval, &out.MinInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheDesyncRemediation.
+func (in *CacheDesyncRemediation) DeepCopy() *CacheDesyncRemediation {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheDesyncRemediation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CacheDesyncRemediationStatus) DeepCopyInto(out *CacheDesyncRemediationStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CacheDesyncRemediationStatus.
+func (in *CacheDesyncRemediationStatus) DeepCopy() *CacheDesyncRemediationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CacheDesyncRemediationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineConfigPool) DeepCopyInto(out *MachineConfigPool) {
 	*out = *in
@@ -156,6 +207,13 @@ func (in *NUMAResourcesOperatorStatus) DeepCopyInto(out *NUMAResourcesOperatorSt
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.NodeGroups != nil {
+		in, out := &in.NodeGroups, &out.NodeGroups
+		*out = make([]NodeGroupStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -267,6 +325,52 @@ func (in *NUMAResourcesSchedulerSpec) DeepCopyInto(out *NUMAResourcesSchedulerSp
 		*out = new(ScoringStrategyParams)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CacheDesyncRemediation != nil {
+		in, out := &in.CacheDesyncRemediation, &out.CacheDesyncRemediation
+		*out = new(CacheDesyncRemediation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]SchedulerProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ConfigOverlayRef != nil {
+		in, out := &in.ConfigOverlayRef, &out.ConfigOverlayRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NUMAResourcesSchedulerSpec.
@@ -300,6 +404,33 @@ func (in *NUMAResourcesSchedulerStatus) DeepCopyInto(out *NUMAResourcesScheduler
 		*out = make([]configv1.ObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.LastCacheDesyncRemediation != nil {
+		in, out := &in.LastCacheDesyncRemediation, &out.LastCacheDesyncRemediation
+		*out = new(CacheDesyncRemediationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeploymentSettings != nil {
+		in, out := &in.DeploymentSettings, &out.DeploymentSettings
+		*out = new(SchedulerDeploymentSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]SchedulerProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(NUMAResourcesSchedulerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = make([]SchedulerReplicaStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NUMAResourcesSchedulerStatus.
@@ -335,6 +466,11 @@ func (in *NodeGroup) DeepCopyInto(out *NodeGroup) {
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = new(NodeGroupConfig)
@@ -382,6 +518,27 @@ func (in *NodeGroupConfig) DeepCopyInto(out *NodeGroupConfig) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ResourceExcludes != nil {
+		in, out := &in.ResourceExcludes, &out.ResourceExcludes
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.PodExcludes != nil {
+		in, out := &in.PodExcludes, &out.PodExcludes
+		*out = make([]NamespacedName, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupConfig.
@@ -394,6 +551,54 @@ func (in *NodeGroupConfig) DeepCopy() *NodeGroupConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeGroupStatus) DeepCopyInto(out *NodeGroupStatus) {
+	*out = *in
+	out.DaemonSet = in.DaemonSet
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = new(NodeGroupConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeGroupStatus.
+func (in *NodeGroupStatus) DeepCopy() *NodeGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestedToCapacityRatioParams) DeepCopyInto(out *RequestedToCapacityRatioParams) {
+	*out = *in
+	if in.Shape != nil {
+		in, out := &in.Shape, &out.Shape
+		*out = make([]UtilizationShapePoint, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestedToCapacityRatioParams.
+func (in *RequestedToCapacityRatioParams) DeepCopy() *RequestedToCapacityRatioParams {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestedToCapacityRatioParams)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSpecParams) DeepCopyInto(out *ResourceSpecParams) {
 	*out = *in
@@ -409,6 +614,91 @@ func (in *ResourceSpecParams) DeepCopy() *ResourceSpecParams {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerDeploymentSettings) DeepCopyInto(out *SchedulerDeploymentSettings) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerDeploymentSettings.
+func (in *SchedulerDeploymentSettings) DeepCopy() *SchedulerDeploymentSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerDeploymentSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerProfile) DeepCopyInto(out *SchedulerProfile) {
+	*out = *in
+	if in.CacheResyncPeriod != nil {
+		in, out := &in.CacheResyncPeriod, &out.CacheResyncPeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.SchedulerInformer != nil {
+		in, out := &in.SchedulerInformer, &out.SchedulerInformer
+		*out = new(SchedulerInformerMode)
+		**out = **in
+	}
+	if in.CacheResyncDetection != nil {
+		in, out := &in.CacheResyncDetection, &out.CacheResyncDetection
+		*out = new(CacheResyncDetectionMode)
+		**out = **in
+	}
+	if in.ScoringStrategy != nil {
+		in, out := &in.ScoringStrategy, &out.ScoringStrategy
+		*out = new(ScoringStrategyParams)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerProfile.
+func (in *SchedulerProfile) DeepCopy() *SchedulerProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerReplicaStatus) DeepCopyInto(out *SchedulerReplicaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerReplicaStatus.
+func (in *SchedulerReplicaStatus) DeepCopy() *SchedulerReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScoringStrategyParams) DeepCopyInto(out *ScoringStrategyParams) {
 	*out = *in
@@ -417,6 +707,11 @@ func (in *ScoringStrategyParams) DeepCopyInto(out *ScoringStrategyParams) {
 		*out = make([]ResourceSpecParams, len(*in))
 		copy(*out, *in)
 	}
+	if in.RequestedToCapacityRatio != nil {
+		in, out := &in.RequestedToCapacityRatio, &out.RequestedToCapacityRatio
+		*out = new(RequestedToCapacityRatioParams)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScoringStrategyParams.
@@ -428,3 +723,18 @@ func (in *ScoringStrategyParams) DeepCopy() *ScoringStrategyParams {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UtilizationShapePoint) DeepCopyInto(out *UtilizationShapePoint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UtilizationShapePoint.
+func (in *UtilizationShapePoint) DeepCopy() *UtilizationShapePoint {
+	if in == nil {
+		return nil
+	}
+	out := new(UtilizationShapePoint)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -107,11 +107,18 @@ func main() {
 		os.Exit(1)
 	}
 
+	statusCli, err := newStatusClient()
+	if err != nil {
+		// not fatal: the scheduler cache state is read using exec instead
+		klog.V(2).InfoS("cannot fetch the scheduler cache state over HTTP", "error", err)
+	}
+
 	env := schedcache.Env{
-		Ctx:    context.Background(),
-		Cli:    cli,
-		K8sCli: k8sCli,
-		Log:    textlogger.NewLogger(logCfg),
+		Ctx:          context.Background(),
+		Cli:          cli,
+		K8sCli:       k8sCli,
+		StatusClient: statusCli,
+		Log:          textlogger.NewLogger(logCfg),
 	}
 
 	var nodeNames []string
@@ -253,6 +260,14 @@ func NewClientWithScheme(scheme *k8sruntime.Scheme) (client.Client, error) {
 	return client.New(cfg, client.Options{Scheme: scheme})
 }
 
+func newStatusClient() (*schedcache.StatusClient, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	return schedcache.NewStatusClient(cfg)
+}
+
 // getKlogLevel reconstructs the klog verb level, because
 // the klog package doesn't give a clean easy way to access
 // the setting, so we have to jumps through some hoops.
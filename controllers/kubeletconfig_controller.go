@@ -23,6 +23,8 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -33,7 +35,9 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 
@@ -41,10 +45,12 @@ import (
 
 	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
 	"github.com/openshift-kni/numaresources-operator/internal/machineconfigpools"
+	"github.com/openshift-kni/numaresources-operator/internal/metrics"
 	"github.com/openshift-kni/numaresources-operator/pkg/apply"
 	"github.com/openshift-kni/numaresources-operator/pkg/kubeletconfig"
 	"github.com/openshift-kni/numaresources-operator/pkg/objectnames"
 	cfgstate "github.com/openshift-kni/numaresources-operator/pkg/objectstate/cfg"
+	"github.com/openshift-kni/numaresources-operator/pkg/status"
 	rteconfig "github.com/openshift-kni/numaresources-operator/rte/pkg/config"
 )
 
@@ -91,17 +97,21 @@ func (r *KubeletConfigReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if err != nil {
 		var klErr *InvalidKubeletConfig
 		if errors.As(err, &klErr) {
-			r.Recorder.Event(instance, "Normal", "ProcessSkip", "ignored kubelet config "+klErr.ObjectName)
+			r.Recorder.Event(instance, corev1.EventTypeNormal, status.ReasonKubeletConfigSkipped, "ignored kubelet config "+klErr.ObjectName)
+			metrics.UpdateReconcileMetric(metrics.ControllerKubeletConfig, status.ConditionInfo{Type: status.ConditionAvailable, Reason: status.ReasonKubeletConfigSkipped})
 			return ctrl.Result{}, nil
 		}
 
 		klog.ErrorS(err, "failed to reconcile configmap", "controller", "kubeletconfig")
 
-		r.Recorder.Event(instance, "Warning", "ProcessFailed", "Failed to update RTE config from kubelet config "+req.NamespacedName.String())
+		r.Recorder.Event(instance, corev1.EventTypeWarning, status.ReasonKubeletConfigProcessFailed, "Failed to update RTE config from kubelet config "+req.NamespacedName.String())
+		metrics.UpdateKubeletConfigSyncFailuresMetric(req.Name)
+		metrics.UpdateReconcileMetric(metrics.ControllerKubeletConfig, status.DegradedFromError(err, status.ReasonKubeletConfigProcessFailed))
 		return ctrl.Result{}, err
 	}
 
-	r.Recorder.Event(instance, "Normal", "ProcessOK", fmt.Sprintf("Updated RTE config %s/%s from kubelet config %s", cm.Namespace, cm.Name, req.NamespacedName.String()))
+	metrics.UpdateReconcileMetric(metrics.ControllerKubeletConfig, status.ConditionInfo{Type: status.ConditionAvailable, Reason: status.ReasonKubeletConfigProcessed})
+	r.Recorder.Event(instance, corev1.EventTypeNormal, status.ReasonKubeletConfigProcessed, fmt.Sprintf("Updated RTE config %s/%s from kubelet config %s", cm.Namespace, cm.Name, req.NamespacedName.String()))
 	return ctrl.Result{}, nil
 }
 
@@ -118,9 +128,43 @@ func (r *KubeletConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&mcov1.KubeletConfig{}, builder.WithPredicates(p)).
 		Owns(&corev1.ConfigMap{}).
+		// the rendered configmaps include the pod and resource excludes set in the NUMAResourcesOperator spec
+		Watches(&nropv1.NUMAResourcesOperator{},
+			handler.EnqueueRequestsFromMapFunc(r.numaResourcesOperatorToKubeletConfigs),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
 
+// numaResourcesOperatorToKubeletConfigs returns the KubeletConfigs targeting the MachineConfigPools of the NUMAResourcesOperator node groups
+func (r *KubeletConfigReconciler) numaResourcesOperatorToKubeletConfigs(ctx context.Context, obj client.Object) []reconcile.Request {
+	instance, ok := obj.(*nropv1.NUMAResourcesOperator)
+	if !ok || instance.Name != objectnames.DefaultNUMAResourcesOperatorCrName {
+		return nil
+	}
+
+	mcps, err := machineconfigpools.GetListByNodeGroupsV1(ctx, r.Client, instance.Spec.NodeGroups)
+	if err != nil {
+		klog.V(2).InfoS("cannot find the machine config pools of the node groups", "error", err)
+		return nil
+	}
+
+	mcoKcs := &mcov1.KubeletConfigList{}
+	if err := r.List(ctx, mcoKcs); err != nil {
+		klog.V(2).InfoS("cannot list the KubeletConfigs", "error", err)
+		return nil
+	}
+
+	var reqs []reconcile.Request
+	for idx := range mcoKcs.Items {
+		mcoKc := &mcoKcs.Items[idx]
+		if _, err := machineconfigpools.FindBySelector(mcps, mcoKc.Spec.MachineConfigPoolSelector); err != nil {
+			continue
+		}
+		reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(mcoKc)})
+	}
+	return reqs
+}
+
 type InvalidKubeletConfig struct {
 	ObjectName string
 	Err        error
@@ -172,17 +216,21 @@ func (r *KubeletConfigReconciler) reconcileConfigMap(ctx context.Context, instan
 		return nil, err
 	}
 
-	return r.syncConfigMap(ctx, mcoKc, kubeletConfig, instance, mcp.Name)
+	return r.syncConfigMap(ctx, mcoKc, kubeletConfig, instance, mcp)
 }
 
-func (r *KubeletConfigReconciler) syncConfigMap(ctx context.Context, mcoKc *mcov1.KubeletConfig, kubeletConfig *kubeletconfigv1beta1.KubeletConfiguration, instance *nropv1.NUMAResourcesOperator, mcpName string) (*corev1.ConfigMap, error) {
+func (r *KubeletConfigReconciler) syncConfigMap(ctx context.Context, mcoKc *mcov1.KubeletConfig, kubeletConfig *kubeletconfigv1beta1.KubeletConfiguration, instance *nropv1.NUMAResourcesOperator, mcp *mcov1.MachineConfigPool) (*corev1.ConfigMap, error) {
+	mcpName := mcp.Name
 	generatedName := objectnames.GetComponentName(instance.Name, mcpName)
 	klog.V(3).InfoS("generated configMap name", "generatedName", generatedName)
 
-	podExcludes := podExcludesListToMap(instance.Spec.PodExcludes)
+	ngConf := nodeGroupConfigForMachineConfigPool(instance.Spec.NodeGroups, mcp)
+	klog.V(5).InfoS("using resourceExcludes", "resourceExcludes", ngConf.ResourceExcludes)
+
+	podExcludes := podExcludesList(instance.Spec.PodExcludes, ngConf.PodExcludes)
 	klog.V(5).InfoS("using podExcludes", "podExcludes", podExcludes)
 
-	data, err := rteconfig.Render(kubeletConfig, podExcludes)
+	data, err := rteconfig.Render(kubeletConfig, podExcludes, ngConf.ResourceExcludes)
 	if err != nil {
 		klog.ErrorS(err, "rendering config", "namespace", r.Namespace, "name", generatedName)
 		return nil, err
@@ -206,10 +254,40 @@ func (r *KubeletConfigReconciler) syncConfigMap(ctx context.Context, mcoKc *mcov
 	return rendered, nil
 }
 
-func podExcludesListToMap(podExcludes []nropv1.NamespacedName) map[string]string {
-	ret := make(map[string]string)
-	for _, pe := range podExcludes {
-		ret[pe.Namespace] = pe.Name
+// podExcludesList merges the global pod excludes with the node group ones, preserving
+// the order and skipping the duplicates
+func podExcludesList(global, nodeGroup []nropv1.NamespacedName) rteconfig.PodExcludeList {
+	ret := rteconfig.PodExcludeList{}
+	seen := make(map[nropv1.NamespacedName]struct{})
+	for _, podExcludes := range [][]nropv1.NamespacedName{global, nodeGroup} {
+		for _, pe := range podExcludes {
+			if _, ok := seen[pe]; ok {
+				continue
+			}
+			seen[pe] = struct{}{}
+			ret = append(ret, rteconfig.PodExclude{
+				NamespacePattern: pe.Namespace,
+				NamePattern:      pe.Name,
+			})
+		}
 	}
 	return ret
 }
+
+// nodeGroupConfigForMachineConfigPool returns the normalized config of the first node group selecting the given MCP
+func nodeGroupConfigForMachineConfigPool(nodeGroups []nropv1.NodeGroup, mcp *mcov1.MachineConfigPool) nropv1.NodeGroupConfig {
+	for _, nodeGroup := range nodeGroups {
+		if nodeGroup.MachineConfigPoolSelector == nil {
+			continue
+		}
+		sel, err := metav1.LabelSelectorAsSelector(nodeGroup.MachineConfigPoolSelector)
+		if err != nil {
+			klog.V(2).InfoS("bad node group machine config pool selector", "error", err)
+			continue
+		}
+		if sel.Matches(labels.Set(mcp.Labels)) {
+			return nodeGroup.NormalizeConfig()
+		}
+	}
+	return nropv1.DefaultNodeGroupConfig()
+}
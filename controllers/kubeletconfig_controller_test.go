@@ -116,6 +116,150 @@ var _ = Describe("Test KubeletConfig Reconcile", func() {
 				Expect(cm.Labels).To(HaveKeyWithValue(rteconfig.LabelOperatorName, nro.Name))
 				Expect(cm.Labels).To(HaveKeyWithValue(rteconfig.LabelNodeGroupName+"/"+rteconfig.LabelNodeGroupKindMachineConfigPool, mcp1.Name))
 			})
+			It("with NRO present, the created configmap should have the resource excludes of the node group", func() {
+				nro.Spec.NodeGroups[0].Config = &nropv1.NodeGroupConfig{
+					ResourceExcludes: map[string][]string{
+						"*": {"memory", "hugepages-1Gi"},
+					},
+				}
+				reconciler, err := NewFakeKubeletConfigReconciler(nro, mcp1, mcoKc1)
+				Expect(err).ToNot(HaveOccurred())
+
+				key := client.ObjectKeyFromObject(mcoKc1)
+				_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+				Expect(err).ToNot(HaveOccurred())
+
+				cm := &corev1.ConfigMap{}
+				key = client.ObjectKey{
+					Namespace: testNamespace,
+					Name:      objectnames.GetComponentName(nro.Name, mcp1.Name),
+				}
+				Expect(reconciler.Client.Get(context.TODO(), key, cm)).ToNot(HaveOccurred())
+				conf, err := rteconfig.Unrender(cm.Data[rteconfig.Key])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(conf.ExcludeList).To(Equal(map[string][]string{
+					"*": {"hugepages-1Gi", "memory"},
+				}))
+			})
+			It("should enqueue the KubeletConfigs of the NRO machine config pools", func() {
+				label2 := map[string]string{"test2": "test2"}
+				mcoKc2 := testobjs.NewKubeletConfig("test2", label2, &metav1.LabelSelector{MatchLabels: label2}, &kubeletconfigv1beta1.KubeletConfiguration{})
+				reconciler, err := NewFakeKubeletConfigReconciler(nro, mcp1, mcoKc1, mcoKc2)
+				Expect(err).ToNot(HaveOccurred())
+
+				reqs := reconciler.numaResourcesOperatorToKubeletConfigs(context.TODO(), nro)
+				Expect(reqs).To(Equal([]reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(mcoKc1)}}))
+			})
+			It("should update the configmap when the NRO resource excludes change", func() {
+				reconciler, err := NewFakeKubeletConfigReconciler(nro, mcp1, mcoKc1)
+				Expect(err).ToNot(HaveOccurred())
+
+				key := client.ObjectKeyFromObject(mcoKc1)
+				_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+				Expect(err).ToNot(HaveOccurred())
+
+				updatedNRO := &nropv1.NUMAResourcesOperator{}
+				Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(nro), updatedNRO)).To(Succeed())
+				updatedNRO.Spec.NodeGroups[0].Config = &nropv1.NodeGroupConfig{
+					ResourceExcludes: map[string][]string{
+						"*": {"memory"},
+					},
+				}
+				Expect(reconciler.Client.Update(context.TODO(), updatedNRO)).To(Succeed())
+
+				reqs := reconciler.numaResourcesOperatorToKubeletConfigs(context.TODO(), updatedNRO)
+				Expect(reqs).To(HaveLen(1))
+				_, err = reconciler.Reconcile(context.TODO(), reqs[0])
+				Expect(err).ToNot(HaveOccurred())
+
+				cm := &corev1.ConfigMap{}
+				key = client.ObjectKey{
+					Namespace: testNamespace,
+					Name:      objectnames.GetComponentName(nro.Name, mcp1.Name),
+				}
+				Expect(reconciler.Client.Get(context.TODO(), key, cm)).ToNot(HaveOccurred())
+				conf, err := rteconfig.Unrender(cm.Data[rteconfig.Key])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(conf.ExcludeList).To(Equal(map[string][]string{
+					"*": {"memory"},
+				}))
+			})
+			It("with NRO present, the created configmap should have the global and the node group pod excludes", func() {
+				nro.Spec.PodExcludes = []nropv1.NamespacedName{
+					{Namespace: "openshift-*", Name: "foo-*"},
+					{Namespace: "openshift-*", Name: "bar-*"},
+				}
+				nro.Spec.NodeGroups[0].Config = &nropv1.NodeGroupConfig{
+					PodExcludes: []nropv1.NamespacedName{
+						{Namespace: "infra", Name: "*"},
+						{Namespace: "openshift-*", Name: "foo-*"},
+					},
+				}
+				reconciler, err := NewFakeKubeletConfigReconciler(nro, mcp1, mcoKc1)
+				Expect(err).ToNot(HaveOccurred())
+
+				key := client.ObjectKeyFromObject(mcoKc1)
+				_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+				Expect(err).ToNot(HaveOccurred())
+
+				cm := &corev1.ConfigMap{}
+				key = client.ObjectKey{
+					Namespace: testNamespace,
+					Name:      objectnames.GetComponentName(nro.Name, mcp1.Name),
+				}
+				Expect(reconciler.Client.Get(context.TODO(), key, cm)).ToNot(HaveOccurred())
+				conf, err := rteconfig.Unrender(cm.Data[rteconfig.Key])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(conf.PodExcludeList).To(Equal(rteconfig.PodExcludeList{
+					{NamespacePattern: "openshift-*", NamePattern: "foo-*"},
+					{NamespacePattern: "openshift-*", NamePattern: "bar-*"},
+					{NamespacePattern: "infra", NamePattern: "*"},
+				}))
+				// the legacy map can't hold two name patterns for the same namespace pattern
+				Expect(conf.PodExcludes).To(BeNil())
+			})
+			It("should update the configmap when the NRO pod excludes change", func() {
+				reconciler, err := NewFakeKubeletConfigReconciler(nro, mcp1, mcoKc1)
+				Expect(err).ToNot(HaveOccurred())
+
+				key := client.ObjectKeyFromObject(mcoKc1)
+				_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+				Expect(err).ToNot(HaveOccurred())
+
+				updatedNRO := &nropv1.NUMAResourcesOperator{}
+				Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(nro), updatedNRO)).To(Succeed())
+				updatedNRO.Spec.PodExcludes = []nropv1.NamespacedName{
+					{Namespace: "openshift-*", Name: "foo-*"},
+				}
+				updatedNRO.Spec.NodeGroups[0].Config = &nropv1.NodeGroupConfig{
+					PodExcludes: []nropv1.NamespacedName{
+						{Namespace: "infra", Name: "*"},
+					},
+				}
+				Expect(reconciler.Client.Update(context.TODO(), updatedNRO)).To(Succeed())
+
+				reqs := reconciler.numaResourcesOperatorToKubeletConfigs(context.TODO(), updatedNRO)
+				Expect(reqs).To(HaveLen(1))
+				_, err = reconciler.Reconcile(context.TODO(), reqs[0])
+				Expect(err).ToNot(HaveOccurred())
+
+				cm := &corev1.ConfigMap{}
+				key = client.ObjectKey{
+					Namespace: testNamespace,
+					Name:      objectnames.GetComponentName(nro.Name, mcp1.Name),
+				}
+				Expect(reconciler.Client.Get(context.TODO(), key, cm)).ToNot(HaveOccurred())
+				conf, err := rteconfig.Unrender(cm.Data[rteconfig.Key])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(conf.PodExcludeList).To(Equal(rteconfig.PodExcludeList{
+					{NamespacePattern: "openshift-*", NamePattern: "foo-*"},
+					{NamespacePattern: "infra", NamePattern: "*"},
+				}))
+				Expect(conf.PodExcludes).To(Equal(map[string]string{
+					"openshift-*": "foo-*",
+					"infra":       "*",
+				}))
+			})
 			It("should send events when NRO present and operation succesfull", func() {
 				reconciler, err := NewFakeKubeletConfigReconciler(nro, mcp1, mcoKc1)
 				Expect(err).ToNot(HaveOccurred())
@@ -0,0 +1,319 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/k8stopologyawareschedwg/deployer/pkg/deployer/platform"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	nodegroupv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1/helper/nodegroup"
+	"github.com/openshift-kni/numaresources-operator/pkg/apply"
+	"github.com/openshift-kni/numaresources-operator/pkg/kubeletconfig"
+	"github.com/openshift-kni/numaresources-operator/pkg/objectnames"
+	cfgstate "github.com/openshift-kni/numaresources-operator/pkg/objectstate/cfg"
+	"github.com/openshift-kni/numaresources-operator/pkg/status"
+	rteconfig "github.com/openshift-kni/numaresources-operator/rte/pkg/config"
+)
+
+const (
+	// the kubelet configuration can change without any object change we can watch
+	// (e.g. manual edits on the node followed by a kubelet restart), so we resync periodically
+	kubeletConfigzResyncPeriod = 5 * time.Minute
+	// the nodes are reached through the API server proxy, and an unreachable kubelet must not stall the reconcile
+	kubeletConfigzFetchTimeout         = 10 * time.Second
+	kubeletConfigzMaxConcurrentFetches = 8
+
+	kindKubeletConfig = "KubeletConfig"
+)
+
+// KubeletConfigzReconciler keeps the RTE configuration in sync with the
+// effective kubelet configuration reported by the nodes through /configz
+type KubeletConfigzReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	Namespace string
+	Platform  platform.Platform
+	// RESTClient is the core/v1 REST client, used to reach the nodes through the API server proxy
+	RESTClient rest.Interface
+}
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=*
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=nodes/proxy,verbs=get
+
+func (r *KubeletConfigzReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	klog.V(3).InfoS("Starting KubeletConfigz reconcile loop", "object", req.NamespacedName)
+	defer klog.V(3).InfoS("Finish KubeletConfigz reconcile loop", "object", req.NamespacedName)
+
+	if req.Name != objectnames.DefaultNUMAResourcesOperatorCrName {
+		// the NUMAResourcesOperator reconciler takes care of reporting the error
+		return ctrl.Result{}, nil
+	}
+
+	instance := &nropv1.NUMAResourcesOperator{}
+	err := r.Get(ctx, req.NamespacedName, instance)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// nothing to sync, the ConfigMaps are garbage collected with their owner
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	trees, err := getTreesByNodeGroup(ctx, r.Client, r.Platform, instance.Spec.NodeGroups)
+	if err != nil {
+		// the NUMAResourcesOperator reconciler takes care of reporting the error
+		klog.V(2).InfoS("cannot resolve node groups", "error", err)
+		return ctrl.Result{RequeueAfter: kubeletConfigzResyncPeriod}, nil
+	}
+
+	var errs []error
+	for _, tree := range trees {
+		for _, pool := range tree.Pools() {
+			cm, updated, err := r.reconcileConfigMap(ctx, instance, tree, pool)
+			if err != nil {
+				klog.ErrorS(err, "failed to reconcile configmap", "controller", "kubeletconfigz", "pool", pool.Name)
+				r.Recorder.Event(instance, corev1.EventTypeWarning, status.ReasonKubeletConfigProcessFailed, "Failed to update RTE config from kubelet configz for node group "+pool.Name)
+				errs = append(errs, err)
+				continue
+			}
+			if !updated {
+				continue
+			}
+			// kubelet configuration changes are expected to be sporadic, yet are important enough
+			// to be made visible at kubernetes level. So we generate events to handle them
+			r.Recorder.Event(instance, corev1.EventTypeNormal, status.ReasonKubeletConfigProcessed, fmt.Sprintf("Updated RTE config %s/%s from kubelet configz for node group %s", cm.Namespace, cm.Name, pool.Name))
+		}
+	}
+	if len(errs) > 0 {
+		return ctrl.Result{}, errs[0]
+	}
+	return ctrl.Result{RequeueAfter: kubeletConfigzResyncPeriod}, nil
+}
+
+func (r *KubeletConfigzReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// the kubelet configuration of a node can change only across restarts, and we
+	// want to notice nodes joining or leaving the node groups. We don't care about status updates.
+	nodePredicates := predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return true
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !labels.Equals(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels())
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("kubeletconfigz").
+		For(&nropv1.NUMAResourcesOperator{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&corev1.ConfigMap{}).
+		Watches(&corev1.Node{},
+			handler.EnqueueRequestsFromMapFunc(nodeToNUMAResourcesOperator),
+			builder.WithPredicates(nodePredicates)).
+		Complete(r)
+}
+
+func nodeToNUMAResourcesOperator(ctx context.Context, obj client.Object) []reconcile.Request {
+	return []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name: objectnames.DefaultNUMAResourcesOperatorCrName,
+			},
+		},
+	}
+}
+
+func (r *KubeletConfigzReconciler) reconcileConfigMap(ctx context.Context, instance *nropv1.NUMAResourcesOperator, tree nodegroupv1.Tree, pool nodegroupv1.Pool) (*corev1.ConfigMap, bool, error) {
+	generatedName := objectnames.GetComponentName(instance.Name, pool.Name)
+
+	existing := cfgstate.FromClient(ctx, r.Client, r.Namespace, generatedName)
+	if existing.Existing.Config != nil {
+		// MCO KubeletConfig objects are authoritative: the KubeletConfig reconciler owns the configmap.
+		if owner := metav1.GetControllerOf(existing.Existing.Config); owner != nil && owner.Kind == kindKubeletConfig {
+			klog.V(4).InfoS("RTE config managed by KubeletConfig, skipped", "configmap", generatedName, "kubeletconfig", owner.Name)
+			return existing.Existing.Config, false, nil
+		}
+	}
+
+	poolConf, err := r.fetchKubeletConfig(ctx, pool)
+	if err != nil {
+		return nil, false, err
+	}
+	if poolConf == nil {
+		klog.V(3).InfoS("no nodes in node group, skipped", "pool", pool.Name)
+		return nil, false, nil
+	}
+	if len(poolConf.unreachableNodes) > 0 {
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, status.ReasonKubeletConfigzUnreachable, "Nodes %s of node group %s did not report their kubelet configuration, skipped", strings.Join(poolConf.unreachableNodes, ","), pool.Name)
+	}
+	if len(poolConf.mismatchingNodes) > 0 {
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, status.ReasonKubeletConfigMismatch, "Nodes %s of node group %s report a topology manager configuration different from node %s, which is used for the RTE config", strings.Join(poolConf.mismatchingNodes, ","), pool.Name, poolConf.nodeName)
+	}
+	kubeletConfig := poolConf.config
+
+	var ngConf nropv1.NodeGroupConfig
+	if tree.NodeGroup != nil {
+		ngConf = tree.NodeGroup.NormalizeConfig()
+	}
+	klog.V(5).InfoS("using resourceExcludes", "resourceExcludes", ngConf.ResourceExcludes)
+
+	podExcludes := podExcludesList(instance.Spec.PodExcludes, ngConf.PodExcludes)
+	klog.V(5).InfoS("using podExcludes", "podExcludes", podExcludes)
+
+	data, err := rteconfig.Render(kubeletConfig, podExcludes, ngConf.ResourceExcludes)
+	if err != nil {
+		klog.ErrorS(err, "rendering config", "namespace", r.Namespace, "name", generatedName)
+		return nil, false, err
+	}
+
+	rendered := rteconfig.CreateConfigMap(r.Namespace, generatedName, data)
+	if pool.MachineConfigPool != nil {
+		rendered = rteconfig.AddSoftRefLabels(rendered, instance.Name, pool.Name)
+	} else {
+		rendered = rteconfig.AddNodeGroupSoftRefLabels(rendered, instance.Name, pool.Name)
+	}
+
+	updated := false
+	for _, objState := range existing.State(cfgstate.Manifests{Config: rendered}) {
+		if err := controllerutil.SetControllerReference(instance, objState.Desired, r.Scheme); err != nil {
+			return nil, false, errors.Wrapf(err, "Failed to set controller reference to %s %s", objState.Desired.GetNamespace(), objState.Desired.GetName())
+		}
+		_, ok, err := apply.ApplyObject(ctx, r.Client, objState)
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "could not create %s", objState.Desired.GetObjectKind().GroupVersionKind().String())
+		}
+		updated = updated || ok
+	}
+	return rendered, updated, nil
+}
+
+// poolKubeletConfig is the kubelet configuration of a pool, as reported by its nodes
+type poolKubeletConfig struct {
+	// nodeName is the node whose configuration is used, the first one by name which reports it
+	nodeName string
+	config   *kubeletconfigv1beta1.KubeletConfiguration
+	// mismatchingNodes are the nodes whose topology manager configuration differs from the one of nodeName
+	mismatchingNodes []string
+	// unreachableNodes are the nodes which failed to report their configuration in time
+	unreachableNodes []string
+}
+
+// fetchKubeletConfig returns the kubelet configuration of the pool, fetched from all its nodes.
+// Nodes belonging to the same pool are expected to share the same configuration: the nodes which
+// disagree on the settings the RTE consumes are reported, and the configuration of the first node is used.
+// The nodes which fail to report their configuration are skipped and reported, unless all of them fail.
+// Returns nil if the pool has no nodes.
+func (r *KubeletConfigzReconciler) fetchKubeletConfig(ctx context.Context, pool nodegroupv1.Pool) (*poolKubeletConfig, error) {
+	if pool.NodeSelector == nil {
+		return nil, nil
+	}
+	sel, err := metav1.LabelSelectorAsSelector(pool.NodeSelector)
+	if err != nil {
+		return nil, err
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+		return nil, err
+	}
+	if len(nodes.Items) == 0 {
+		return nil, nil
+	}
+	// deterministic ordering, to minimize the churn if the nodes disagree
+	sort.Slice(nodes.Items, func(i, j int) bool {
+		return nodes.Items[i].Name < nodes.Items[j].Name
+	})
+
+	kubeletConfigs := make([]*kubeletconfigv1beta1.KubeletConfiguration, len(nodes.Items))
+	fetchErrs := make([]error, len(nodes.Items))
+	var eg errgroup.Group
+	eg.SetLimit(kubeletConfigzMaxConcurrentFetches)
+	for idx := range nodes.Items {
+		idx := idx // capture the loop variable
+		eg.Go(func() error {
+			fetchCtx, cancel := context.WithTimeout(ctx, kubeletConfigzFetchTimeout)
+			defer cancel()
+			kubeletConfigs[idx], fetchErrs[idx] = kubeletconfig.FetchFromConfigz(fetchCtx, r.RESTClient, nodes.Items[idx].Name)
+			return nil
+		})
+	}
+	_ = eg.Wait() // the errors are per node, collected above
+
+	var poolConf *poolKubeletConfig
+	var unreachableNodes []string
+	var lastErr error
+	for idx, node := range nodes.Items {
+		kubeletConfig, err := kubeletConfigs[idx], fetchErrs[idx]
+		if err != nil {
+			klog.V(2).InfoS("cannot get kubelet configz", "node", node.Name, "pool", pool.Name, "error", err)
+			unreachableNodes = append(unreachableNodes, node.Name)
+			lastErr = err
+			continue
+		}
+		klog.V(4).InfoS("got kubelet configz", "node", node.Name, "pool", pool.Name, "topologyManagerPolicy", kubeletConfig.TopologyManagerPolicy, "topologyManagerScope", kubeletConfig.TopologyManagerScope)
+		if poolConf == nil {
+			poolConf = &poolKubeletConfig{
+				nodeName: node.Name,
+				config:   kubeletConfig,
+			}
+			continue
+		}
+		if kubeletConfig.TopologyManagerPolicy != poolConf.config.TopologyManagerPolicy || kubeletConfig.TopologyManagerScope != poolConf.config.TopologyManagerScope {
+			klog.V(2).InfoS("kubelet configz mismatch", "node", node.Name, "referenceNode", poolConf.nodeName, "pool", pool.Name)
+			poolConf.mismatchingNodes = append(poolConf.mismatchingNodes, node.Name)
+		}
+	}
+	if poolConf == nil {
+		return nil, lastErr
+	}
+	poolConf.unreachableNodes = unreachableNodes
+	return poolConf, nil
+}
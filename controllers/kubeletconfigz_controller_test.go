@@ -0,0 +1,244 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/k8stopologyawareschedwg/deployer/pkg/deployer/platform"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	restfake "k8s.io/client-go/rest/fake"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	"github.com/openshift-kni/numaresources-operator/pkg/objectnames"
+	"github.com/openshift-kni/numaresources-operator/pkg/status"
+	rteconfig "github.com/openshift-kni/numaresources-operator/rte/pkg/config"
+
+	testobjs "github.com/openshift-kni/numaresources-operator/internal/objects"
+)
+
+func NewFakeKubeletConfigzReconciler(configzData map[string]string, initObjects ...runtime.Object) (*KubeletConfigzReconciler, error) {
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(initObjects...).Build()
+	// the fetches run concurrently, and the fake RESTClient records the requests without locking,
+	// so use a real RESTClient on top of the fake transport
+	httpClient := restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+		if _, ok := req.Context().Deadline(); !ok {
+			return nil, fmt.Errorf("unbounded configz request %q", req.URL.Path)
+		}
+		for nodeName, data := range configzData {
+			if req.URL.Path == fmt.Sprintf("/api/v1/nodes/%s/proxy/configz", nodeName) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(bytes.NewBufferString(data)),
+				}, nil
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(bytes.NewBufferString("{}")),
+		}, nil
+	})
+	restConfig := &rest.Config{
+		Host:    "https://fake.test",
+		APIPath: "/api",
+		ContentConfig: rest.ContentConfig{
+			GroupVersion:         &schema.GroupVersion{Version: "v1"},
+			NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		},
+	}
+	restClient, err := rest.RESTClientForConfigAndClient(restConfig, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	return &KubeletConfigzReconciler{
+		Client:     fakeClient,
+		Scheme:     scheme.Scheme,
+		Namespace:  testNamespace,
+		Platform:   platform.Kubernetes,
+		Recorder:   record.NewFakeRecorder(bufferSize),
+		RESTClient: restClient,
+	}, nil
+}
+
+func newTestNode(name string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+}
+
+var _ = Describe("Test KubeletConfigz Reconcile", func() {
+	Context("with node groups using NodeSelector", func() {
+		var nro *nropv1.NUMAResourcesOperator
+		var nodeLabels map[string]string
+		var configzData string
+
+		BeforeEach(func() {
+			nodeLabels = map[string]string{
+				"node-role.kubernetes.io/worker": "",
+			}
+			nro = testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "workers", &metav1.LabelSelector{MatchLabels: nodeLabels}, nil)
+			configzData = `{"kubeletconfig":{"topologyManagerPolicy":"single-numa-node","topologyManagerScope":"pod"}}`
+		})
+
+		It("should create the RTE configmap from the node configz", func() {
+			node0 := newTestNode("node-0", nodeLabels)
+			node1 := newTestNode("node-1", nodeLabels)
+			// node-0 is unreachable, so node-1 must be used
+			reconciler, err := NewFakeKubeletConfigzReconciler(map[string]string{"node-1": configzData}, nro, node0, node1)
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(kubeletConfigzResyncPeriod))
+
+			cm := &corev1.ConfigMap{}
+			cmKey := client.ObjectKey{
+				Namespace: testNamespace,
+				Name:      objectnames.GetComponentName(nro.Name, "workers"),
+			}
+			Expect(reconciler.Client.Get(context.TODO(), cmKey, cm)).To(Succeed())
+			Expect(cm.Labels).To(HaveKeyWithValue(rteconfig.LabelOperatorName, nro.Name))
+			Expect(cm.Labels).To(HaveKeyWithValue(rteconfig.LabelNodeGroupName+"/"+rteconfig.LabelNodeGroupKindNodeGroup, "workers"))
+			Expect(metav1.IsControlledBy(cm, nro)).To(BeTrue())
+
+			conf, err := rteconfig.Unrender(cm.Data[rteconfig.Key])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(conf.TopologyManagerPolicy).To(Equal("single-numa-node"))
+			Expect(conf.TopologyManagerScope).To(Equal("pod"))
+
+			fakeRecorder, ok := reconciler.Recorder.(*record.FakeRecorder)
+			Expect(ok).To(BeTrue())
+			unreachableEvent := <-fakeRecorder.Events
+			Expect(unreachableEvent).To(ContainSubstring(status.ReasonKubeletConfigzUnreachable))
+			Expect(unreachableEvent).To(ContainSubstring("Nodes node-0 of node group workers"))
+			Expect(<-fakeRecorder.Events).To(ContainSubstring("ProcessOK"))
+
+			// nothing changed, so only the unreachable node is reported again
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(<-fakeRecorder.Events).To(ContainSubstring(status.ReasonKubeletConfigzUnreachable))
+			Expect(fakeRecorder.Events).To(BeEmpty())
+		})
+
+		It("should warn if the nodes report different configz", func() {
+			node0 := newTestNode("node-0", nodeLabels)
+			node1 := newTestNode("node-1", nodeLabels)
+			node2 := newTestNode("node-2", nodeLabels)
+			configzRestricted := `{"kubeletconfig":{"topologyManagerPolicy":"restricted","topologyManagerScope":"pod"}}`
+			reconciler, err := NewFakeKubeletConfigzReconciler(map[string]string{
+				"node-0": configzData,
+				"node-1": configzRestricted,
+				"node-2": configzData,
+			}, nro, node0, node1, node2)
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			cm := &corev1.ConfigMap{}
+			cmKey := client.ObjectKey{
+				Namespace: testNamespace,
+				Name:      objectnames.GetComponentName(nro.Name, "workers"),
+			}
+			Expect(reconciler.Client.Get(context.TODO(), cmKey, cm)).To(Succeed())
+			conf, err := rteconfig.Unrender(cm.Data[rteconfig.Key])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(conf.TopologyManagerPolicy).To(Equal("single-numa-node"))
+
+			fakeRecorder, ok := reconciler.Recorder.(*record.FakeRecorder)
+			Expect(ok).To(BeTrue())
+			mismatchEvent := <-fakeRecorder.Events
+			Expect(mismatchEvent).To(ContainSubstring(status.ReasonKubeletConfigMismatch))
+			Expect(mismatchEvent).To(ContainSubstring("Nodes node-1 of node group workers"))
+			Expect(<-fakeRecorder.Events).To(ContainSubstring("ProcessOK"))
+		})
+
+		It("should not create the RTE configmap if the node group has no nodes", func() {
+			reconciler, err := NewFakeKubeletConfigzReconciler(map[string]string{"node-0": configzData}, nro, newTestNode("node-0", map[string]string{"foo": "bar"}))
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			cm := &corev1.ConfigMap{}
+			cmKey := client.ObjectKey{
+				Namespace: testNamespace,
+				Name:      objectnames.GetComponentName(nro.Name, "workers"),
+			}
+			Expect(reconciler.Client.Get(context.TODO(), cmKey, cm)).ToNot(Succeed())
+		})
+
+		It("should fail if no node reports its configz", func() {
+			reconciler, err := NewFakeKubeletConfigzReconciler(map[string]string{}, nro, newTestNode("node-0", nodeLabels))
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).To(HaveOccurred())
+
+			fakeRecorder, ok := reconciler.Recorder.(*record.FakeRecorder)
+			Expect(ok).To(BeTrue())
+			Expect(<-fakeRecorder.Events).To(ContainSubstring("ProcessFailed"))
+		})
+
+		It("should not override the RTE configmap managed by a KubeletConfig", func() {
+			cm := rteconfig.CreateConfigMap(testNamespace, objectnames.GetComponentName(nro.Name, "workers"), "topologyManagerPolicy: restricted\n")
+			cm.OwnerReferences = []metav1.OwnerReference{
+				{
+					APIVersion: "machineconfiguration.openshift.io/v1",
+					Kind:       "KubeletConfig",
+					Name:       "test-kc",
+					UID:        "test-uid",
+					Controller: func() *bool { b := true; return &b }(),
+				},
+			}
+			reconciler, err := NewFakeKubeletConfigzReconciler(map[string]string{"node-0": configzData}, nro, cm, newTestNode("node-0", nodeLabels))
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			updatedCm := &corev1.ConfigMap{}
+			Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(cm), updatedCm)).To(Succeed())
+			Expect(updatedCm.Data).To(Equal(cm.Data))
+		})
+	})
+})
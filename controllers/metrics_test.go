@@ -0,0 +1,87 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/k8stopologyawareschedwg/deployer/pkg/deployer/platform"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift-kni/numaresources-operator/internal/metrics"
+	testobjs "github.com/openshift-kni/numaresources-operator/internal/objects"
+	"github.com/openshift-kni/numaresources-operator/pkg/objectnames"
+	"github.com/openshift-kni/numaresources-operator/pkg/status"
+	"github.com/openshift-kni/numaresources-operator/pkg/validation"
+)
+
+var _ = Describe("Test controllers metrics", func() {
+	Context("with the NUMAResourcesOperator controller", func() {
+		It("should count the degraded reconciles by reason", func() {
+			nro := testobjs.NewNUMAResourcesOperator(objectnames.DefaultNUMAResourcesOperatorCrName, []*metav1.LabelSelector{nil})
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.OpenShift, defaultOCPVersion, nro)
+			Expect(err).ToNot(HaveOccurred())
+
+			counter := metrics.ReconcileTotal.WithLabelValues(metrics.ControllerNUMAResourcesOperator, status.ConditionDegraded, validation.NodeGroupsError)
+			before := testutil.ToFloat64(counter)
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(nro)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+		})
+
+		It("should report the node groups and the MachineConfigPools updating", func() {
+			labels := map[string]string{"test": "test"}
+			mcp := testobjs.NewMachineConfigPool("test", labels, &metav1.LabelSelector{MatchLabels: labels}, &metav1.LabelSelector{MatchLabels: labels})
+			nro := testobjs.NewNUMAResourcesOperator(objectnames.DefaultNUMAResourcesOperatorCrName, []*metav1.LabelSelector{{MatchLabels: labels}})
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.OpenShift, defaultOCPVersion, nro, mcp)
+			Expect(err).ToNot(HaveOccurred())
+
+			// the MachineConfigPool is not updated yet
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(nro)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(testutil.ToFloat64(metrics.NodeGroups)).To(Equal(float64(1)))
+			Expect(testutil.ToFloat64(metrics.MachineConfigPoolsUpdating)).To(Equal(float64(1)))
+		})
+	})
+
+	Context("with the NUMAResourcesScheduler controller", func() {
+		It("should report the scheduler as not available while the deployment is not ready", func() {
+			nrs := testobjs.NewNUMAResourcesScheduler(objectnames.DefaultNUMAResourcesSchedulerCrName, "some/url:latest", testSchedulerName, 9*time.Second)
+			reconciler, err := NewFakeNUMAResourcesSchedulerReconciler(nrs)
+			Expect(err).ToNot(HaveOccurred())
+
+			metrics.UpdateSchedulerAvailableMetric(true)
+			counter := metrics.ReconcileTotal.WithLabelValues(metrics.ControllerNUMAResourcesScheduler, status.ConditionProgressing, status.ReasonDeploymentNotReady)
+			before := testutil.ToFloat64(counter)
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(nrs)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(testutil.ToFloat64(metrics.SchedulerAvailable)).To(Equal(float64(0)))
+			Expect(testutil.ToFloat64(counter)).To(Equal(before + 1))
+		})
+	})
+})
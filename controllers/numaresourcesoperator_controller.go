@@ -20,43 +20,56 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/k8stopologyawareschedwg/deployer/pkg/deployer/platform"
 	"github.com/k8stopologyawareschedwg/deployer/pkg/manifests"
 	apimanifests "github.com/k8stopologyawareschedwg/deployer/pkg/manifests/api"
 	rtemanifests "github.com/k8stopologyawareschedwg/deployer/pkg/manifests/rte"
+	nrtv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
 	securityv1 "github.com/openshift/api/security/v1"
 	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
 	nodegroupv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1/helper/nodegroup"
+	"github.com/openshift-kni/numaresources-operator/internal/events"
+	"github.com/openshift-kni/numaresources-operator/internal/metrics"
+	intnrt "github.com/openshift-kni/numaresources-operator/internal/noderesourcetopology"
 	"github.com/openshift-kni/numaresources-operator/internal/relatedobjects"
 	"github.com/openshift-kni/numaresources-operator/pkg/apply"
 	"github.com/openshift-kni/numaresources-operator/pkg/hash"
 	"github.com/openshift-kni/numaresources-operator/pkg/images"
 	"github.com/openshift-kni/numaresources-operator/pkg/loglevel"
+	"github.com/openshift-kni/numaresources-operator/pkg/numaresourcesoperator/manifests/rtemetrics"
 	"github.com/openshift-kni/numaresources-operator/pkg/objectnames"
 	apistate "github.com/openshift-kni/numaresources-operator/pkg/objectstate/api"
 	rtestate "github.com/openshift-kni/numaresources-operator/pkg/objectstate/rte"
@@ -67,18 +80,34 @@ import (
 
 const numaResourcesRetryPeriod = 1 * time.Minute
 
+// maxStaleNodesInMessage caps the nodes listed in the NodeResourceTopologyStale condition message, which must stay short
+const maxStaleNodesInMessage = 5
+
 // NUMAResourcesOperatorReconciler reconciles a NUMAResourcesOperator object
 type NUMAResourcesOperatorReconciler struct {
 	client.Client
-	Scheme          *runtime.Scheme
-	Platform        platform.Platform
-	APIManifests    apimanifests.Manifests
-	RTEManifests    rtemanifests.Manifests
-	Namespace       string
-	Images          images.Data
-	ImagePullPolicy corev1.PullPolicy
-	Recorder        record.EventRecorder
-	ForwardMCPConds bool
+	Scheme              *runtime.Scheme
+	Platform            platform.Platform
+	APIManifests        apimanifests.Manifests
+	RTEManifests        rtemanifests.Manifests
+	RTEMetricsManifests rtemetrics.Manifests
+	Namespace           string
+	Images              images.Data
+	ImagePullPolicy     corev1.PullPolicy
+	Recorder            record.EventRecorder
+	ForwardMCPConds     bool
+	// Clock tells the time the NRT objects staleness is checked against. Defaults to the real clock.
+	Clock clock.PassiveClock
+
+	// the NRT API may be installed only after we start, so we can watch the NRT objects
+	// only once the CRD is in place. Reconcile is never run concurrently.
+	controller controller.Controller
+	cache      cache.Cache
+	nrtWatched bool
+
+	// the same state is observed over and over while waiting for the cluster to settle,
+	// so the events about it must be recorded only once
+	events events.Deduplicator
 }
 
 // TODO: narrow down
@@ -87,13 +116,14 @@ type NUMAResourcesOperatorReconciler struct {
 // TODO
 
 // Cluster Scoped
-//+kubebuilder:rbac:groups=topology.node.k8s.io,resources=noderesourcetopologies,verbs=get;list;create;update
+//+kubebuilder:rbac:groups=topology.node.k8s.io,resources=noderesourcetopologies,verbs=get;list;watch;create;update
 //+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=list
 //+kubebuilder:rbac:groups=config.openshift.io,resources=clusteroperators,verbs=get
 //+kubebuilder:rbac:groups=machineconfiguration.openshift.io,resources=machineconfigs,verbs=*
 //+kubebuilder:rbac:groups=machineconfiguration.openshift.io,resources=machineconfigpools,verbs=get;list;watch
 //+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=*
 //+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=*
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions/status,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=*
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=*
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=*
@@ -101,6 +131,7 @@ type NUMAResourcesOperatorReconciler struct {
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=*
 //+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=*
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;patch
 //+kubebuilder:rbac:groups=nodetopology.openshift.io,resources=numaresourcesoperators,verbs=*
 //+kubebuilder:rbac:groups=nodetopology.openshift.io,resources=numaresourcesoperators/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=nodetopology.openshift.io,resources=numaresourcesoperators/finalizers,verbs=update
@@ -121,6 +152,12 @@ func (r *NUMAResourcesOperatorReconciler) Reconcile(ctx context.Context, req ctr
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			if req.Name == objectnames.DefaultNUMAResourcesOperatorCrName {
+				metrics.UpdateNodeGroupsMetrics(nil)
+				metrics.UpdateMachineConfigPoolsUpdatingMetric(0)
+				// the node labels are not owned objects, so we need to clean them up ourselves
+				return ctrl.Result{}, r.syncStaleNodeTopologyLabels(ctx, nil, nil, nil)
+			}
 			return ctrl.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -136,7 +173,23 @@ func (r *NUMAResourcesOperatorReconciler) Reconcile(ctx context.Context, req ctr
 		return r.updateStatus(ctx, instance, status.ConditionDegraded, validation.NodeGroupsError, err.Error())
 	}
 
-	trees, err := getTreesByNodeGroup(ctx, r.Client, instance.Spec.NodeGroups)
+	if err := validation.NodeGroupsPlatform(instance.Spec.NodeGroups, r.Platform); err != nil {
+		return r.updateStatus(ctx, instance, status.ConditionDegraded, validation.NodeGroupsError, err.Error())
+	}
+
+	if err := validation.NodeGroupsConfig(instance.Spec.NodeGroups); err != nil {
+		return r.updateStatus(ctx, instance, status.ConditionDegraded, validation.NodeGroupsError, err.Error())
+	}
+
+	if err := validation.RTEMetricsPlatform(instance.Spec.RTEMetrics, r.Platform); err != nil {
+		return r.updateStatus(ctx, instance, status.ConditionDegraded, validation.OperatorSpecError, err.Error())
+	}
+
+	if err := validation.PodExcludes(instance.Spec.PodExcludes); err != nil {
+		return r.updateStatus(ctx, instance, status.ConditionDegraded, validation.OperatorSpecError, err.Error())
+	}
+
+	trees, err := getTreesByNodeGroup(ctx, r.Client, r.Platform, instance.Spec.NodeGroups)
 	if err != nil {
 		return r.updateStatus(ctx, instance, status.ConditionDegraded, validation.NodeGroupsError, err.Error())
 	}
@@ -145,24 +198,50 @@ func (r *NUMAResourcesOperatorReconciler) Reconcile(ctx context.Context, req ctr
 		return r.updateStatus(ctx, instance, status.ConditionDegraded, validation.NodeGroupsError, err.Error())
 	}
 
+	if err := r.validateNodeSelectorGroups(ctx, trees); err != nil {
+		return r.updateStatus(ctx, instance, status.ConditionDegraded, validation.NodeGroupsError, err.Error())
+	}
+
+	if err := r.findNodeSelectorMachineConfigPools(ctx, trees); err != nil {
+		return r.updateStatus(ctx, instance, status.ConditionDegraded, validation.NodeGroupsError, err.Error())
+	}
+
+	// the validation failures must be reported again if they come back after a fix
+	r.events.Forget(instance, validation.NodeGroupsError)
+	r.events.Forget(instance, validation.OperatorSpecError)
+
 	for idx := range trees {
 		conf := trees[idx].NodeGroup.NormalizeConfig()
 		trees[idx].NodeGroup.Config = &conf
 	}
 
-	result, condition, err := r.reconcileResource(ctx, instance, trees)
-	if condition != "" {
-		// TODO: use proper reason
-		reason, message := condition, messageFromError(err)
-		_, _ = r.updateStatus(ctx, instance, condition, reason, message)
+	result, cond, err := r.reconcileResource(ctx, instance, trees)
+	nodeGroupsUpdated, recheckNodeTopologies := r.syncNodeGroupsStatus(ctx, instance, trees)
+	metrics.UpdateNodeGroupsMetrics(instance.Status.NodeGroups)
+	metrics.UpdateMachineConfigPoolsUpdatingMetric(len(machineConfigPoolsUpdating(instance.Name, trees)))
+	if cond.Type != "" {
+		metrics.UpdateReconcileMetric(metrics.ControllerNUMAResourcesOperator, cond)
+		upgradeable := operatorUpgradeableCondition(ctx, r.Client, instance, trees, cond)
+		if _, err := updateStatus(ctx, r.Client, instance, nodeGroupsUpdated, cond.Type, cond.Reason, cond.Message, upgradeable); err != nil {
+			klog.InfoS("Failed to update numaresourcesoperator status", "Desired condition", cond.Type, "error", err)
+		}
+	}
+	// the NRT objects may not be watched yet, and their staleness is noticed only by the lack of updates,
+	// so we need to poll until all the node groups settle, and keep polling while NRTs are expected to refresh
+	if err == nil && result.IsZero() && (!allNodeGroupsAvailable(instance.Status.NodeGroups) || recheckNodeTopologies) {
+		result.RequeueAfter = numaResourcesRetryPeriod
 	}
 	return result, err
 }
 
 func (r *NUMAResourcesOperatorReconciler) updateStatus(ctx context.Context, instance *nropv1.NUMAResourcesOperator, condition string, reason string, message string) (ctrl.Result, error) {
 	klog.InfoS("updateStatus", "condition", condition, "reason", reason, "message", message)
+	metrics.UpdateReconcileMetric(metrics.ControllerNUMAResourcesOperator, status.ConditionInfo{Type: condition, Reason: reason, Message: message})
+	r.events.Eventf(r.Recorder, instance, corev1.EventTypeWarning, reason, "Invalid configuration: %s", message)
 
-	if _, err := updateStatus(ctx, r.Client, instance, condition, reason, message); err != nil {
+	// the failures reported here need to be fixed before upgrading
+	upgradeable := status.NewUpgradeableCondition(reason, message)
+	if _, err := updateStatus(ctx, r.Client, instance, false, condition, reason, message, upgradeable); err != nil {
 		klog.InfoS("Failed to update numaresourcesoperator status", "Desired condition", status.ConditionDegraded, "error", err)
 		return ctrl.Result{}, err
 	}
@@ -171,12 +250,15 @@ func (r *NUMAResourcesOperatorReconciler) updateStatus(ctx context.Context, inst
 	return ctrl.Result{}, nil
 }
 
-func updateStatus(ctx context.Context, cli client.Client, instance *nropv1.NUMAResourcesOperator, condition string, reason string, message string) (bool, error) {
-	conditions, ok := status.GetUpdatedConditions(instance.Status.Conditions, condition, reason, message)
-	if !ok {
+// updateStatus sends the status to the cluster if the conditions changed, or if statusUpdated is true
+// to signal that other status fields changed.
+func updateStatus(ctx context.Context, cli client.Client, instance *nropv1.NUMAResourcesOperator, statusUpdated bool, condition string, reason string, message string, upgradeable metav1.Condition) (bool, error) {
+	conditions, ok := status.GetUpdatedConditionsWithUpgradeable(instance.Status.Conditions, condition, reason, message, upgradeable, instance.Generation)
+	if !ok && !statusUpdated && instance.Status.ObservedGeneration == instance.Generation {
 		return false, nil
 	}
 	instance.Status.Conditions = conditions
+	instance.Status.ObservedGeneration = instance.Generation
 
 	if err := cli.Status().Update(ctx, instance); err != nil {
 		return false, errors.Wrapf(err, "could not update status for object %s", client.ObjectKeyFromObject(instance))
@@ -184,38 +266,36 @@ func updateStatus(ctx context.Context, cli client.Client, instance *nropv1.NUMAR
 	return true, nil
 }
 
-func messageFromError(err error) string {
-	if err == nil {
-		return ""
-	}
-	unwErr := errors.Unwrap(err)
-	if unwErr == nil {
-		return ""
-	}
-	return unwErr.Error()
-}
-
-func (r *NUMAResourcesOperatorReconciler) reconcileResourceAPI(ctx context.Context, instance *nropv1.NUMAResourcesOperator, trees []nodegroupv1.Tree) (bool, ctrl.Result, string, error) {
+func (r *NUMAResourcesOperatorReconciler) reconcileResourceAPI(ctx context.Context, instance *nropv1.NUMAResourcesOperator, trees []nodegroupv1.Tree) (bool, ctrl.Result, status.ConditionInfo, error) {
 	applied, err := r.syncNodeResourceTopologyAPI(ctx)
 	if err != nil {
-		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "FailedCRDInstall", "Failed to install Node Resource Topology CRD: %v", err)
-		return true, ctrl.Result{}, status.ConditionDegraded, errors.Wrapf(err, "FailedAPISync")
+		r.events.Eventf(r.Recorder, instance, corev1.EventTypeWarning, "FailedCRDInstall", "Failed to install Node Resource Topology CRD: %v", err)
+		err = status.NewReasonedError(status.ReasonNodeResourceTopologyAPISyncFailed, err)
+		return true, ctrl.Result{}, status.DegradedFromError(err, status.ReasonInternalError), err
 	}
+	r.events.Forget(instance, "FailedCRDInstall")
 	if applied {
 		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "SuccessfulCRDInstall", "Node Resource Topology CRD installed")
 	}
-	return false, ctrl.Result{}, "", nil
+	if err := r.watchNodeResourceTopologies(); err != nil {
+		// not fatal: we still poll the NRT objects while they are expected to change
+		klog.ErrorS(err, "cannot watch the NodeResourceTopology objects")
+	}
+	return false, ctrl.Result{}, status.ConditionInfo{}, nil
 }
 
-func (r *NUMAResourcesOperatorReconciler) reconcileResourceMachineConfig(ctx context.Context, instance *nropv1.NUMAResourcesOperator, trees []nodegroupv1.Tree) (bool, ctrl.Result, string, error) {
+func (r *NUMAResourcesOperatorReconciler) reconcileResourceMachineConfig(ctx context.Context, instance *nropv1.NUMAResourcesOperator, trees []nodegroupv1.Tree) (bool, ctrl.Result, status.ConditionInfo, error) {
 	// we need to sync machine configs first and wait for the MachineConfigPool updates
 	// before checking additional components for updates
 	_, err := r.syncMachineConfigs(ctx, instance, trees)
 	if err != nil {
-		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "FailedMCSync", "Failed to set up machine configuration for worker nodes: %v", err)
-		return true, ctrl.Result{}, status.ConditionDegraded, errors.Wrapf(err, "failed to sync machine configs")
+		r.events.Forget(instance, "SuccessfulMCSync")
+		r.events.Eventf(r.Recorder, instance, corev1.EventTypeWarning, "FailedMCSync", "Failed to set up machine configuration for worker nodes: %v", err)
+		err = status.NewReasonedError(status.ReasonMachineConfigSyncFailed, errors.Wrapf(err, "failed to sync machine configs"))
+		return true, ctrl.Result{}, status.DegradedFromError(err, status.ReasonInternalError), err
 	}
-	r.Recorder.Eventf(instance, corev1.EventTypeNormal, "SuccessfulMCSync", "Enabled machine configuration for worker nodes")
+	r.events.Forget(instance, "FailedMCSync")
+	r.events.Eventf(r.Recorder, instance, corev1.EventTypeNormal, "SuccessfulMCSync", "Enabled machine configuration for worker nodes")
 
 	// MCO need to update SELinux context and other stuff, and need to trigger a reboot.
 	// It can take a while.
@@ -223,39 +303,47 @@ func (r *NUMAResourcesOperatorReconciler) reconcileResourceMachineConfig(ctx con
 	instance.Status.MachineConfigPools = mcpStatuses
 	if !allMCPsUpdated {
 		// the Machine Config Pool still did not apply the machine config, wait for one minute
-		return true, ctrl.Result{RequeueAfter: numaResourcesRetryPeriod}, status.ConditionProgressing, nil
+		message := fmt.Sprintf("waiting for MachineConfigPool %q to apply the machine configuration", mcpStatuses[len(mcpStatuses)-1].Name)
+		r.events.Eventf(r.Recorder, instance, corev1.EventTypeNormal, status.ReasonMachineConfigPoolUpdating, "Waiting for MachineConfigPool %q to apply the machine configuration", mcpStatuses[len(mcpStatuses)-1].Name)
+		return true, ctrl.Result{RequeueAfter: numaResourcesRetryPeriod}, status.Progressing(status.ReasonMachineConfigPoolUpdating, message), nil
 	}
 
+	r.events.Forget(instance, status.ReasonMachineConfigPoolUpdating)
+
 	instance.Status.MachineConfigPools = syncMachineConfigPoolNodeGroupConfigStatuses(instance.Status.MachineConfigPools, trees)
-	return false, ctrl.Result{}, "", nil
+	return false, ctrl.Result{}, status.ConditionInfo{}, nil
 }
 
-func (r *NUMAResourcesOperatorReconciler) reconcileResourceDaemonSet(ctx context.Context, instance *nropv1.NUMAResourcesOperator, trees []nodegroupv1.Tree) (bool, ctrl.Result, string, error) {
+func (r *NUMAResourcesOperatorReconciler) reconcileResourceDaemonSet(ctx context.Context, instance *nropv1.NUMAResourcesOperator, trees []nodegroupv1.Tree) (bool, ctrl.Result, status.ConditionInfo, error) {
 	daemonSetsInfo, err := r.syncNUMAResourcesOperatorResources(ctx, instance, trees)
 	if err != nil {
-		r.Recorder.Eventf(instance, corev1.EventTypeWarning, "FailedRTECreate", "Failed to create Resource-Topology-Exporter DaemonSets: %v", err)
-		return true, ctrl.Result{}, status.ConditionDegraded, errors.Wrapf(err, "FailedRTESync")
+		r.events.Forget(instance, "SuccessfulRTECreate")
+		r.events.Eventf(r.Recorder, instance, corev1.EventTypeWarning, "FailedRTECreate", "Failed to create Resource-Topology-Exporter DaemonSets: %v", err)
+		err = status.NewReasonedError(status.ReasonDaemonSetSyncFailed, err)
+		return true, ctrl.Result{}, status.DegradedFromError(err, status.ReasonInternalError), err
 	}
+	r.events.Forget(instance, "FailedRTECreate")
 	if len(daemonSetsInfo) == 0 {
-		return false, ctrl.Result{}, "", nil
+		return false, ctrl.Result{}, status.ConditionInfo{}, nil
 	}
 
-	r.Recorder.Eventf(instance, corev1.EventTypeNormal, "SuccessfulRTECreate", "Created Resource-Topology-Exporter DaemonSets")
+	r.events.Eventf(r.Recorder, instance, corev1.EventTypeNormal, "SuccessfulRTECreate", "Created Resource-Topology-Exporter DaemonSets")
 
 	dsStatuses, allDSsUpdated, err := r.syncDaemonSetsStatuses(ctx, r.Client, daemonSetsInfo)
 	instance.Status.DaemonSets = dsStatuses
 	instance.Status.RelatedObjects = relatedobjects.ResourceTopologyExporter(r.Namespace, dsStatuses)
 	if err != nil {
-		return true, ctrl.Result{}, status.ConditionDegraded, err
+		err = status.NewReasonedError(status.ReasonDaemonSetSyncFailed, err)
+		return true, ctrl.Result{}, status.DegradedFromError(err, status.ReasonInternalError), err
 	}
 	if !allDSsUpdated {
-		return true, ctrl.Result{RequeueAfter: 5 * time.Second}, status.ConditionProgressing, nil
+		return true, ctrl.Result{RequeueAfter: 5 * time.Second}, status.Progressing(status.ReasonDaemonSetNotReady, "waiting for the RTE daemonsets to be ready"), nil
 	}
 
-	return false, ctrl.Result{}, "", nil
+	return false, ctrl.Result{}, status.ConditionInfo{}, nil
 }
 
-func (r *NUMAResourcesOperatorReconciler) reconcileResource(ctx context.Context, instance *nropv1.NUMAResourcesOperator, trees []nodegroupv1.Tree) (ctrl.Result, string, error) {
+func (r *NUMAResourcesOperatorReconciler) reconcileResource(ctx context.Context, instance *nropv1.NUMAResourcesOperator, trees []nodegroupv1.Tree) (ctrl.Result, status.ConditionInfo, error) {
 	if done, res, cond, err := r.reconcileResourceAPI(ctx, instance, trees); done {
 		return res, cond, err
 	}
@@ -270,7 +358,7 @@ func (r *NUMAResourcesOperatorReconciler) reconcileResource(ctx context.Context,
 		return res, cond, err
 	}
 
-	return ctrl.Result{}, status.ConditionAvailable, nil
+	return ctrl.Result{}, status.Available(), nil
 }
 
 func (r *NUMAResourcesOperatorReconciler) syncDaemonSetsStatuses(ctx context.Context, rd client.Reader, daemonSetsInfo []nropv1.NamespacedName) ([]nropv1.NamespacedName, bool, error) {
@@ -345,6 +433,11 @@ func (r *NUMAResourcesOperatorReconciler) syncMachineConfigs(ctx context.Context
 		if !updated {
 			continue
 		}
+		if objState.IsNotFoundError() {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, status.ReasonMachineConfigCreated, "Created MachineConfig %s", objState.Desired.GetName())
+		} else {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, status.ReasonMachineConfigUpdated, "Updated MachineConfig %s", objState.Desired.GetName())
+		}
 		updatedCount++
 	}
 
@@ -411,6 +504,302 @@ func syncMachineConfigPoolNodeGroupConfigStatuses(mcpStatuses []nropv1.MachineCo
 	return updatedMcpStatuses
 }
 
+// syncNodeGroupsStatus refreshes the per node group status and the NodeResourceTopologyStale condition.
+// Returns true if the status changed, and true if the NRT objects need to be checked again later
+// because they are expected to be refreshed periodically.
+func (r *NUMAResourcesOperatorReconciler) syncNodeGroupsStatus(ctx context.Context, instance *nropv1.NUMAResourcesOperator, trees []nodegroupv1.Tree) (bool, bool) {
+	klog.V(4).InfoS("Node Group Status Sync start", "trees", len(trees))
+	defer klog.V(4).Info("Node Group Status Sync stop")
+
+	nodeTopologies, err := r.getNodeTopologies(ctx)
+	if err != nil {
+		// we can still report the state of the daemonsets
+		klog.ErrorS(err, "cannot list the NodeResourceTopology objects")
+	}
+
+	recheck := false
+	staleNodes := []string{}
+	// the nodes whose staleness can't be told now, so their label must be kept as it is
+	unknownNodes := sets.New[string]()
+	nodesKnown := true
+	ngStatuses := []nropv1.NodeGroupStatus{}
+	for _, tree := range trees {
+		conf := nropv1.DefaultNodeGroupConfig()
+		if tree.NodeGroup != nil && tree.NodeGroup.Config != nil {
+			conf = *tree.NodeGroup.Config
+		}
+
+		for _, pool := range tree.Pools() {
+			ngStatus := nropv1.NodeGroupStatus{
+				Name: pool.Name,
+				DaemonSet: nropv1.NamespacedName{
+					Namespace: r.Namespace,
+					Name:      objectnames.GetComponentName(instance.Name, pool.Name),
+				},
+			}
+			if pool.MachineConfigPool != nil {
+				ngStatus.MachineConfigPool = pool.MachineConfigPool.Name
+			}
+			if tree.NodeGroup != nil && tree.NodeGroup.Config != nil {
+				ngStatus.Config = tree.NodeGroup.Config.DeepCopy()
+			}
+
+			var condition, reason, message string
+			nodes, err := r.getPoolNodes(ctx, pool)
+			if err != nil {
+				condition, reason, message = status.ConditionDegraded, status.ReasonNodeGroupStatusUnknown, err.Error()
+			} else {
+				condition, reason, message = r.observeNodeGroup(ctx, &ngStatus, nodes, nodeTopologies)
+			}
+			prevStatus := getNodeGroupStatusByName(instance.Status.NodeGroups, pool.Name)
+			ngStatus.Conditions, _ = status.GetUpdatedConditions(prevStatus.Conditions, condition, reason, message, instance.Generation)
+
+			// while the RTE pods are rolling out missing or outdated NRT objects are expected
+			if err != nil {
+				nodesKnown = false
+			} else if nodeTopologies != nil && (condition == status.ConditionAvailable || reason == status.ReasonNodeTopologyMissing) {
+				stale, periodic := findStaleNodeTopologies(nodes, nodeTopologies, conf, r.now())
+				staleNodes = append(staleNodes, stale...)
+				recheck = recheck || periodic
+			} else {
+				for _, node := range nodes {
+					unknownNodes.Insert(node.Name)
+				}
+			}
+
+			ngStatuses = append(ngStatuses, ngStatus)
+		}
+	}
+
+	updated := false
+	if nodeTopologies != nil {
+		cond := nodeTopologyStaleCondition(staleNodes)
+		cond.ObservedGeneration = instance.Generation
+		updated = meta.SetStatusCondition(&instance.Status.Conditions, cond)
+		if !nodesKnown {
+			klog.InfoS("cannot tell all the nodes, keeping the stale node topology labels")
+		} else if err := r.syncStaleNodeTopologyLabels(ctx, instance, staleNodes, unknownNodes); err != nil {
+			// we will try again at the next reconcile
+			klog.ErrorS(err, "cannot sync the stale node topology labels")
+		}
+	}
+
+	if apiequality.Semantic.DeepEqual(instance.Status.NodeGroups, ngStatuses) {
+		return updated, recheck
+	}
+	instance.Status.NodeGroups = ngStatuses
+	return true, recheck
+}
+
+func (r *NUMAResourcesOperatorReconciler) getPoolNodes(ctx context.Context, pool nodegroupv1.Pool) ([]corev1.Node, error) {
+	if pool.NodeSelector == nil {
+		return nil, nil
+	}
+	sel, err := metav1.LabelSelectorAsSelector(pool.NodeSelector)
+	if err != nil {
+		return nil, err
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+		return nil, err
+	}
+	return nodes.Items, nil
+}
+
+// observeNodeGroup fills the observed counters of the node group status, and returns the node group condition
+func (r *NUMAResourcesOperatorReconciler) observeNodeGroup(ctx context.Context, ngStatus *nropv1.NodeGroupStatus, nodes []corev1.Node, nodeTopologies map[string]*nrtv1alpha2.NodeResourceTopology) (string, string, string) {
+	for _, node := range nodes {
+		ngStatus.SelectedNodes++
+		if _, ok := nodeTopologies[node.Name]; ok {
+			ngStatus.NodesWithTopology++
+		}
+	}
+
+	ds := appsv1.DaemonSet{}
+	dsKey := client.ObjectKey{
+		Namespace: ngStatus.DaemonSet.Namespace,
+		Name:      ngStatus.DaemonSet.Name,
+	}
+	if err := r.Get(ctx, dsKey, &ds); err != nil {
+		if apierrors.IsNotFound(err) {
+			return status.ConditionProgressing, status.ReasonDaemonSetNotFound, fmt.Sprintf("daemonset %s not created yet", ngStatus.DaemonSet.String())
+		}
+		return status.ConditionDegraded, status.ReasonNodeGroupStatusUnknown, err.Error()
+	}
+	ngStatus.DesiredPods = ds.Status.DesiredNumberScheduled
+	ngStatus.ReadyPods = ds.Status.NumberReady
+	ngStatus.UpdatedPods = ds.Status.UpdatedNumberScheduled
+
+	if !isDaemonSetReady(&ds) || ngStatus.UpdatedPods < ngStatus.DesiredPods {
+		return status.ConditionProgressing, status.ReasonDaemonSetRollingOut, fmt.Sprintf("daemonset %s: desired=%d ready=%d updated=%d", ngStatus.DaemonSet.String(), ngStatus.DesiredPods, ngStatus.ReadyPods, ngStatus.UpdatedPods)
+	}
+	if ngStatus.NodesWithTopology < ngStatus.SelectedNodes {
+		return status.ConditionProgressing, status.ReasonNodeTopologyMissing, fmt.Sprintf("%d nodes out of %d have no topology data", ngStatus.SelectedNodes-ngStatus.NodesWithTopology, ngStatus.SelectedNodes)
+	}
+	return status.ConditionAvailable, "", ""
+}
+
+func (r *NUMAResourcesOperatorReconciler) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock.Now()
+}
+
+// getNodeTopologies returns the NRT objects by node name, or nil if the NRT API is not installed
+func (r *NUMAResourcesOperatorReconciler) getNodeTopologies(ctx context.Context) (map[string]*nrtv1alpha2.NodeResourceTopology, error) {
+	nrts := &nrtv1alpha2.NodeResourceTopologyList{}
+	if err := r.List(ctx, nrts); err != nil {
+		if meta.IsNoMatchError(err) {
+			// the API is not installed yet
+			return nil, nil
+		}
+		return nil, err
+	}
+	nodeTopologies := make(map[string]*nrtv1alpha2.NodeResourceTopology, len(nrts.Items))
+	for idx := range nrts.Items {
+		nrt := &nrts.Items[idx]
+		nodeTopologies[nrt.Name] = nrt
+	}
+	return nodeTopologies, nil
+}
+
+// findStaleNodeTopologies returns the names of the nodes whose NRT object is missing or stale, and true
+// if any NRT object is expected to be refreshed periodically, so staleness must be checked again later.
+// Staleness is detected from the time of the last update published by RTE, see intnrt.IsStale.
+func findStaleNodeTopologies(nodes []corev1.Node, nodeTopologies map[string]*nrtv1alpha2.NodeResourceTopology, conf nropv1.NodeGroupConfig, now time.Time) ([]string, bool) {
+	var staleNodes []string
+	periodic := false
+	for _, node := range nodes {
+		nrt, ok := nodeTopologies[node.Name]
+		if !ok {
+			staleNodes = append(staleNodes, node.Name)
+			continue
+		}
+		periodic = periodic || intnrt.ExpectedUpdateInterval(nrt, conf) > 0
+		if intnrt.IsStale(nrt, conf, now) {
+			staleNodes = append(staleNodes, node.Name)
+		}
+	}
+	return staleNodes, periodic
+}
+
+// syncStaleNodeTopologyLabels labels the nodes in staleNodes if the StaleNodeTopologyPolicy asks to exclude them,
+// and removes the label from all the other nodes but the ones in unknownNodes, whose staleness can't be told,
+// e.g. while their RTE pods are rolling out. A nil instance removes the label from all the nodes.
+func (r *NUMAResourcesOperatorReconciler) syncStaleNodeTopologyLabels(ctx context.Context, instance *nropv1.NUMAResourcesOperator, staleNodes []string, unknownNodes sets.Set[string]) error {
+	toLabel := sets.New[string]()
+	if instance != nil && instance.Spec.StaleNodeTopologyPolicy == nropv1.StaleNodeTopologyExclude {
+		toLabel.Insert(staleNodes...)
+	}
+
+	labeledNodes := &corev1.NodeList{}
+	if err := r.List(ctx, labeledNodes, client.HasLabels{nropv1.StaleNodeTopologyLabel}); err != nil {
+		return err
+	}
+
+	var errs []error
+	for idx := range labeledNodes.Items {
+		node := &labeledNodes.Items[idx]
+		if toLabel.Has(node.Name) {
+			toLabel.Delete(node.Name) // already labeled
+			continue
+		}
+		if instance != nil && instance.Spec.StaleNodeTopologyPolicy == nropv1.StaleNodeTopologyExclude && unknownNodes.Has(node.Name) {
+			continue
+		}
+		if err := r.setStaleNodeTopologyLabel(ctx, node, false); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		klog.InfoS("node topology data is fresh, node included in NUMA-aware scheduling", "node", node.Name)
+		if instance != nil {
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, status.ReasonNodeTopologyRecovered, "Node %s included again in NUMA-aware scheduling", node.Name)
+		}
+	}
+
+	for _, nodeName := range sets.List(toLabel) {
+		node := &corev1.Node{}
+		if err := r.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := r.setStaleNodeTopologyLabel(ctx, node, true); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		klog.InfoS("node topology data is stale, node excluded from NUMA-aware scheduling", "node", node.Name)
+		r.Recorder.Eventf(instance, corev1.EventTypeWarning, status.ReasonNodeTopologyStale, "Node %s excluded from NUMA-aware scheduling because its topology data is missing or stale", node.Name)
+	}
+
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+func (r *NUMAResourcesOperatorReconciler) setStaleNodeTopologyLabel(ctx context.Context, node *corev1.Node, stale bool) error {
+	patch := client.MergeFrom(node.DeepCopy())
+	if stale {
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[nropv1.StaleNodeTopologyLabel] = "true"
+	} else {
+		delete(node.Labels, nropv1.StaleNodeTopologyLabel)
+	}
+	if err := r.Patch(ctx, node, patch); err != nil {
+		return errors.Wrapf(err, "could not update the %q label on node %s", nropv1.StaleNodeTopologyLabel, node.Name)
+	}
+	return nil
+}
+
+func nodeTopologyStaleCondition(staleNodes []string) metav1.Condition {
+	if len(staleNodes) == 0 {
+		return metav1.Condition{
+			Type:    status.ConditionTypeNodeResourceTopologyStale,
+			Status:  metav1.ConditionFalse,
+			Reason:  status.ReasonNodeTopologyUpToDate,
+			Message: "all the NodeResourceTopology objects are up to date",
+		}
+	}
+	sort.Strings(staleNodes)
+	listed := staleNodes
+	if len(listed) > maxStaleNodesInMessage {
+		listed = listed[:maxStaleNodesInMessage]
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "missing or stale NodeResourceTopology objects for %d nodes: %s", len(staleNodes), strings.Join(listed, ","))
+	if more := len(staleNodes) - len(listed); more > 0 {
+		fmt.Fprintf(&sb, " and %d more", more)
+	}
+	return metav1.Condition{
+		Type:    status.ConditionTypeNodeResourceTopologyStale,
+		Status:  metav1.ConditionTrue,
+		Reason:  status.ReasonNodeTopologyStale,
+		Message: sb.String(),
+	}
+}
+
+func allNodeGroupsAvailable(ngStatuses []nropv1.NodeGroupStatus) bool {
+	for _, ngStatus := range ngStatuses {
+		cond := status.FindCondition(ngStatus.Conditions, status.ConditionAvailable)
+		if cond == nil || cond.Status != metav1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+func getNodeGroupStatusByName(ngStatuses []nropv1.NodeGroupStatus, name string) nropv1.NodeGroupStatus {
+	for _, ngStatus := range ngStatuses {
+		if ngStatus.Name == name {
+			return ngStatus
+		}
+	}
+	return nropv1.NodeGroupStatus{Name: name}
+}
+
 func getMachineConfigPoolStatusByName(mcpStatuses []nropv1.MachineConfigPool, name string) nropv1.MachineConfigPool {
 	for _, mcpStatus := range mcpStatuses {
 		if mcpStatus.Name == name {
@@ -429,9 +818,11 @@ func (r *NUMAResourcesOperatorReconciler) syncNUMAResourcesOperatorResources(ctx
 		klog.ErrorS(fmt.Errorf("failed to delete unused daemonsets"), "errors", errorList)
 	}
 
-	errorList = r.deleteUnusedMachineConfigs(ctx, instance, trees)
-	if len(errorList) > 0 {
-		klog.ErrorS(fmt.Errorf("failed to delete unused machineconfigs"), "errors", errorList)
+	if r.Platform == platform.OpenShift {
+		errorList = r.deleteUnusedMachineConfigs(ctx, instance, trees)
+		if len(errorList) > 0 {
+			klog.ErrorS(fmt.Errorf("failed to delete unused machineconfigs"), "errors", errorList)
+		}
 	}
 
 	var err error
@@ -452,6 +843,11 @@ func (r *NUMAResourcesOperatorReconciler) syncNUMAResourcesOperatorResources(ctx
 		return daemonSetsNName, err
 	}
 
+	err = rteupdate.DaemonSetMetrics(r.RTEManifests.DaemonSet, instance.Spec.RTEMetrics)
+	if err != nil {
+		return daemonSetsNName, err
+	}
+
 	// ConfigMap should be provided by the kubeletconfig reconciliation loop
 	if r.RTEManifests.ConfigMap != nil {
 		cmHash, err := hash.ComputeCurrentConfigMap(ctx, r.Client, r.RTEManifests.ConfigMap)
@@ -476,15 +872,22 @@ func (r *NUMAResourcesOperatorReconciler) syncNUMAResourcesOperatorResources(ctx
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to set controller reference to %s %s", objState.Desired.GetNamespace(), objState.Desired.GetName())
 		}
-		obj, _, err := apply.ApplyObject(ctx, r.Client, objState)
+		obj, updated, err := apply.ApplyObject(ctx, r.Client, objState)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to apply (%s) %s/%s", objState.Desired.GetObjectKind().GroupVersionKind(), objState.Desired.GetNamespace(), objState.Desired.GetName())
 		}
 
 		if nname, ok := rtestate.DaemonSetNamespacedNameFromObject(obj); ok {
 			daemonSetsNName = append(daemonSetsNName, nname)
+			if updated {
+				r.recordDaemonSetApplied(instance, objState.IsNotFoundError(), nname)
+			}
 		}
 	}
+
+	if err := r.syncRTEMetrics(ctx, instance); err != nil {
+		return nil, status.NewReasonedError(status.ReasonRTEMetricsSyncFailed, err)
+	}
 	return daemonSetsNName, nil
 }
 
@@ -500,8 +903,8 @@ func (r *NUMAResourcesOperatorReconciler) deleteUnusedDaemonSets(ctx context.Con
 
 	expectedDaemonSetNames := sets.NewString()
 	for _, tree := range trees {
-		for _, mcp := range tree.MachineConfigPools {
-			expectedDaemonSetNames = expectedDaemonSetNames.Insert(objectnames.GetComponentName(instance.Name, mcp.Name))
+		for _, pool := range tree.Pools() {
+			expectedDaemonSetNames = expectedDaemonSetNames.Insert(objectnames.GetComponentName(instance.Name, pool.Name))
 		}
 	}
 
@@ -510,9 +913,11 @@ func (r *NUMAResourcesOperatorReconciler) deleteUnusedDaemonSets(ctx context.Con
 			if isOwnedBy(ds.GetObjectMeta(), instance) {
 				if err := r.Client.Delete(ctx, &ds); err != nil {
 					klog.ErrorS(err, "error while deleting daemonset", "DaemonSet", ds.Name)
+					r.events.Eventf(r.Recorder, instance, corev1.EventTypeWarning, status.ReasonDaemonSetDeleteFailed, "Failed to delete unused DaemonSet %s/%s: %v", ds.Namespace, ds.Name, err)
 					errors = append(errors, err)
 				} else {
 					klog.V(3).InfoS("Daemonset deleted", "name", ds.Name)
+					r.Recorder.Eventf(instance, corev1.EventTypeNormal, status.ReasonDaemonSetDeleted, "Deleted unused DaemonSet %s/%s", ds.Namespace, ds.Name)
 				}
 			}
 		}
@@ -542,9 +947,11 @@ func (r *NUMAResourcesOperatorReconciler) deleteUnusedMachineConfigs(ctx context
 			if isOwnedBy(mc.GetObjectMeta(), instance) {
 				if err := r.Client.Delete(ctx, &mc); err != nil {
 					klog.ErrorS(err, "error while deleting machineconfig", "MachineConfig", mc.Name)
+					r.events.Eventf(r.Recorder, instance, corev1.EventTypeWarning, status.ReasonMachineConfigDeleteFailed, "Failed to delete unused MachineConfig %s: %v", mc.Name, err)
 					errors = append(errors, err)
 				} else {
 					klog.V(3).InfoS("Machineconfig deleted", "name", mc.Name)
+					r.Recorder.Eventf(instance, corev1.EventTypeNormal, status.ReasonMachineConfigDeleted, "Deleted unused MachineConfig %s", mc.Name)
 				}
 			}
 		}
@@ -552,6 +959,14 @@ func (r *NUMAResourcesOperatorReconciler) deleteUnusedMachineConfigs(ctx context
 	return errors
 }
 
+func (r *NUMAResourcesOperatorReconciler) recordDaemonSetApplied(instance *nropv1.NUMAResourcesOperator, created bool, nname nropv1.NamespacedName) {
+	if created {
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, status.ReasonDaemonSetCreated, "Created DaemonSet %s", nname.String())
+		return
+	}
+	r.Recorder.Eventf(instance, corev1.EventTypeNormal, status.ReasonDaemonSetUpdated, "Updated DaemonSet %s", nname.String())
+}
+
 func isOwnedBy(element metav1.Object, owner metav1.Object) bool {
 	for _, ref := range element.GetOwnerReferences() {
 		if ref.UID == owner.GetUID() {
@@ -594,19 +1009,51 @@ func (r *NUMAResourcesOperatorReconciler) SetupWithManager(mgr ctrl.Manager) err
 
 	b := ctrl.NewControllerManagedBy(mgr).For(&nropv1.NUMAResourcesOperator{})
 	if r.Platform == platform.OpenShift {
+		// the MCO objects exist only on OpenShift: on other platforms the informers would never sync
 		b = b.Owns(&securityv1.SecurityContextConstraints{}).
-			Owns(&machineconfigv1.MachineConfig{}, builder.WithPredicates(p))
+			Owns(&machineconfigv1.MachineConfig{}, builder.WithPredicates(p)).
+			Watches(
+				&machineconfigv1.MachineConfigPool{},
+				handler.EnqueueRequestsFromMapFunc(r.mcpToNUMAResourceOperator),
+				builder.WithPredicates(mcpPredicates))
 	}
-	return b.Owns(&apiextensionv1.CustomResourceDefinition{}).
+	c, err := b.Owns(&apiextensionv1.CustomResourceDefinition{}).
 		Owns(&corev1.ServiceAccount{}, builder.WithPredicates(p)).
 		Owns(&rbacv1.RoleBinding{}, builder.WithPredicates(p)).
 		Owns(&rbacv1.Role{}, builder.WithPredicates(p)).
 		Owns(&appsv1.DaemonSet{}, builder.WithPredicates(p)).
-		Watches(
-			&machineconfigv1.MachineConfigPool{},
-			handler.EnqueueRequestsFromMapFunc(r.mcpToNUMAResourceOperator),
-			builder.WithPredicates(mcpPredicates)).
-		Complete(r)
+		Owns(&corev1.Service{}, builder.WithPredicates(p)).
+		Owns(&networkingv1.NetworkPolicy{}, builder.WithPredicates(p)).
+		Build(r)
+	if err != nil {
+		return err
+	}
+	r.controller = c
+	r.cache = mgr.GetCache()
+	return nil
+}
+
+// watchNodeResourceTopologies starts watching the NRT objects, once. Must be called only after
+// the NRT API is installed, otherwise the informer would never sync.
+func (r *NUMAResourcesOperatorReconciler) watchNodeResourceTopologies() error {
+	if r.controller == nil || r.nrtWatched {
+		return nil
+	}
+	// we want to notice NRT objects being published or removed. Staleness can only be noticed
+	// by the lack of updates, so we poll for it instead of reacting to every update.
+	nrtPredicates := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return false
+		},
+	}
+	err := r.controller.Watch(source.Kind(r.cache, &nrtv1alpha2.NodeResourceTopology{}),
+		handler.EnqueueRequestsFromMapFunc(nodeToNUMAResourcesOperator),
+		nrtPredicates)
+	if err != nil {
+		return err
+	}
+	r.nrtWatched = true
+	return nil
 }
 
 func (r *NUMAResourcesOperatorReconciler) mcpToNUMAResourceOperator(ctx context.Context, mcpObj client.Object) []reconcile.Request {
@@ -633,6 +1080,15 @@ func (r *NUMAResourcesOperatorReconciler) mcpToNUMAResourceOperator(ctx context.
 		nro := &nros.Items[i]
 		mcpLabels := labels.Set(mcp.Labels)
 		for _, nodeGroup := range nro.Spec.NodeGroups {
+			// the node groups defined by NodeSelector may need any MachineConfigPool their nodes belong to
+			if nodeGroup.NodeSelector != nil {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: client.ObjectKey{
+						Name: nro.Name,
+					},
+				})
+				continue
+			}
 			if nodeGroup.MachineConfigPoolSelector == nil {
 				continue
 			}
@@ -691,6 +1147,19 @@ func IsMachineConfigPoolUpdatedAfterDeletion(instanceName string, mcp *machineco
 	return true
 }
 
+// machineConfigPoolsUpdating returns the names of the MachineConfigPools still applying the operator machine configuration
+func machineConfigPoolsUpdating(instanceName string, trees []nodegroupv1.Tree) []string {
+	var mcpNames []string
+	for _, tree := range trees {
+		for _, mcp := range tree.MachineConfigPools {
+			if !IsMachineConfigPoolUpdated(instanceName, mcp) {
+				mcpNames = append(mcpNames, mcp.Name)
+			}
+		}
+	}
+	return mcpNames
+}
+
 func isMachineConfigExists(instanceName string, mcp *machineconfigv1.MachineConfigPool) bool {
 	mcName := objectnames.GetMachineConfigName(instanceName, mcp.Name)
 	for _, s := range mcp.Status.Configuration.Source {
@@ -730,29 +1199,24 @@ func validateMachineConfigLabels(mc client.Object, trees []nodegroupv1.Tree) err
 	return nil
 }
 
-func daemonsetUpdater(mcpName string, gdm *rtestate.GeneratedDesiredManifest) error {
+func daemonsetUpdater(poolName string, gdm *rtestate.GeneratedDesiredManifest) error {
 	rteupdate.DaemonSetTolerations(gdm.DaemonSet, gdm.NodeGroup.Config.Tolerations)
 
 	err := rteupdate.DaemonSetArgs(gdm.DaemonSet, *gdm.NodeGroup.Config)
 	if err != nil {
-		klog.V(5).InfoS("DaemonSet update: cannot update arguments", "mcp", mcpName, "daemonset", gdm.DaemonSet.Name, "error", err)
+		klog.V(5).InfoS("DaemonSet update: cannot update arguments", "pool", poolName, "daemonset", gdm.DaemonSet.Name, "error", err)
 		return err
 	}
 
-	// on kubernetes we can just mount the kubeletconfig (no SCC/Selinux),
-	// so handling the kubeletconfig configmap is not needed at all.
+	// the RTE config is rendered from the MCO KubeletConfig or from the
+	// node's /configz, on all platforms, so we always mount it.
 	// We cannot do this at GetManifests time because we need to mount
 	// a specific configmap for each daemonset, whose name we know only
-	// when we instantiate the daemonset from the MCP.
-	if gdm.ClusterPlatform != platform.OpenShift {
-		klog.V(5).InfoS("DaemonSet update: unsupported platform", "mcp", mcpName, "platform", gdm.ClusterPlatform)
-		// nothing to do!
-		return nil
-	}
+	// when we instantiate the daemonset from the pool.
 	err = rteupdate.ContainerConfig(gdm.DaemonSet, gdm.DaemonSet.Name)
 	if err != nil {
 		// intentionally info because we want to keep going
-		klog.V(5).InfoS("DaemonSet update: cannot update config", "mcp", mcpName, "daemonset", gdm.DaemonSet.Name, "error", err)
+		klog.V(5).InfoS("DaemonSet update: cannot update config", "pool", poolName, "daemonset", gdm.DaemonSet.Name, "error", err)
 		return err
 	}
 	return nil
@@ -764,10 +1228,51 @@ func isDaemonSetReady(ds *appsv1.DaemonSet) bool {
 	return ok
 }
 
-func getTreesByNodeGroup(ctx context.Context, cli client.Client, nodeGroups []nropv1.NodeGroup) ([]nodegroupv1.Tree, error) {
+func (r *NUMAResourcesOperatorReconciler) validateNodeSelectorGroups(ctx context.Context, trees []nodegroupv1.Tree) error {
+	if !hasNodeSelectorGroups(trees) {
+		return nil
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return err
+	}
+	return validation.NodeSelectorGroups(trees, nodes.Items)
+}
+
+// findNodeSelectorMachineConfigPools adds to the trees of the node groups defined by NodeSelector the MachineConfigPools
+// their nodes belong to, which must get the RTE machine config. Must run after the node groups validation, which
+// expects only the MachineConfigPools selected by the node groups.
+func (r *NUMAResourcesOperatorReconciler) findNodeSelectorMachineConfigPools(ctx context.Context, trees []nodegroupv1.Tree) error {
+	if r.Platform != platform.OpenShift || !hasNodeSelectorGroups(trees) {
+		return nil
+	}
 	mcps := &machineconfigv1.MachineConfigPoolList{}
-	if err := cli.List(ctx, mcps); err != nil {
-		return nil, err
+	if err := r.List(ctx, mcps); err != nil {
+		return err
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return err
+	}
+	return nodegroupv1.FindNodeSelectorMachineConfigPools(trees, mcps, nodes.Items)
+}
+
+func hasNodeSelectorGroups(trees []nodegroupv1.Tree) bool {
+	for _, tree := range trees {
+		if tree.NodeGroup != nil && tree.NodeGroup.NodeSelector != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func getTreesByNodeGroup(ctx context.Context, cli client.Client, plat platform.Platform, nodeGroups []nropv1.NodeGroup) ([]nodegroupv1.Tree, error) {
+	mcps := &machineconfigv1.MachineConfigPoolList{}
+	// MachineConfigPools are not available outside OpenShift, and node groups must use NodeSelector there
+	if plat == platform.OpenShift {
+		if err := cli.List(ctx, mcps); err != nil {
+			return nil, err
+		}
 	}
 	return nodegroupv1.FindTrees(mcps, nodeGroups)
 }
@@ -18,6 +18,8 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -31,26 +33,33 @@ import (
 	"github.com/k8stopologyawareschedwg/deployer/pkg/deployer/platform"
 	apimanifests "github.com/k8stopologyawareschedwg/deployer/pkg/manifests/api"
 	rtemanifests "github.com/k8stopologyawareschedwg/deployer/pkg/manifests/rte"
+	nrtv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/k8sannotations"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
 	"github.com/openshift-kni/numaresources-operator/pkg/images"
+	"github.com/openshift-kni/numaresources-operator/pkg/numaresourcesoperator/manifests/rtemetrics"
 	"github.com/openshift-kni/numaresources-operator/pkg/objectnames"
 	"github.com/openshift-kni/numaresources-operator/pkg/objectstate/rte"
+	rteupdate "github.com/openshift-kni/numaresources-operator/pkg/objectupdate/rte"
 	"github.com/openshift-kni/numaresources-operator/pkg/status"
 	"github.com/openshift-kni/numaresources-operator/pkg/validation"
+	"github.com/openshift-kni/numaresources-operator/rte/pkg/heartbeat"
 
 	testobjs "github.com/openshift-kni/numaresources-operator/internal/objects"
 )
@@ -72,15 +81,21 @@ func NewFakeNUMAResourcesOperatorReconciler(plat platform.Platform, platVersion
 		return nil, err
 	}
 
+	rteMetricsManifests, err := rtemetrics.GetManifests(testNamespace)
+	if err != nil {
+		return nil, err
+	}
+
 	recorder := record.NewFakeRecorder(bufferSize)
 
 	return &NUMAResourcesOperatorReconciler{
-		Client:       fakeClient,
-		Scheme:       scheme.Scheme,
-		Platform:     plat,
-		APIManifests: apiManifests,
-		RTEManifests: rteManifests,
-		Namespace:    testNamespace,
+		Client:              fakeClient,
+		Scheme:              scheme.Scheme,
+		Platform:            plat,
+		APIManifests:        apiManifests,
+		RTEManifests:        rteManifests,
+		RTEMetricsManifests: rteMetricsManifests,
+		Namespace:           testNamespace,
 		Images: images.Data{
 			Builtin: testImageSpec,
 		},
@@ -118,6 +133,29 @@ var _ = Describe("Test NUMAResourcesOperator Reconcile", func() {
 		})
 	})
 
+	Context("with NRO machine config pool selector node group on kubernetes", func() {
+		It("should updated the CR condition to degraded", func() {
+			nro := testobjs.NewNUMAResourcesOperator(objectnames.DefaultNUMAResourcesOperatorCrName, []*metav1.LabelSelector{
+				{
+					MatchLabels: map[string]string{"test": "test"},
+				},
+			})
+
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.Kubernetes, defaultOCPVersion, nro)
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+
+			Expect(reconciler.Client.Get(context.TODO(), key, nro)).ToNot(HaveOccurred())
+			degradedCondition := getConditionByType(nro.Status.Conditions, status.ConditionDegraded)
+			Expect(degradedCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degradedCondition.Reason).To(Equal(validation.NodeGroupsError))
+		})
+	})
+
 	Context("without available machine config pools", func() {
 		It("should updated the CR condition to degraded", func() {
 			nro := testobjs.NewNUMAResourcesOperator(objectnames.DefaultNUMAResourcesOperatorCrName, []*metav1.LabelSelector{
@@ -263,6 +301,12 @@ var _ = Describe("Test NUMAResourcesOperator Reconcile", func() {
 				}
 				Expect(reconciler.Client.Get(context.TODO(), mc2Key, mc)).To(HaveOccurred(), "error: Machineconfig %v should have been deleted", mc2Key)
 			})
+			It("should record the deletions as events", func() {
+				events := drainEvents(reconciler.Recorder)
+				Expect(events).To(ContainElement(ContainSubstring(fmt.Sprintf("%s Deleted unused DaemonSet %s/%s", status.ReasonDaemonSetDeleted, testNamespace, objectnames.GetComponentName(nro.Name, mcp2.Name)))))
+				Expect(events).To(ContainElement(ContainSubstring(fmt.Sprintf("%s Deleted unused MachineConfig %s", status.ReasonMachineConfigDeleted, objectnames.GetMachineConfigName(nro.Name, mcp2.Name)))))
+				Expect(events).ToNot(ContainElement(ContainSubstring(fmt.Sprintf("Deleted unused DaemonSet %s/%s", testNamespace, objectnames.GetComponentName(nro.Name, mcp1.Name)))))
+			})
 			When("a NOT owned Daemonset exists", func() {
 				BeforeEach(func() {
 					By("Create a new Daemonset with correct name but not owner reference")
@@ -385,6 +429,18 @@ var _ = Describe("Test NUMAResourcesOperator Reconcile", func() {
 						Expect(len(nro.Status.MachineConfigPools)).To(Equal(1))
 						Expect(nro.Status.MachineConfigPools[0].Name).To(Equal("test1"))
 					})
+					It("should record the wait only once", func() {
+						events := drainEvents(reconciler.Recorder)
+						Expect(events).To(ContainElement(ContainSubstring(fmt.Sprintf("%s Created MachineConfig %s", status.ReasonMachineConfigCreated, objectnames.GetMachineConfigName(nro.Name, mcp1.Name)))))
+
+						waitEvents := 0
+						for _, event := range events {
+							if strings.Contains(event, status.ReasonMachineConfigPoolUpdating) {
+								waitEvents++
+							}
+						}
+						Expect(waitEvents).To(Equal(1), "unexpected events: %v", events)
+					})
 				})
 
 				When("machine config pools are ready", func() {
@@ -1088,8 +1144,790 @@ var _ = Describe("Test NUMAResourcesOperator Reconcile", func() {
 			Expect(ds.Spec.Template.Spec.Tolerations).To(Equal(reconciler.RTEManifests.DaemonSet.Spec.Template.Spec.Tolerations), "DS tolerations not restored to defaults")
 		})
 	})
+
+	Context("with RTE metrics", func() {
+		var labSel metav1.LabelSelector
+		var mcp *machineconfigv1.MachineConfigPool
+
+		BeforeEach(func() {
+			labels := map[string]string{
+				"test": "test",
+			}
+			labSel = metav1.LabelSelector{
+				MatchLabels: labels,
+			}
+			mcp = testobjs.NewMachineConfigPool("test", labels, &metav1.LabelSelector{MatchLabels: labels}, &metav1.LabelSelector{MatchLabels: labels})
+		})
+
+		It("should not serve the metrics by default", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeGroupConfig(objectnames.DefaultNUMAResourcesOperatorCrName, &labSel, nil)
+			reconciler := reconcileObjects(nro, mcp)
+
+			ds := &appsv1.DaemonSet{}
+			dsKey := client.ObjectKey{
+				Name:      objectnames.GetComponentName(nro.Name, mcp.Name),
+				Namespace: testNamespace,
+			}
+			Expect(reconciler.Client.Get(context.TODO(), dsKey, ds)).To(Succeed())
+			args := ds.Spec.Template.Spec.Containers[0].Args
+			Expect(args).ToNot(ContainElement(ContainSubstring("--metrics-mode")), "malformed args: %v", args)
+
+			svc := &corev1.Service{}
+			err := reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(reconciler.RTEMetricsManifests.Service), svc)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+		})
+
+		It("should serve the metrics over TLS and expose them when enabled, and clean up when disabled", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeGroupConfig(objectnames.DefaultNUMAResourcesOperatorCrName, &labSel, nil)
+			nro.Spec.RTEMetrics = nropv1.RTEMetricsEnabled
+			reconciler := reconcileObjects(nro, mcp)
+
+			ds := &appsv1.DaemonSet{}
+			dsKey := client.ObjectKey{
+				Name:      objectnames.GetComponentName(nro.Name, mcp.Name),
+				Namespace: testNamespace,
+			}
+			Expect(reconciler.Client.Get(context.TODO(), dsKey, ds)).To(Succeed())
+			args := ds.Spec.Template.Spec.Containers[0].Args
+			Expect(args).To(ContainElement("--metrics-mode=httptls"), "malformed args: %v", args)
+			Expect(ds.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("VolumeSource.Secret.SecretName", rteupdate.MetricsSecretName)))
+
+			svc := &corev1.Service{}
+			Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(reconciler.RTEMetricsManifests.Service), svc)).To(Succeed())
+			Expect(svc.Annotations).To(HaveKeyWithValue("service.beta.openshift.io/serving-cert-secret-name", rteupdate.MetricsSecretName))
+			np := &networkingv1.NetworkPolicy{}
+			Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(reconciler.RTEMetricsManifests.NetworkPolicy), np)).To(Succeed())
+
+			key := client.ObjectKeyFromObject(nro)
+			Expect(reconciler.Client.Get(context.TODO(), key, nro)).To(Succeed())
+			nro.Spec.RTEMetrics = nropv1.RTEMetricsDisabled
+			Expect(reconciler.Client.Update(context.TODO(), nro)).To(Succeed())
+
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(reconciler.Client.Get(context.TODO(), dsKey, ds)).To(Succeed())
+			args = ds.Spec.Template.Spec.Containers[0].Args
+			Expect(args).ToNot(ContainElement(ContainSubstring("--metrics-mode")), "malformed args: %v", args)
+
+			err = reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(svc), svc)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+			err = reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(np), np)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+		})
+	})
+
+	Context("with node groups using NodeSelector", func() {
+		var nodeSel *metav1.LabelSelector
+
+		BeforeEach(func() {
+			nodeSel = &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"numa-aware": "true",
+				},
+			}
+		})
+
+		It("should create the DS without waiting for MachineConfigPools", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.OpenShift, defaultOCPVersion, nro)
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Second}))
+
+			dsKey := client.ObjectKey{
+				Name:      objectnames.GetComponentName(nro.Name, "ng-numa"),
+				Namespace: testNamespace,
+			}
+			ds := &appsv1.DaemonSet{}
+			Expect(reconciler.Client.Get(context.TODO(), dsKey, ds)).To(Succeed())
+			Expect(ds.Spec.Template.Spec.NodeSelector).To(Equal(nodeSel.MatchLabels))
+
+			mcList := &machineconfigv1.MachineConfigList{}
+			Expect(reconciler.Client.List(context.TODO(), mcList)).To(Succeed())
+			Expect(mcList.Items).To(BeEmpty())
+
+			nroUpdated := &nropv1.NUMAResourcesOperator{}
+			Expect(reconciler.Client.Get(context.TODO(), key, nroUpdated)).To(Succeed())
+			Expect(nroUpdated.Status.MachineConfigPools).To(BeEmpty())
+		})
+
+		It("should become available once the DS is ready", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.Kubernetes, defaultOCPVersion, nro)
+			Expect(err).ToNot(HaveOccurred())
+
+			// the fake client creates the DS with the status of the manifest
+			reconciler.RTEManifests.DaemonSet.Status.DesiredNumberScheduled = 2
+			reconciler.RTEManifests.DaemonSet.Status.NumberReady = 2
+			reconciler.RTEManifests.DaemonSet.Status.UpdatedNumberScheduled = 2
+
+			key := client.ObjectKeyFromObject(nro)
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+
+			nroUpdated := &nropv1.NUMAResourcesOperator{}
+			Expect(reconciler.Client.Get(context.TODO(), key, nroUpdated)).To(Succeed())
+			availableCondition := getConditionByType(nroUpdated.Status.Conditions, status.ConditionAvailable)
+			Expect(availableCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(nroUpdated.Status.DaemonSets).To(ConsistOf(nropv1.NamespacedName{
+				Namespace: testNamespace,
+				Name:      objectnames.GetComponentName(nro.Name, "ng-numa"),
+			}))
+		})
+
+		It("should degrade serving the RTE metrics on kubernetes", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+			nro.Spec.RTEMetrics = nropv1.RTEMetricsEnabled
+
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.Kubernetes, defaultOCPVersion, nro)
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+
+			Expect(reconciler.Client.Get(context.TODO(), key, nro)).To(Succeed())
+			degradedCondition := getConditionByType(nro.Status.Conditions, status.ConditionDegraded)
+			Expect(degradedCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degradedCondition.Reason).To(Equal(validation.OperatorSpecError))
+
+			dsKey := client.ObjectKey{
+				Name:      objectnames.GetComponentName(nro.Name, "ng-numa"),
+				Namespace: testNamespace,
+			}
+			err = reconciler.Client.Get(context.TODO(), dsKey, &appsv1.DaemonSet{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "unexpected error: %v", err)
+		})
+
+		It("should degrade on malformed node group resource excludes", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, &nropv1.NodeGroupConfig{
+				ResourceExcludes: map[string][]string{
+					"worker-[": {"memory"},
+				},
+			})
+
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.Kubernetes, defaultOCPVersion, nro)
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+
+			Expect(reconciler.Client.Get(context.TODO(), key, nro)).To(Succeed())
+			degradedCondition := getConditionByType(nro.Status.Conditions, status.ConditionDegraded)
+			Expect(degradedCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degradedCondition.Reason).To(Equal(validation.NodeGroupsError))
+			Expect(degradedCondition.Message).To(ContainSubstring("malformed node name pattern"))
+		})
+
+		It("should degrade on malformed pod excludes", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+			nro.Spec.PodExcludes = []nropv1.NamespacedName{
+				{Namespace: "openshift-*"},
+			}
+
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.Kubernetes, defaultOCPVersion, nro)
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+
+			Expect(reconciler.Client.Get(context.TODO(), key, nro)).To(Succeed())
+			degradedCondition := getConditionByType(nro.Status.Conditions, status.ConditionDegraded)
+			Expect(degradedCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degradedCondition.Reason).To(Equal(validation.OperatorSpecError))
+			Expect(degradedCondition.Message).To(ContainSubstring("podExcludes[0]"))
+		})
+
+		It("should report the node group status", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+			node0 := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node-0",
+					Labels: nodeSel.MatchLabels,
+				},
+			}
+			node1 := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node-1",
+					Labels: nodeSel.MatchLabels,
+				},
+			}
+			nrt0 := &nrtv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-0",
+				},
+			}
+
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.Kubernetes, defaultOCPVersion, nro, node0, node1, nrt0)
+			Expect(err).ToNot(HaveOccurred())
+
+			// the fake client creates the DS with the status of the manifest
+			reconciler.RTEManifests.DaemonSet.Status.DesiredNumberScheduled = 2
+			reconciler.RTEManifests.DaemonSet.Status.NumberReady = 2
+			reconciler.RTEManifests.DaemonSet.Status.UpdatedNumberScheduled = 2
+
+			key := client.ObjectKeyFromObject(nro)
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			nroUpdated := &nropv1.NUMAResourcesOperator{}
+			Expect(reconciler.Client.Get(context.TODO(), key, nroUpdated)).To(Succeed())
+			Expect(nroUpdated.Status.NodeGroups).To(HaveLen(1))
+			ngStatus := nroUpdated.Status.NodeGroups[0]
+			Expect(ngStatus.Name).To(Equal("ng-numa"))
+			Expect(ngStatus.MachineConfigPool).To(BeEmpty())
+			Expect(ngStatus.DaemonSet).To(Equal(nropv1.NamespacedName{
+				Namespace: testNamespace,
+				Name:      objectnames.GetComponentName(nro.Name, "ng-numa"),
+			}))
+			Expect(ngStatus.DesiredPods).To(Equal(int32(2)))
+			Expect(ngStatus.ReadyPods).To(Equal(int32(2)))
+			Expect(ngStatus.UpdatedPods).To(Equal(int32(2)))
+			Expect(ngStatus.SelectedNodes).To(Equal(int32(2)))
+			Expect(ngStatus.NodesWithTopology).To(Equal(int32(1)))
+			Expect(ngStatus.Config).ToNot(BeNil())
+			Expect(*ngStatus.Config).To(Equal(nropv1.DefaultNodeGroupConfig()))
+			progressingCondition := getConditionByType(ngStatus.Conditions, status.ConditionProgressing)
+			Expect(progressingCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(progressingCondition.Reason).To(Equal(status.ReasonNodeTopologyMissing))
+
+			nrt1 := &nrtv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-1",
+				},
+			}
+			Expect(reconciler.Client.Create(context.TODO(), nrt1)).To(Succeed())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(reconciler.Client.Get(context.TODO(), key, nroUpdated)).To(Succeed())
+			Expect(nroUpdated.Status.NodeGroups).To(HaveLen(1))
+			Expect(nroUpdated.Status.NodeGroups[0].NodesWithTopology).To(Equal(int32(2)))
+			availableCondition := getConditionByType(nroUpdated.Status.NodeGroups[0].Conditions, status.ConditionAvailable)
+			Expect(availableCondition.Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("should report the stale and missing NRT objects", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+			var nodes []runtime.Object
+			for _, name := range []string{"node-0", "node-1", "node-2"} {
+				nodes = append(nodes, &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   name,
+						Labels: nodeSel.MatchLabels,
+					},
+				})
+			}
+			onTime := map[string]string{
+				k8sannotations.UpdateInterval:  "10s",
+				k8sannotations.SleepDuration:   "10s",
+				heartbeat.LastUpdateAnnotation: time.Now().UTC().Format(time.RFC3339),
+			}
+			nrtFresh := &nrtv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "node-0",
+					Annotations: onTime,
+				},
+			}
+			nrtStale := &nrtv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-1",
+					Annotations: map[string]string{
+						k8sannotations.UpdateInterval:  "10s",
+						k8sannotations.SleepDuration:   "10s",
+						heartbeat.LastUpdateAnnotation: time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+					},
+				},
+			}
+
+			objs := append(nodes, nro, nrtFresh, nrtStale)
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.Kubernetes, defaultOCPVersion, objs...)
+			Expect(err).ToNot(HaveOccurred())
+
+			reconciler.RTEManifests.DaemonSet.Status.DesiredNumberScheduled = 3
+			reconciler.RTEManifests.DaemonSet.Status.NumberReady = 3
+			reconciler.RTEManifests.DaemonSet.Status.UpdatedNumberScheduled = 3
+
+			key := client.ObjectKeyFromObject(nro)
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(numaResourcesRetryPeriod))
+
+			nroUpdated := &nropv1.NUMAResourcesOperator{}
+			Expect(reconciler.Client.Get(context.TODO(), key, nroUpdated)).To(Succeed())
+			staleCondition := getConditionByType(nroUpdated.Status.Conditions, status.ConditionTypeNodeResourceTopologyStale)
+			Expect(staleCondition).ToNot(BeNil())
+			Expect(staleCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(staleCondition.Reason).To(Equal(status.ReasonNodeTopologyStale))
+			Expect(staleCondition.Message).To(HaveSuffix("for 2 nodes: node-1,node-2"))
+
+			// the stale NRT objects must not affect the main conditions
+			availableCondition := getConditionByType(nroUpdated.Status.Conditions, status.ConditionAvailable)
+			Expect(availableCondition.Status).To(Equal(metav1.ConditionTrue))
+
+			By("refreshing the NRT objects")
+			Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(nrtStale), nrtStale)).To(Succeed())
+			nrtStale.Annotations = onTime
+			Expect(reconciler.Client.Update(context.TODO(), nrtStale)).To(Succeed())
+			nrtMissing := &nrtv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "node-2",
+					Annotations: onTime,
+				},
+			}
+			Expect(reconciler.Client.Create(context.TODO(), nrtMissing)).To(Succeed())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(reconciler.Client.Get(context.TODO(), key, nroUpdated)).To(Succeed())
+			staleCondition = getConditionByType(nroUpdated.Status.Conditions, status.ConditionTypeNodeResourceTopologyStale)
+			Expect(staleCondition).ToNot(BeNil())
+			Expect(staleCondition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(staleCondition.Reason).To(Equal(status.ReasonNodeTopologyUpToDate))
+		})
+
+		It("should bound the stale NRT objects condition message", func() {
+			var staleNodes []string
+			for idx := 0; idx < maxStaleNodesInMessage+3; idx++ {
+				staleNodes = append(staleNodes, fmt.Sprintf("node-%02d", idx))
+			}
+			cond := nodeTopologyStaleCondition(staleNodes)
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Message).To(HavePrefix(fmt.Sprintf("missing or stale NodeResourceTopology objects for %d nodes: node-00,", maxStaleNodesInMessage+3)))
+			Expect(cond.Message).To(HaveSuffix("and 3 more"))
+			Expect(cond.Message).ToNot(ContainSubstring(fmt.Sprintf("node-%02d", maxStaleNodesInMessage)))
+		})
+
+		It("should exclude the nodes with stale NRT objects if requested", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+			nro.Spec.StaleNodeTopologyPolicy = nropv1.StaleNodeTopologyExclude
+
+			staleLabels := map[string]string{
+				nropv1.StaleNodeTopologyLabel: "true",
+			}
+			for key, value := range nodeSel.MatchLabels {
+				staleLabels[key] = value
+			}
+			node0 := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node-0",
+					Labels: nodeSel.MatchLabels,
+				},
+			}
+			node1 := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node-1",
+					Labels: nodeSel.MatchLabels,
+				},
+			}
+			// excluded in the past, recovered since
+			node2 := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node-2",
+					Labels: staleLabels,
+				},
+			}
+			now := metav1.Now()
+			var nrts []runtime.Object
+			for _, name := range []string{"node-0", "node-2"} {
+				nrts = append(nrts, &nrtv1alpha2.NodeResourceTopology{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:              name,
+						CreationTimestamp: now,
+					},
+				})
+			}
+
+			objs := append(nrts, nro, node0, node1, node2)
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.Kubernetes, defaultOCPVersion, objs...)
+			Expect(err).ToNot(HaveOccurred())
+
+			reconciler.RTEManifests.DaemonSet.Status.DesiredNumberScheduled = 3
+			reconciler.RTEManifests.DaemonSet.Status.NumberReady = 3
+			reconciler.RTEManifests.DaemonSet.Status.UpdatedNumberScheduled = 3
+
+			key := client.ObjectKeyFromObject(nro)
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			expectStaleLabel := func(name string, expected bool) {
+				GinkgoHelper()
+				node := &corev1.Node{}
+				Expect(reconciler.Client.Get(context.TODO(), client.ObjectKey{Name: name}, node)).To(Succeed())
+				if expected {
+					Expect(node.Labels).To(HaveKeyWithValue(nropv1.StaleNodeTopologyLabel, "true"))
+				} else {
+					Expect(node.Labels).ToNot(HaveKey(nropv1.StaleNodeTopologyLabel))
+				}
+			}
+			expectStaleLabel("node-0", false)
+			expectStaleLabel("node-1", true)
+			expectStaleLabel("node-2", false)
+
+			By("deleting the NUMAResourcesOperator")
+			Expect(reconciler.Client.Delete(context.TODO(), nro)).To(Succeed())
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			expectStaleLabel("node-1", false)
+		})
+
+		It("should keep the stale node labels while the RTE pods roll out", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+			nro.Spec.StaleNodeTopologyPolicy = nropv1.StaleNodeTopologyExclude
+
+			staleLabels := map[string]string{
+				nropv1.StaleNodeTopologyLabel: "true",
+			}
+			for key, value := range nodeSel.MatchLabels {
+				staleLabels[key] = value
+			}
+			node0 := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node-0",
+					Labels: staleLabels,
+				},
+			}
+			nrt0 := &nrtv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-0",
+					Annotations: map[string]string{
+						k8sannotations.UpdateInterval:  "10s",
+						heartbeat.LastUpdateAnnotation: time.Now().UTC().Format(time.RFC3339),
+					},
+				},
+			}
+
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.Kubernetes, defaultOCPVersion, nro, node0, nrt0)
+			Expect(err).ToNot(HaveOccurred())
+
+			reconciler.RTEManifests.DaemonSet.Status.DesiredNumberScheduled = 1
+			reconciler.RTEManifests.DaemonSet.Status.NumberReady = 1
+			reconciler.RTEManifests.DaemonSet.Status.UpdatedNumberScheduled = 0
+
+			key := client.ObjectKeyFromObject(nro)
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(node0), node0)).To(Succeed())
+			Expect(node0.Labels).To(HaveKeyWithValue(nropv1.StaleNodeTopologyLabel, "true"))
+
+			By("completing the RTE rollout")
+			ds := &appsv1.DaemonSet{}
+			dsKey := client.ObjectKey{
+				Namespace: testNamespace,
+				Name:      objectnames.GetComponentName(nro.Name, "ng-numa"),
+			}
+			Expect(reconciler.Client.Get(context.TODO(), dsKey, ds)).To(Succeed())
+			ds.Status.UpdatedNumberScheduled = 1
+			Expect(reconciler.Client.Status().Update(context.TODO(), ds)).To(Succeed())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(node0), node0)).To(Succeed())
+			Expect(node0.Labels).ToNot(HaveKey(nropv1.StaleNodeTopologyLabel))
+			Expect(drainEvents(reconciler.Recorder)).To(ContainElement(HavePrefix(corev1.EventTypeNormal + " " + status.ReasonNodeTopologyRecovered + " ")))
+		})
+
+		It("should exclude the nodes whose RTE stops refreshing the NRT objects, and only them", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+			nro.Spec.StaleNodeTopologyPolicy = nropv1.StaleNodeTopologyExclude
+
+			var objs []runtime.Object
+			for _, name := range []string{"node-0", "node-1"} {
+				objs = append(objs, &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   name,
+						Labels: nodeSel.MatchLabels,
+					},
+				})
+			}
+			fakeClock := clocktesting.NewFakePassiveClock(time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC))
+			refreshed := func() map[string]string {
+				return map[string]string{
+					k8sannotations.UpdateInterval:  "10s",
+					k8sannotations.SleepDuration:   "10s",
+					heartbeat.LastUpdateAnnotation: fakeClock.Now().Format(time.RFC3339),
+				}
+			}
+			// the API server drops the no-op updates, so the NRT objects of idle nodes would be last written long ago
+			// if RTE did not stamp every update
+			lastWritten := metav1.NewTime(fakeClock.Now().Add(-time.Hour))
+			nrtIdle := &nrtv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "node-0",
+					CreationTimestamp: lastWritten,
+					ManagedFields: []metav1.ManagedFieldsEntry{
+						{
+							Manager: "resource-topology-exporter",
+							Time:    &lastWritten,
+						},
+					},
+					Annotations: refreshed(),
+				},
+			}
+			nrtStopping := &nrtv1alpha2.NodeResourceTopology{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "node-1",
+					CreationTimestamp: lastWritten,
+					Annotations:       refreshed(),
+				},
+			}
+			objs = append(objs, nro, nrtIdle, nrtStopping)
+
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.Kubernetes, defaultOCPVersion, objs...)
+			Expect(err).ToNot(HaveOccurred())
+			reconciler.Clock = fakeClock
+
+			reconciler.RTEManifests.DaemonSet.Status.DesiredNumberScheduled = 2
+			reconciler.RTEManifests.DaemonSet.Status.NumberReady = 2
+			reconciler.RTEManifests.DaemonSet.Status.UpdatedNumberScheduled = 2
+
+			key := client.ObjectKeyFromObject(nro)
+			reconcileAndExpectStale := func(node0, node1 bool) {
+				GinkgoHelper()
+				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+				Expect(err).ToNot(HaveOccurred())
+				for name, expected := range map[string]bool{"node-0": node0, "node-1": node1} {
+					node := &corev1.Node{}
+					Expect(reconciler.Client.Get(context.TODO(), client.ObjectKey{Name: name}, node)).To(Succeed())
+					if expected {
+						Expect(node.Labels).To(HaveKeyWithValue(nropv1.StaleNodeTopologyLabel, "true"), "node %q", name)
+					} else {
+						Expect(node.Labels).ToNot(HaveKey(nropv1.StaleNodeTopologyLabel), "node %q", name)
+					}
+				}
+			}
+			refresh := func(nrt *nrtv1alpha2.NodeResourceTopology) {
+				GinkgoHelper()
+				Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(nrt), nrt)).To(Succeed())
+				nrt.Annotations = refreshed()
+				Expect(reconciler.Client.Update(context.TODO(), nrt)).To(Succeed())
+			}
+
+			reconcileAndExpectStale(false, false)
+
+			By("having the RTE on node-1 stop updating while the one on node-0 keeps refreshing the unchanged data")
+			for i := 0; i < 6; i++ {
+				fakeClock.SetTime(fakeClock.Now().Add(10 * time.Second))
+				refresh(nrtIdle)
+			}
+			reconcileAndExpectStale(false, true)
+
+			By("having the RTE on node-1 recover")
+			refresh(nrtStopping)
+			reconcileAndExpectStale(false, false)
+		})
+
+		It("should not exclude the nodes with stale NRT objects by default", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+			node0 := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node-0",
+					Labels: nodeSel.MatchLabels,
+				},
+			}
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.Kubernetes, defaultOCPVersion, nro, node0)
+			Expect(err).ToNot(HaveOccurred())
+
+			reconciler.RTEManifests.DaemonSet.Status.DesiredNumberScheduled = 1
+			reconciler.RTEManifests.DaemonSet.Status.NumberReady = 1
+			reconciler.RTEManifests.DaemonSet.Status.UpdatedNumberScheduled = 1
+
+			key := client.ObjectKeyFromObject(nro)
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(node0), node0)).To(Succeed())
+			Expect(node0.Labels).ToNot(HaveKey(nropv1.StaleNodeTopologyLabel))
+		})
+
+		It("should report the node group as progressing while the DS is not ready", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.Kubernetes, defaultOCPVersion, nro)
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			nroUpdated := &nropv1.NUMAResourcesOperator{}
+			Expect(reconciler.Client.Get(context.TODO(), key, nroUpdated)).To(Succeed())
+			Expect(nroUpdated.Status.NodeGroups).To(HaveLen(1))
+			progressingCondition := getConditionByType(nroUpdated.Status.NodeGroups[0].Conditions, status.ConditionProgressing)
+			Expect(progressingCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(progressingCondition.Reason).To(Equal(status.ReasonDaemonSetRollingOut))
+		})
+
+		It("should create the machine config for the MachineConfigPools of the selected nodes on OpenShift", func() {
+			workerLabels := map[string]string{
+				"node-role.kubernetes.io/worker": "",
+			}
+			mcpWorker := testobjs.NewMachineConfigPool("worker", nil, &metav1.LabelSelector{MatchLabels: map[string]string{"machineconfiguration.openshift.io/role": "worker"}}, &metav1.LabelSelector{MatchLabels: workerLabels})
+			cnfLabels := map[string]string{
+				"node-role.kubernetes.io/worker-cnf": "",
+			}
+			mcpCNF := testobjs.NewMachineConfigPool("worker-cnf", nil, &metav1.LabelSelector{MatchLabels: map[string]string{"machineconfiguration.openshift.io/role": "worker-cnf"}}, &metav1.LabelSelector{MatchLabels: cnfLabels})
+
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+
+			// the node in a custom pool belongs to it and not to the worker pool, like the MCO does
+			node0 := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-0",
+					Labels: map[string]string{
+						"node-role.kubernetes.io/worker":     "",
+						"node-role.kubernetes.io/worker-cnf": "",
+						"numa-aware":                         "true",
+					},
+				},
+			}
+			node1 := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node-1",
+					Labels: workerLabels,
+				},
+			}
+
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.OpenShift, defaultOCPVersion, nro, mcpWorker, mcpCNF, node0, node1)
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{RequeueAfter: time.Minute}))
+
+			mcList := &machineconfigv1.MachineConfigList{}
+			Expect(reconciler.Client.List(context.TODO(), mcList)).To(Succeed())
+			Expect(mcList.Items).To(HaveLen(1))
+			Expect(mcList.Items[0].Name).To(Equal(objectnames.GetMachineConfigName(nro.Name, mcpCNF.Name)))
+			Expect(mcList.Items[0].Labels).To(Equal(mcpCNF.Spec.MachineConfigSelector.MatchLabels))
+
+			dsKey := client.ObjectKey{
+				Name:      objectnames.GetComponentName(nro.Name, "ng-numa"),
+				Namespace: testNamespace,
+			}
+			ds := &appsv1.DaemonSet{}
+			Expect(reconciler.Client.Get(context.TODO(), dsKey, ds)).ToNot(Succeed(), "the DS must wait for the machine config")
+
+			By("Ensure the MachineConfigPool is ready")
+			Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(mcpCNF), mcpCNF)).To(Succeed())
+			mcpCNF.Status.Configuration.Source = []corev1.ObjectReference{
+				{
+					Name: objectnames.GetMachineConfigName(nro.Name, mcpCNF.Name),
+				},
+			}
+			mcpCNF.Status.Conditions = []machineconfigv1.MachineConfigPoolCondition{
+				{
+					Type:   machineconfigv1.MachineConfigPoolUpdated,
+					Status: corev1.ConditionTrue,
+				},
+			}
+			Expect(reconciler.Client.Update(context.TODO(), mcpCNF)).To(Succeed())
+
+			result, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{RequeueAfter: 5 * time.Second}))
+			Expect(reconciler.Client.Get(context.TODO(), dsKey, ds)).To(Succeed())
+
+			nroUpdated := &nropv1.NUMAResourcesOperator{}
+			Expect(reconciler.Client.Get(context.TODO(), key, nroUpdated)).To(Succeed())
+			Expect(nroUpdated.Status.MachineConfigPools).To(HaveLen(1))
+			Expect(nroUpdated.Status.MachineConfigPools[0].Name).To(Equal(mcpCNF.Name))
+		})
+
+		It("should set the degraded condition on OpenShift if a selected node belongs to no MachineConfigPool", func() {
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node-0",
+					Labels: nodeSel.MatchLabels,
+				},
+			}
+
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.OpenShift, defaultOCPVersion, nro, node)
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			nroUpdated := &nropv1.NUMAResourcesOperator{}
+			Expect(reconciler.Client.Get(context.TODO(), key, nroUpdated)).To(Succeed())
+			degradedCondition := getConditionByType(nroUpdated.Status.Conditions, status.ConditionDegraded)
+			Expect(degradedCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degradedCondition.Reason).To(Equal(validation.NodeGroupsError))
+			Expect(degradedCondition.Message).To(ContainSubstring("node-0"))
+		})
+
+		It("should set the degraded condition if the selected nodes overlap with another node group", func() {
+			labels := map[string]string{
+				"test": "test",
+			}
+			mcp := testobjs.NewMachineConfigPool("test", labels, &metav1.LabelSelector{MatchLabels: labels}, &metav1.LabelSelector{MatchLabels: labels})
+
+			nro := testobjs.NewNUMAResourcesOperatorWithNodeSelector(objectnames.DefaultNUMAResourcesOperatorCrName, "ng-numa", nodeSel, nil)
+			nro.Spec.NodeGroups = append(nro.Spec.NodeGroups, nropv1.NodeGroup{
+				MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: labels},
+			})
+
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node-0",
+					Labels: map[string]string{
+						"test":       "test",
+						"numa-aware": "true",
+					},
+				},
+			}
+
+			reconciler, err := NewFakeNUMAResourcesOperatorReconciler(platform.OpenShift, defaultOCPVersion, nro, mcp, node)
+			Expect(err).ToNot(HaveOccurred())
+
+			key := client.ObjectKeyFromObject(nro)
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			Expect(err).ToNot(HaveOccurred())
+
+			nroUpdated := &nropv1.NUMAResourcesOperator{}
+			Expect(reconciler.Client.Get(context.TODO(), key, nroUpdated)).To(Succeed())
+			degradedCondition := getConditionByType(nroUpdated.Status.Conditions, status.ConditionDegraded)
+			Expect(degradedCondition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(degradedCondition.Reason).To(Equal(validation.NodeGroupsError))
+			Expect(degradedCondition.Message).To(ContainSubstring("node-0"))
+		})
+	})
 })
 
+func drainEvents(rec record.EventRecorder) []string {
+	fakeRecorder, ok := rec.(*record.FakeRecorder)
+	Expect(ok).To(BeTrue())
+
+	var events []string
+	for {
+		select {
+		case event := <-fakeRecorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
 func getConditionByType(conditions []metav1.Condition, conditionType string) *metav1.Condition {
 	for i := range conditions {
 		c := &conditions[i]
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	"github.com/openshift-kni/numaresources-operator/pkg/apply"
+	"github.com/openshift-kni/numaresources-operator/pkg/objectstate"
+	"github.com/openshift-kni/numaresources-operator/pkg/objectstate/compare"
+	"github.com/openshift-kni/numaresources-operator/pkg/objectstate/merge"
+)
+
+//+kubebuilder:rbac:groups="",resources=services,verbs=*
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=*
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=*
+
+// syncRTEMetrics creates the objects which let the monitoring stack scrape the RTE metrics,
+// or deletes them if the metrics are disabled.
+func (r *NUMAResourcesOperatorReconciler) syncRTEMetrics(ctx context.Context, instance *nropv1.NUMAResourcesOperator) error {
+	mf := r.RTEMetricsManifests.Clone()
+	enabled := (instance.Spec.RTEMetrics == nropv1.RTEMetricsEnabled)
+	klog.V(4).InfoS("RTE metrics sync start", "enabled", enabled)
+	defer klog.V(4).Info("RTE metrics sync stop")
+
+	objStates := []objectstate.ObjectState{
+		r.rteMetricsObjectState(ctx, &corev1.Service{}, mf.Service, merge.ServiceForUpdate),
+		r.rteMetricsObjectState(ctx, &networkingv1.NetworkPolicy{}, mf.NetworkPolicy, merge.ObjectForUpdate),
+	}
+
+	smExisting := &unstructured.Unstructured{}
+	smExisting.SetGroupVersionKind(mf.ServiceMonitor.GroupVersionKind())
+	smState := r.rteMetricsObjectState(ctx, smExisting, mf.ServiceMonitor, merge.ObjectForUpdate)
+	if meta.IsNoMatchError(smState.Error) {
+		// no monitoring stack, nothing would scrape the metrics anyway
+		klog.V(2).InfoS("ServiceMonitor API not available, skipped", "object", client.ObjectKeyFromObject(mf.ServiceMonitor))
+	} else {
+		objStates = append(objStates, smState)
+	}
+
+	for _, objState := range objStates {
+		if objState.Error != nil && !objState.IsNotFoundError() {
+			return errors.Wrapf(objState.Error, "could not get (%s) %s/%s", objState.Desired.GetObjectKind().GroupVersionKind(), objState.Desired.GetNamespace(), objState.Desired.GetName())
+		}
+		if !enabled {
+			if objState.Existing == nil || !isOwnedBy(objState.Existing, instance) {
+				continue
+			}
+			klog.InfoS("deleting", "object", client.ObjectKeyFromObject(objState.Existing))
+			if err := r.Delete(ctx, objState.Existing); client.IgnoreNotFound(err) != nil {
+				return errors.Wrapf(err, "could not delete (%s) %s/%s", objState.Desired.GetObjectKind().GroupVersionKind(), objState.Desired.GetNamespace(), objState.Desired.GetName())
+			}
+			continue
+		}
+
+		if err := controllerutil.SetControllerReference(instance, objState.Desired, r.Scheme); err != nil {
+			return errors.Wrapf(err, "failed to set controller reference to %s %s", objState.Desired.GetNamespace(), objState.Desired.GetName())
+		}
+		if _, _, err := apply.ApplyObject(ctx, r.Client, objState); err != nil {
+			return errors.Wrapf(err, "failed to apply (%s) %s/%s", objState.Desired.GetObjectKind().GroupVersionKind(), objState.Desired.GetNamespace(), objState.Desired.GetName())
+		}
+	}
+	return nil
+}
+
+func (r *NUMAResourcesOperatorReconciler) rteMetricsObjectState(ctx context.Context, existing, desired client.Object, mergeFn func(current, updated client.Object) (client.Object, error)) objectstate.ObjectState {
+	objState := objectstate.ObjectState{
+		Desired: desired,
+		Compare: compare.Object,
+		Merge:   mergeFn,
+	}
+	if objState.Error = r.Get(ctx, client.ObjectKeyFromObject(desired), existing); objState.Error == nil {
+		objState.Existing = existing
+	}
+	return objState
+}
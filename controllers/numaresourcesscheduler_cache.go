@@ -0,0 +1,246 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	nrtv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	"github.com/openshift-kni/numaresources-operator/internal/podlist"
+	"github.com/openshift-kni/numaresources-operator/internal/schedcache"
+	schedupdate "github.com/openshift-kni/numaresources-operator/pkg/objectupdate/sched"
+	"github.com/openshift-kni/numaresources-operator/pkg/status"
+)
+
+// the scheduler resyncs its cache every few seconds, so a short desync is expected.
+// We check much less often, to keep the overhead of inspecting the replicas low.
+const cacheDesyncCheckPeriod = 1 * time.Minute
+
+// maxDesyncedNodesInMessage caps the nodes listed in the CacheDesynced condition message, which must stay short
+const maxDesyncedNodesInMessage = 5
+
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+//+kubebuilder:rbac:groups="",resources=pods/proxy,verbs=get
+//+kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=topology.node.k8s.io,resources=noderesourcetopologies,verbs=get;list;watch
+
+// syncCacheStatus compares the scheduler cache state with the NRT data and updates the CacheDesynced condition.
+// Returns true if the check is enabled and should be repeated later.
+func (r *NUMAResourcesSchedulerReconciler) syncCacheStatus(ctx context.Context, instance *nropv1.NUMAResourcesScheduler, schedSpec nropv1.NUMAResourcesSchedulerSpec) bool {
+	if r.CacheSyncCheck == nil || !reportsCacheState(schedSpec.CacheResyncDebug) {
+		// the scheduler replicas don't report their cache state
+		meta.RemoveStatusCondition(&instance.Status.Conditions, status.ConditionTypeCacheDesynced)
+		return false
+	}
+
+	unsynced, unsyncedReplicas, err := r.findUnsyncedNodes(ctx, instance.Status.Deployment)
+	if err != nil {
+		// transient errors are expected (e.g. replicas restarting), keep the last known state
+		klog.ErrorS(err, "cannot check the scheduler cache state")
+		return true
+	}
+
+	cond := cacheDesyncedCondition(unsynced)
+	cond.ObservedGeneration = instance.Generation
+	prevCond := status.FindCondition(instance.Status.Conditions, status.ConditionTypeCacheDesynced)
+	transitioned := prevCond == nil || prevCond.Status != cond.Status
+	meta.SetStatusCondition(&instance.Status.Conditions, cond)
+	// the message changes with the set of desynced nodes, we want the events only when the status flips
+	if transitioned {
+		if cond.Status == metav1.ConditionTrue {
+			klog.InfoS("scheduler cache desynced", "nodes", len(unsynced))
+			r.Recorder.Event(instance, corev1.EventTypeWarning, "CacheDesynced", cond.Message)
+		} else {
+			klog.InfoS("scheduler cache synced")
+			r.Recorder.Event(instance, corev1.EventTypeNormal, "CacheSynced", cond.Message)
+		}
+	}
+
+	r.remediateCacheDesync(ctx, instance, *schedSpec.CacheDesyncRemediation, unsynced, unsyncedReplicas, time.Now())
+	return true
+}
+
+// reportsCacheState tells if the scheduler replicas report their cache state, either as files or over HTTP
+func reportsCacheState(mode *nropv1.CacheResyncDebugMode) bool {
+	return mode != nil && (*mode == nropv1.CacheResyncDebugDumpJSONFile || *mode == nropv1.CacheResyncDebugServeHTTP)
+}
+
+// remediateCacheDesync applies the remediation action if the cache desync persisted beyond the threshold,
+// no more often than the configured minimum interval.
+func (r *NUMAResourcesSchedulerReconciler) remediateCacheDesync(ctx context.Context, instance *nropv1.NUMAResourcesScheduler, rem nropv1.CacheDesyncRemediation, unsynced map[string]sets.Set[string], unsyncedReplicas []corev1.Pod, now time.Time) {
+	mode := *rem.Mode
+	if mode == nropv1.CacheDesyncRemediationNone || len(unsynced) == 0 {
+		return
+	}
+	cond := status.FindCondition(instance.Status.Conditions, status.ConditionTypeCacheDesynced)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		return
+	}
+	if desyncedSince := now.Sub(cond.LastTransitionTime.Time); desyncedSince < rem.Threshold.Duration {
+		klog.V(4).InfoS("cache desync remediation pending", "desyncedSince", desyncedSince, "threshold", rem.Threshold.Duration)
+		return
+	}
+	if last := instance.Status.LastCacheDesyncRemediation; last != nil && now.Sub(last.Time.Time) < rem.MinInterval.Duration {
+		klog.V(2).InfoS("cache desync remediation rate limited", "lastRemediation", last.Time, "minInterval", rem.MinInterval.Duration)
+		return
+	}
+
+	nodeNames := sets.List(sets.KeySet(unsynced))
+	switch mode {
+	case nropv1.CacheDesyncRemediationRestartPod:
+		for idx := range unsyncedReplicas {
+			pod := &unsyncedReplicas[idx]
+			if err := r.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+				klog.ErrorS(err, "cannot delete scheduler replica", "pod", client.ObjectKeyFromObject(pod))
+				r.Recorder.Eventf(instance, corev1.EventTypeWarning, "CacheDesyncRemediationFailed", "Failed to delete scheduler replica %s/%s: %v", pod.Namespace, pod.Name, err)
+				continue
+			}
+			klog.InfoS("deleted scheduler replica to remediate the cache desync", "pod", client.ObjectKeyFromObject(pod), "nodes", nodeNames)
+			r.Recorder.Eventf(instance, corev1.EventTypeNormal, "CacheDesyncRemediation", "Deleted scheduler replica %s/%s to remediate the cache desync on nodes %s", pod.Namespace, pod.Name, strings.Join(nodeNames, ","))
+		}
+	case nropv1.CacheDesyncRemediationForceResync:
+		if err := r.requestCacheResync(ctx, instance.Status.Deployment, now); err != nil {
+			klog.ErrorS(err, "cannot request the scheduler cache resync")
+			r.Recorder.Eventf(instance, corev1.EventTypeWarning, "CacheDesyncRemediationFailed", "Failed to request the scheduler cache resync: %v", err)
+			return
+		}
+		klog.InfoS("requested the scheduler cache resync to remediate the cache desync", "nodes", nodeNames)
+		r.Recorder.Eventf(instance, corev1.EventTypeNormal, "CacheDesyncRemediation", "Requested the scheduler cache resync to remediate the cache desync on nodes %s", strings.Join(nodeNames, ","))
+	}
+
+	instance.Status.LastCacheDesyncRemediation = &nropv1.CacheDesyncRemediationStatus{
+		Mode:  mode,
+		Time:  metav1.NewTime(now),
+		Nodes: nodeNames,
+	}
+}
+
+// requestCacheResync annotates the scheduler pod template to roll out all the replicas.
+// The annotation is rendered again at each reconcile from the status, see schedupdate.DeploymentCacheResyncSettings.
+func (r *NUMAResourcesSchedulerReconciler) requestCacheResync(ctx context.Context, dpKey nropv1.NamespacedName, now time.Time) error {
+	dp := &appsv1.Deployment{}
+	if err := r.Get(ctx, client.ObjectKey(dpKey), dp); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(dp.DeepCopy())
+	schedupdate.DeploymentCacheResyncSettings(dp, &nropv1.CacheDesyncRemediationStatus{
+		Mode: nropv1.CacheDesyncRemediationForceResync,
+		Time: metav1.NewTime(now),
+	})
+	return r.Patch(ctx, dp, patch)
+}
+
+// findUnsyncedNodes returns the nodes whose cache is not in sync in any scheduler replica, with the detected pods,
+// and the replicas which have a desynced cache.
+func (r *NUMAResourcesSchedulerReconciler) findUnsyncedNodes(ctx context.Context, dpKey nropv1.NamespacedName) (map[string]sets.Set[string], []corev1.Pod, error) {
+	nrts := &nrtv1alpha2.NodeResourceTopologyList{}
+	if err := r.List(ctx, nrts); err != nil {
+		return nil, nil, err
+	}
+	var nodeNames []string
+	for _, nrt := range nrts.Items {
+		nodeNames = append(nodeNames, nrt.Name)
+	}
+
+	dp := appsv1.Deployment{}
+	if err := r.Get(ctx, client.ObjectKey(dpKey), &dp); err != nil {
+		return nil, nil, err
+	}
+	pods, err := podlist.With(r.Client).ByDeployment(ctx, dp)
+	if err != nil {
+		return nil, nil, err
+	}
+	pods, err = schedcache.LeaderPods(ctx, r.Client, &dp, pods)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unsynced := make(map[string]sets.Set[string])
+	var unsyncedReplicas []corev1.Pod
+	for idx := range pods {
+		pod := &pods[idx]
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		replicaUnsynced, err := r.CacheSyncCheck(ctx, pod, nodeNames)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to check the cache of replica %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+		if len(replicaUnsynced) == 0 {
+			continue
+		}
+		unsyncedReplicas = append(unsyncedReplicas, *pod)
+		for nodeName, detectedPods := range replicaUnsynced {
+			unsynced[nodeName] = detectedPods.Union(unsynced[nodeName])
+		}
+	}
+	return unsynced, unsyncedReplicas, nil
+}
+
+func cacheDesyncedCondition(unsynced map[string]sets.Set[string]) metav1.Condition {
+	if len(unsynced) == 0 {
+		return metav1.Condition{
+			Type:    status.ConditionTypeCacheDesynced,
+			Status:  metav1.ConditionFalse,
+			Reason:  status.ReasonCacheNodesSynced,
+			Message: "the scheduler cache is in sync on all the nodes",
+		}
+	}
+
+	nodeNames := make([]string, 0, len(unsynced))
+	for nodeName := range unsynced {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+
+	listed := nodeNames
+	if len(listed) > maxDesyncedNodesInMessage {
+		listed = listed[:maxDesyncedNodesInMessage]
+	}
+	items := make([]string, 0, len(listed))
+	for _, nodeName := range listed {
+		items = append(items, fmt.Sprintf("%s (detected pods: %d)", nodeName, unsynced[nodeName].Len()))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "the scheduler cache is not in sync on %d nodes: %s", len(nodeNames), strings.Join(items, ", "))
+	if more := len(nodeNames) - len(listed); more > 0 {
+		fmt.Fprintf(&sb, " and %d more", more)
+	}
+	return metav1.Condition{
+		Type:    status.ConditionTypeCacheDesynced,
+		Status:  metav1.ConditionTrue,
+		Reason:  status.ReasonCacheNodesDesynced,
+		Message: sb.String(),
+	}
+}
@@ -19,30 +19,40 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/pkg/errors"
 
+	"github.com/k8stopologyawareschedwg/deployer/pkg/deployer/platform"
 	k8swgmanifests "github.com/k8stopologyawareschedwg/deployer/pkg/manifests"
+	k8swgobjupdate "github.com/k8stopologyawareschedwg/deployer/pkg/objectupdate"
 
 	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	"github.com/openshift-kni/numaresources-operator/internal/events"
+	"github.com/openshift-kni/numaresources-operator/internal/metrics"
+	"github.com/openshift-kni/numaresources-operator/internal/podlist"
 	"github.com/openshift-kni/numaresources-operator/internal/relatedobjects"
+	"github.com/openshift-kni/numaresources-operator/internal/schedcache"
 	"github.com/openshift-kni/numaresources-operator/pkg/apply"
 	"github.com/openshift-kni/numaresources-operator/pkg/hash"
 	"github.com/openshift-kni/numaresources-operator/pkg/loglevel"
@@ -51,6 +61,7 @@ import (
 	"github.com/openshift-kni/numaresources-operator/pkg/objectnames"
 	schedupdate "github.com/openshift-kni/numaresources-operator/pkg/objectupdate/sched"
 	"github.com/openshift-kni/numaresources-operator/pkg/status"
+	"github.com/openshift-kni/numaresources-operator/pkg/validation"
 )
 
 const (
@@ -63,12 +74,26 @@ type NUMAResourcesSchedulerReconciler struct {
 	Scheme             *runtime.Scheme
 	SchedulerManifests schedmanifests.Manifests
 	Namespace          string
+	Recorder           record.EventRecorder
+	// CacheSyncCheck inspects the cache of the scheduler replicas. Leave nil to disable the cache desync detection.
+	CacheSyncCheck schedcache.ReplicaSyncFunc
+	// StatusServerImage is the image running the sidecar which serves the scheduler cache state over HTTP, usually the operator image.
+	StatusServerImage string
+	Platform          platform.Platform
+
+	// the same state is observed over and over while waiting for the cluster to settle,
+	// so the events about it must be recorded only once
+	events events.Deduplicator
 }
 
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=*
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=*
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=*
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=*
 //+kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=*
+//+kubebuilder:rbac:groups="",resources=services,verbs=*
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=*
 //+kubebuilder:rbac:groups=nodetopology.openshift.io,resources=numaresourcesschedulers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=nodetopology.openshift.io,resources=numaresourcesschedulers/status,verbs=get;update;patch
@@ -94,6 +119,7 @@ func (r *NUMAResourcesSchedulerReconciler) Reconcile(ctx context.Context, req ct
 			// Request object not found, could have been deleted after reconcile request.
 			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
 			// Return and don't requeue
+			metrics.UpdateSchedulerAvailableMetric(false)
 			return ctrl.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -102,14 +128,24 @@ func (r *NUMAResourcesSchedulerReconciler) Reconcile(ctx context.Context, req ct
 
 	if req.Name != objectnames.DefaultNUMAResourcesSchedulerCrName {
 		message := fmt.Sprintf("incorrect NUMAResourcesScheduler resource name: %s", instance.Name)
-		return ctrl.Result{}, r.updateStatus(ctx, instance, status.ConditionDegraded, conditionTypeIncorrectNUMAResourcesSchedulerResourceName, message)
+		r.events.Eventf(r.Recorder, instance, corev1.EventTypeWarning, conditionTypeIncorrectNUMAResourcesSchedulerResourceName, "Invalid configuration: %s", message)
+		return ctrl.Result{}, r.updateStatus(ctx, instance, status.Degraded(conditionTypeIncorrectNUMAResourcesSchedulerResourceName, message))
 	}
 
-	result, condition, err := r.reconcileResource(ctx, instance)
+	if err := validation.SchedulerScoringStrategies(instance.Spec); err != nil {
+		r.events.Eventf(r.Recorder, instance, corev1.EventTypeWarning, validation.SchedulerSpecError, "Invalid configuration: %v", err)
+		return ctrl.Result{}, r.updateStatus(ctx, instance, status.Degraded(validation.SchedulerSpecError, err.Error()))
+	}
+	if err := validation.SchedulerPlatform(instance.Spec, r.Platform); err != nil {
+		r.events.Eventf(r.Recorder, instance, corev1.EventTypeWarning, validation.SchedulerSpecError, "Invalid configuration: %v", err)
+		return ctrl.Result{}, r.updateStatus(ctx, instance, status.Degraded(validation.SchedulerSpecError, err.Error()))
+	}
+	// the validation failures must be reported again if they come back after a fix
+	r.events.Forget(instance, validation.SchedulerSpecError)
 
-	reason := condition // TODO: use proper reason
-	if err := r.updateStatus(ctx, instance, condition, reason, messageFromError(err)); err != nil {
-		klog.InfoS("Failed to update numaresourcesscheduler status", "Desired condition", condition, "error", err)
+	result, cond, err := r.reconcileResource(ctx, instance)
+	if err := r.updateStatus(ctx, instance, cond); err != nil {
+		klog.InfoS("Failed to update numaresourcesscheduler status", "Desired condition", cond.Type, "error", err)
 	}
 
 	return result, err
@@ -126,34 +162,67 @@ func (r *NUMAResourcesSchedulerReconciler) SetupWithManager(mgr ctrl.Manager) er
 		},
 	}
 
+	// the replicas state is reported in status
+	deploymentReplicasChanged := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			dpOld, okOld := e.ObjectOld.(*appsv1.Deployment)
+			dpNew, okNew := e.ObjectNew.(*appsv1.Deployment)
+			if !okOld || !okNew {
+				return false
+			}
+			return dpNew.Status.ReadyReplicas != dpOld.Status.ReadyReplicas ||
+				dpNew.Status.UpdatedReplicas != dpOld.Status.UpdatedReplicas ||
+				dpNew.Status.Replicas != dpOld.Status.Replicas
+		},
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&nropv1.NUMAResourcesScheduler{}).
 		Owns(&rbacv1.ClusterRole{}, builder.WithPredicates(p)).
 		Owns(&rbacv1.ClusterRoleBinding{}, builder.WithPredicates(p)).
 		Owns(&corev1.ServiceAccount{}, builder.WithPredicates(p)).
 		Owns(&corev1.ConfigMap{}, builder.WithPredicates(p)).
-		Owns(&appsv1.Deployment{}, builder.WithPredicates(p)).
+		Owns(&appsv1.Deployment{}, builder.WithPredicates(predicate.Or(p, deploymentReplicasChanged))).
+		Owns(&policyv1.PodDisruptionBudget{}, builder.WithPredicates(p)).
+		Owns(&corev1.Service{}, builder.WithPredicates(p)).
+		Watches(&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.configOverlayToNUMAResourcesScheduler),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		Complete(r)
 }
 
-func (r *NUMAResourcesSchedulerReconciler) reconcileResource(ctx context.Context, instance *nropv1.NUMAResourcesScheduler) (reconcile.Result, string, error) {
+func (r *NUMAResourcesSchedulerReconciler) reconcileResource(ctx context.Context, instance *nropv1.NUMAResourcesScheduler) (reconcile.Result, status.ConditionInfo, error) {
 	schedStatus, err := r.syncNUMASchedulerResources(ctx, instance)
 	if err != nil {
-		return ctrl.Result{}, status.ConditionDegraded, errors.Wrapf(err, "FailedSchedulerSync")
+		return ctrl.Result{}, status.DegradedFromError(err, status.ReasonSchedulerSyncFailed), err
 	}
 
+	schedStatus.Conditions = instance.Status.Conditions
+	schedStatus.LastCacheDesyncRemediation = instance.Status.LastCacheDesyncRemediation
 	instance.Status = schedStatus
 	instance.Status.RelatedObjects = relatedobjects.Scheduler(r.Namespace, instance.Status.Deployment)
 
+	replicas, err := replicasStatus(ctx, r.Client, schedStatus.Deployment)
+	if err != nil {
+		// the replicas state is informational, we don't want to fail the reconcile because of it
+		klog.ErrorS(err, "cannot get the scheduler replicas state", "deployment", schedStatus.Deployment.String())
+	}
+	instance.Status.Replicas = replicas
+
 	ok, err := isDeploymentRunning(ctx, r.Client, schedStatus.Deployment)
 	if err != nil {
-		return ctrl.Result{}, status.ConditionDegraded, err
+		return ctrl.Result{}, status.DegradedFromError(err, status.ReasonSchedulerSyncFailed), err
 	}
 	if !ok {
-		return ctrl.Result{RequeueAfter: 5 * time.Second}, status.ConditionProgressing, nil
+		message := fmt.Sprintf("waiting for the scheduler deployment %s to be available", schedStatus.Deployment.String())
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, status.Progressing(status.ReasonDeploymentNotReady, message), nil
 	}
 
-	return ctrl.Result{}, status.ConditionAvailable, nil
+	if r.syncCacheStatus(ctx, instance, instance.Spec.Normalize()) {
+		// the cache state changes without any object change we can watch
+		return ctrl.Result{RequeueAfter: cacheDesyncCheckPeriod}, status.Available(), nil
+	}
+	return ctrl.Result{}, status.Available(), nil
 }
 
 func isDeploymentRunning(ctx context.Context, c client.Client, key nropv1.NamespacedName) (bool, error) {
@@ -170,44 +239,117 @@ func isDeploymentRunning(ctx context.Context, c client.Client, key nropv1.Namesp
 	return false, nil
 }
 
+func replicasStatus(ctx context.Context, c client.Client, key nropv1.NamespacedName) ([]nropv1.SchedulerReplicaStatus, error) {
+	dp := appsv1.Deployment{}
+	if err := c.Get(ctx, client.ObjectKey(key), &dp); err != nil {
+		return nil, err
+	}
+	pods, err := podlist.With(c).ByDeployment(ctx, dp)
+	if err != nil {
+		return nil, err
+	}
+
+	var replicas []nropv1.SchedulerReplicaStatus
+	for idx := range pods {
+		pod := &pods[idx] // shortcut
+		replica := nropv1.SchedulerReplicaStatus{
+			Name:     pod.Name,
+			NodeName: pod.Spec.NodeName,
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == corev1.PodReady {
+				replica.Ready = cond.Status == corev1.ConditionTrue
+				break
+			}
+		}
+		replicas = append(replicas, replica)
+	}
+	sort.Slice(replicas, func(i, j int) bool {
+		return replicas[i].Name < replicas[j].Name
+	})
+	return replicas, nil
+}
+
 func (r *NUMAResourcesSchedulerReconciler) syncNUMASchedulerResources(ctx context.Context, instance *nropv1.NUMAResourcesScheduler) (nropv1.NUMAResourcesSchedulerStatus, error) {
 	klog.V(4).Info("SchedulerSync start")
 	defer klog.V(4).Info("SchedulerSync stop")
 
+	// always render from the builtin manifests, so the settings removed from the spec revert to their defaults
+	mf := r.SchedulerManifests.Clone()
 	schedSpec := instance.Spec.Normalize()
-	cacheResyncPeriod := unpackAPIResyncPeriod(schedSpec.CacheResyncPeriod)
-	params := configParamsFromSchedSpec(schedSpec, cacheResyncPeriod)
 
-	schedName, ok := schedstate.SchedulerNameFromObject(r.SchedulerManifests.ConfigMap)
+	schedName, ok := schedstate.SchedulerNameFromObject(mf.ConfigMap)
 	if !ok {
 		err := fmt.Errorf("missing scheduler name in builtin config map")
 		klog.V(2).ErrorS(err, "cannot find the scheduler profile name")
-		return nropv1.NUMAResourcesSchedulerStatus{}, err
+		return nropv1.NUMAResourcesSchedulerStatus{}, status.NewReasonedError(status.ReasonSchedulerConfigRenderFailed, err)
 	}
 	klog.V(4).InfoS("detected scheduler profile", "profileName", schedName)
 
-	if err := schedupdate.SchedulerConfig(r.SchedulerManifests.ConfigMap, schedName, &params); err != nil {
+	profiles, err := schedulerProfiles(schedSpec, schedName)
+	if err != nil {
+		return nropv1.NUMAResourcesSchedulerStatus{}, status.NewReasonedError(status.ReasonSchedulerConfigRenderFailed, err)
+	}
+	allParams := make([]k8swgmanifests.ConfigParams, 0, len(profiles))
+	for idx := range profiles {
+		profile := &profiles[idx] // shortcut
+		cacheResyncPeriod := unpackAPIResyncPeriod(profile.CacheResyncPeriod)
+		profile.CacheResyncPeriod = &metav1.Duration{
+			Duration: cacheResyncPeriod,
+		}
+		allParams = append(allParams, configParamsFromProfile(*profile, cacheResyncPeriod))
+	}
+
+	if err := schedupdate.SchedulerProfilesConfig(mf.ConfigMap, schedName, allParams); err != nil {
+		return nropv1.NUMAResourcesSchedulerStatus{}, status.NewReasonedError(status.ReasonSchedulerConfigRenderFailed, err)
+	}
+	for _, profile := range profiles {
+		if profile.ScoringStrategy.Type != nropv1.RequestedToCapacityRatio {
+			continue
+		}
+		if err := schedupdate.SchedulerRequestedToCapacityRatioConfig(mf.ConfigMap, profile.SchedulerName, profile.ScoringStrategy.RequestedToCapacityRatio); err != nil {
+			return nropv1.NUMAResourcesSchedulerStatus{}, status.NewReasonedError(status.ReasonSchedulerConfigRenderFailed, err)
+		}
+	}
+
+	// the replicas need to elect a leader, otherwise they would all schedule pods concurrently
+	replicas := *schedSpec.Replicas
+	lease := schedstate.LeaderElectionLease(mf.Deployment)
+	if err := schedupdate.SchedulerLeaderElectionConfig(mf.ConfigMap, replicas > 1, lease); err != nil {
+		return nropv1.NUMAResourcesSchedulerStatus{}, status.NewReasonedError(status.ReasonSchedulerConfigRenderFailed, err)
+	}
+
+	// must be the last change to the scheduler config, to detect the conflicts with the operator-managed fields
+	if err := r.applySchedulerConfigOverlay(ctx, schedSpec.ConfigOverlayRef, mf.ConfigMap); err != nil {
 		return nropv1.NUMAResourcesSchedulerStatus{}, err
 	}
 
 	schedStatus := nropv1.NUMAResourcesSchedulerStatus{
-		SchedulerName: schedSpec.SchedulerName,
-		CacheResyncPeriod: &metav1.Duration{
-			Duration: cacheResyncPeriod,
-		},
+		SchedulerName:     schedSpec.SchedulerName,
+		CacheResyncPeriod: profiles[0].CacheResyncPeriod.DeepCopy(),
+		Profiles:          profiles,
+		Config:            schedSpec.DeepCopy(),
 	}
 
-	cmHash := hash.ConfigMapData(r.SchedulerManifests.ConfigMap)
-	schedupdate.DeploymentImageSettings(r.SchedulerManifests.Deployment, schedSpec.SchedulerImage)
-	schedupdate.DeploymentConfigMapSettings(r.SchedulerManifests.Deployment, r.SchedulerManifests.ConfigMap.Name, cmHash)
-	if err := loglevel.UpdatePodSpec(&r.SchedulerManifests.Deployment.Spec.Template.Spec, "", schedSpec.LogLevel); err != nil {
-		return schedStatus, err
+	cmHash := hash.ConfigMapData(mf.ConfigMap)
+	mf.Deployment.Spec.Replicas = &replicas
+	schedupdate.DeploymentImageSettings(mf.Deployment, schedSpec.SchedulerImage)
+	schedupdate.DeploymentConfigMapSettings(mf.Deployment, mf.ConfigMap.Name, cmHash)
+	if err := loglevel.UpdatePodSpec(&mf.Deployment.Spec.Template.Spec, "", schedSpec.LogLevel); err != nil {
+		return schedStatus, status.NewReasonedError(status.ReasonSchedulerConfigRenderFailed, err)
 	}
 
-	schedupdate.DeploymentEnvVarSettings(r.SchedulerManifests.Deployment, schedSpec)
+	schedupdate.DeploymentPlacementSettings(mf.Deployment, schedSpec)
+	schedupdate.DeploymentResourcesSettings(mf.Deployment, schedSpec.Resources)
+	schedupdate.DeploymentEnvVarSettings(mf.Deployment, schedSpec)
+	if *schedSpec.CacheResyncDebug == nropv1.CacheResyncDebugServeHTTP && r.StatusServerImage == "" {
+		return schedStatus, status.NewReasonedError(status.ReasonSchedulerConfigRenderFailed, fmt.Errorf("cannot serve the scheduler cache state: missing status server image"))
+	}
+	schedupdate.DeploymentStatusServerSettings(mf.Deployment, schedSpec, r.StatusServerImage)
+	schedupdate.DeploymentCacheResyncSettings(mf.Deployment, instance.Status.LastCacheDesyncRemediation)
 
-	existing := schedstate.FromClient(ctx, r.Client, r.SchedulerManifests)
-	for _, objState := range existing.State(r.SchedulerManifests) {
+	existing := schedstate.FromClient(ctx, r.Client, mf)
+	for _, objState := range existing.State(mf) {
 		if err := controllerutil.SetControllerReference(instance, objState.Desired, r.Scheme); err != nil {
 			return schedStatus, errors.Wrapf(err, "Failed to set controller reference to %s %s", objState.Desired.GetNamespace(), objState.Desired.GetName())
 		}
@@ -222,12 +364,88 @@ func (r *NUMAResourcesSchedulerReconciler) syncNUMASchedulerResources(ctx contex
 		if schedName, ok := schedstate.SchedulerNameFromObject(obj); ok {
 			schedStatus.SchedulerName = schedName
 		}
+		if dp, ok := obj.(*appsv1.Deployment); ok {
+			schedStatus.DeploymentSettings = deploymentSettingsFromDeployment(dp)
+			schedStatus.ConfigHash = dp.Spec.Template.Annotations[hash.ConfigMapAnnotation]
+			if prevHash := instance.Status.ConfigHash; prevHash != "" && prevHash != schedStatus.ConfigHash {
+				r.events.Eventf(r.Recorder, instance, corev1.EventTypeNormal, status.ReasonSchedulerConfigUpdated, "Scheduler configuration changed, rolling out %s (previous %s)", schedStatus.ConfigHash, prevHash)
+			}
+		}
+	}
+
+	if err := r.syncPodDisruptionBudget(ctx, instance, mf, existing, replicas); err != nil {
+		return schedStatus, err
+	}
+	if err := r.syncStatusService(ctx, instance, mf, existing, *schedSpec.CacheResyncDebug == nropv1.CacheResyncDebugServeHTTP); err != nil {
+		return schedStatus, err
 	}
 	return schedStatus, nil
 }
 
-func (r *NUMAResourcesSchedulerReconciler) updateStatus(ctx context.Context, sched *nropv1.NUMAResourcesScheduler, condition string, reason string, message string) error {
-	sched.Status.Conditions, _ = status.GetUpdatedConditions(sched.Status.Conditions, condition, reason, message)
+// syncStatusService creates the service whose service-serving certificate secures the cache state server
+// of the scheduler replicas, or deletes it if the cache state is not served over HTTP.
+func (r *NUMAResourcesSchedulerReconciler) syncStatusService(ctx context.Context, instance *nropv1.NUMAResourcesScheduler, mf schedmanifests.Manifests, existing schedstate.ExistingManifests, serveHTTP bool) error {
+	if !serveHTTP {
+		svc := existing.Existing.StatusService
+		if svc == nil {
+			return nil
+		}
+		klog.InfoS("deleting", "object", client.ObjectKeyFromObject(svc))
+		return client.IgnoreNotFound(r.Delete(ctx, svc))
+	}
+
+	objState := existing.StatusServiceState(mf)
+	if err := controllerutil.SetControllerReference(instance, objState.Desired, r.Scheme); err != nil {
+		return errors.Wrapf(err, "Failed to set controller reference to %s %s", objState.Desired.GetNamespace(), objState.Desired.GetName())
+	}
+	if _, _, err := apply.ApplyObject(ctx, r.Client, objState); err != nil {
+		return errors.Wrapf(err, "could not apply (%s) %s/%s", objState.Desired.GetObjectKind().GroupVersionKind(), objState.Desired.GetNamespace(), objState.Desired.GetName())
+	}
+	return nil
+}
+
+// syncPodDisruptionBudget keeps at least a scheduler replica running during voluntary disruptions.
+// A single replica can't tolerate any disruption, so in that case the budget would only block the node drains.
+func (r *NUMAResourcesSchedulerReconciler) syncPodDisruptionBudget(ctx context.Context, instance *nropv1.NUMAResourcesScheduler, mf schedmanifests.Manifests, existing schedstate.ExistingManifests, replicas int32) error {
+	if replicas <= 1 {
+		pdb := existing.Existing.PodDisruptionBudget
+		if pdb == nil {
+			return nil
+		}
+		klog.InfoS("deleting", "object", client.ObjectKeyFromObject(pdb))
+		return client.IgnoreNotFound(r.Delete(ctx, pdb))
+	}
+
+	objState := existing.PodDisruptionBudgetState(mf)
+	if err := controllerutil.SetControllerReference(instance, objState.Desired, r.Scheme); err != nil {
+		return errors.Wrapf(err, "Failed to set controller reference to %s %s", objState.Desired.GetNamespace(), objState.Desired.GetName())
+	}
+	if _, _, err := apply.ApplyObject(ctx, r.Client, objState); err != nil {
+		return errors.Wrapf(err, "could not apply (%s) %s/%s", objState.Desired.GetObjectKind().GroupVersionKind(), objState.Desired.GetNamespace(), objState.Desired.GetName())
+	}
+	return nil
+}
+
+func deploymentSettingsFromDeployment(dp *appsv1.Deployment) *nropv1.SchedulerDeploymentSettings {
+	podSpec := &dp.Spec.Template.Spec // shortcut
+	settings := nropv1.SchedulerDeploymentSettings{
+		NodeSelector:      podSpec.NodeSelector,
+		Tolerations:       podSpec.Tolerations,
+		Affinity:          podSpec.Affinity,
+		PriorityClassName: podSpec.PriorityClassName,
+	}
+	if cnt := k8swgobjupdate.FindContainerByName(podSpec.Containers, schedupdate.MainContainerName); cnt != nil {
+		settings.Resources = cnt.Resources
+	}
+	return settings.DeepCopy()
+}
+
+func (r *NUMAResourcesSchedulerReconciler) updateStatus(ctx context.Context, sched *nropv1.NUMAResourcesScheduler, cond status.ConditionInfo) error {
+	metrics.UpdateReconcileMetric(metrics.ControllerNUMAResourcesScheduler, cond)
+	metrics.UpdateSchedulerAvailableMetric(cond.Type == status.ConditionAvailable)
+	upgradeable := schedulerUpgradeableCondition(ctx, r.Client, sched, cond)
+	sched.Status.Conditions, _ = status.GetUpdatedConditionsWithUpgradeable(sched.Status.Conditions, cond.Type, cond.Reason, cond.Message, upgradeable, sched.Generation)
+	sched.Status.ObservedGeneration = sched.Generation
 	if err := r.Client.Status().Update(ctx, sched); err != nil {
 		return errors.Wrapf(err, "could not update status for object %s", client.ObjectKeyFromObject(sched))
 	}
@@ -240,11 +458,39 @@ func unpackAPIResyncPeriod(reconcilePeriod *metav1.Duration) time.Duration {
 	return period
 }
 
-func configParamsFromSchedSpec(schedSpec nropv1.NUMAResourcesSchedulerSpec, cacheResyncPeriod time.Duration) k8swgmanifests.ConfigParams {
+// schedulerProfiles returns the main profile, defined by the top level settings, followed by the additional profiles.
+// The spec must be normalized.
+func schedulerProfiles(schedSpec nropv1.NUMAResourcesSchedulerSpec, defaultSchedName string) ([]nropv1.SchedulerProfile, error) {
+	mainProfile := nropv1.SchedulerProfile{
+		SchedulerName:        schedSpec.SchedulerName,
+		CacheResyncPeriod:    schedSpec.CacheResyncPeriod,
+		SchedulerInformer:    schedSpec.SchedulerInformer,
+		CacheResyncDetection: schedSpec.CacheResyncDetection,
+		ScoringStrategy:      schedSpec.ScoringStrategy,
+	}
+	if mainProfile.SchedulerName == "" {
+		mainProfile.SchedulerName = defaultSchedName
+	}
+
+	profiles := append([]nropv1.SchedulerProfile{mainProfile}, schedSpec.Profiles...)
+	seen := make(map[string]bool, len(profiles))
+	for _, profile := range profiles {
+		if profile.SchedulerName == "" {
+			return nil, fmt.Errorf("missing scheduler name in scheduler profile")
+		}
+		if seen[profile.SchedulerName] {
+			return nil, fmt.Errorf("duplicate scheduler profile %q", profile.SchedulerName)
+		}
+		seen[profile.SchedulerName] = true
+	}
+	return profiles, nil
+}
+
+func configParamsFromProfile(profile nropv1.SchedulerProfile, cacheResyncPeriod time.Duration) k8swgmanifests.ConfigParams {
 	resyncPeriod := int64(cacheResyncPeriod.Seconds())
 
 	params := k8swgmanifests.ConfigParams{
-		ProfileName: schedSpec.SchedulerName,
+		ProfileName: profile.SchedulerName,
 		Cache: &k8swgmanifests.ConfigCacheParams{
 			ResyncPeriodSeconds: &resyncPeriod,
 		},
@@ -255,29 +501,32 @@ func configParamsFromSchedSpec(schedSpec nropv1.NUMAResourcesSchedulerSpec, cach
 	var resyncMethod string = k8swgmanifests.CacheResyncAutodetect
 	var informerMode string
 	var scoringStrategyType string
-	if *schedSpec.CacheResyncDetection == nropv1.CacheResyncDetectionRelaxed {
+	if *profile.CacheResyncDetection == nropv1.CacheResyncDetectionRelaxed {
 		foreignPodsDetect = k8swgmanifests.ForeignPodsDetectOnlyExclusiveResources
 	} else {
 		foreignPodsDetect = k8swgmanifests.ForeignPodsDetectAll
 	}
-	if *schedSpec.SchedulerInformer == k8swgmanifests.CacheInformerDedicated {
+	if *profile.SchedulerInformer == k8swgmanifests.CacheInformerDedicated {
 		informerMode = k8swgmanifests.CacheInformerDedicated
 	} else {
 		informerMode = k8swgmanifests.CacheInformerShared
 	}
-	if schedSpec.ScoringStrategy.Type == nropv1.LeastAllocated {
+	if profile.ScoringStrategy.Type == nropv1.LeastAllocated {
 		scoringStrategyType = k8swgmanifests.ScoringStrategyLeastAllocated
-	} else if schedSpec.ScoringStrategy.Type == nropv1.BalancedAllocation {
+	} else if profile.ScoringStrategy.Type == nropv1.BalancedAllocation {
 		scoringStrategyType = k8swgmanifests.ScoringStrategyBalancedAllocation
-	} else if schedSpec.ScoringStrategy.Type == nropv1.MostAllocated {
+	} else if profile.ScoringStrategy.Type == nropv1.MostAllocated {
 		scoringStrategyType = k8swgmanifests.ScoringStrategyMostAllocated
+	} else if profile.ScoringStrategy.Type == nropv1.RequestedToCapacityRatio {
+		// the deployer doesn't support this strategy, see schedupdate.SchedulerRequestedToCapacityRatioConfig
+		scoringStrategyType = ""
 	} else {
 		scoringStrategyType = k8swgmanifests.ScoringStrategyLeastAllocated
 	}
 	params.ScoringStrategy.Type = scoringStrategyType
 
 	var resources []k8swgmanifests.ResourceSpecParams
-	for _, resource := range schedSpec.ScoringStrategy.Resources {
+	for _, resource := range profile.ScoringStrategy.Resources {
 		resources = append(resources, k8swgmanifests.ResourceSpecParams{
 			Name:   resource.Name,
 			Weight: resource.Weight,
@@ -18,6 +18,8 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/onsi/ginkgo/v2"
@@ -27,24 +29,34 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
 
+	"github.com/k8stopologyawareschedwg/deployer/pkg/deployer/platform"
 	depmanifests "github.com/k8stopologyawareschedwg/deployer/pkg/manifests"
 	depobjupdate "github.com/k8stopologyawareschedwg/deployer/pkg/objectupdate"
+	nrtv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
 	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
 	"github.com/openshift-kni/numaresources-operator/pkg/hash"
 	schedmanifests "github.com/openshift-kni/numaresources-operator/pkg/numaresourcesscheduler/manifests/sched"
 	"github.com/openshift-kni/numaresources-operator/pkg/numaresourcesscheduler/objectstate/sched"
 	schedupdate "github.com/openshift-kni/numaresources-operator/pkg/objectupdate/sched"
 	"github.com/openshift-kni/numaresources-operator/pkg/status"
+	"github.com/openshift-kni/numaresources-operator/pkg/validation"
 
 	testobjs "github.com/openshift-kni/numaresources-operator/internal/objects"
 )
@@ -63,6 +75,8 @@ func NewFakeNUMAResourcesSchedulerReconciler(initObjects ...runtime.Object) (*NU
 		Scheme:             scheme.Scheme,
 		SchedulerManifests: schedMf,
 		Namespace:          testNamespace,
+		Recorder:           record.NewFakeRecorder(bufferSize),
+		Platform:           platform.OpenShift,
 	}, nil
 }
 
@@ -131,6 +145,32 @@ var _ = ginkgo.Describe("Test NUMAResourcesScheduler Reconcile", func() {
 			gomega.Expect(reconciler.Client.Get(context.TODO(), key, dp)).ToNot(gomega.HaveOccurred())
 		})
 
+		ginkgo.It("should allow the scheduler service account to review the cache state requests", func() {
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			sa := reconciler.SchedulerManifests.ServiceAccount
+			crbKey := client.ObjectKeyFromObject(reconciler.SchedulerManifests.ClusterRoleBindingAuthDelegator)
+			crb := &rbacv1.ClusterRoleBinding{}
+			gomega.Expect(reconciler.Client.Get(context.TODO(), crbKey, crb)).To(gomega.Succeed())
+			gomega.Expect(crb.RoleRef).To(gomega.Equal(rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     "system:auth-delegator",
+			}))
+			gomega.Expect(crb.Subjects).To(gomega.ConsistOf(rbacv1.Subject{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      sa.Name,
+				Namespace: testNamespace,
+			}))
+			gomega.Expect(metav1.IsControlledBy(crb, nrs)).To(gomega.BeTrue())
+
+			dp := &appsv1.Deployment{}
+			gomega.Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(reconciler.SchedulerManifests.Deployment), dp)).To(gomega.Succeed())
+			gomega.Expect(dp.Spec.Template.Spec.ServiceAccountName).To(gomega.Equal(sa.Name))
+		})
+
 		ginkgo.It("should have the correct schedulerName", func() {
 			key := client.ObjectKeyFromObject(nrs)
 			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
@@ -208,6 +248,38 @@ var _ = ginkgo.Describe("Test NUMAResourcesScheduler Reconcile", func() {
 			gomega.Expect(nrs.Status.CacheResyncPeriod.Seconds()).To(gomega.Equal(resyncPeriod.Seconds()))
 		})
 
+		ginkgo.It("should record the scheduler config changes once", func() {
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			initialHash := nrs.Status.ConfigHash
+			gomega.Expect(initialHash).ToNot(gomega.BeEmpty())
+			gomega.Expect(drainEvents(reconciler.Recorder)).ToNot(gomega.ContainElement(gomega.ContainSubstring(status.ReasonSchedulerConfigUpdated)))
+
+			nrs.Spec.CacheResyncPeriod = &metav1.Duration{
+				Duration: 7 * time.Second,
+			}
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+			for i := 0; i < 2; i++ {
+				_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			}
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			gomega.Expect(nrs.Status.ConfigHash).ToNot(gomega.Equal(initialHash))
+
+			updateEvents := 0
+			for _, event := range drainEvents(reconciler.Recorder) {
+				if strings.Contains(event, status.ReasonSchedulerConfigUpdated) {
+					gomega.Expect(event).To(gomega.ContainSubstring(nrs.Status.ConfigHash))
+					updateEvents++
+				}
+			}
+			gomega.Expect(updateEvents).To(gomega.Equal(1))
+		})
+
 		ginkgo.It("should have a config hash annotation under deployment", func() {
 			key := client.ObjectKeyFromObject(nrs)
 			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
@@ -390,6 +462,85 @@ var _ = ginkgo.Describe("Test NUMAResourcesScheduler Reconcile", func() {
 			}
 		})
 
+		ginkgo.It("should serve the scheduler cache state from a sidecar container", func() {
+			debugServeHTTP := nropv1.CacheResyncDebugServeHTTP
+			nrs := nrs.DeepCopy()
+			nrs.Spec.CacheResyncDebug = &debugServeHTTP
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+			ginkgo.By("missing the status server image")
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).To(gomega.HaveOccurred())
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			degradedCondition := getConditionByType(nrs.Status.Conditions, status.ConditionDegraded)
+			gomega.Expect(degradedCondition.Status).To(gomega.Equal(metav1.ConditionTrue))
+			gomega.Expect(degradedCondition.Message).To(gomega.ContainSubstring("missing status server image"))
+
+			ginkgo.By("providing the status server image")
+			reconciler.StatusServerImage = "quay.io/openshift-kni/numaresources-operator:test"
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			dp := &appsv1.Deployment{}
+			dpKey := client.ObjectKey{
+				Name:      "secondary-scheduler",
+				Namespace: testNamespace,
+			}
+			gomega.Expect(reconciler.Client.Get(context.TODO(), dpKey, dp)).ToNot(gomega.HaveOccurred())
+
+			cnt := depobjupdate.FindContainerByName(dp.Spec.Template.Spec.Containers, schedupdate.MainContainerName)
+			gomega.Expect(cnt).ToNot(gomega.BeNil(), "cannot find container %q in deployment", schedupdate.MainContainerName)
+			gotEv := schedupdate.FindEnvVarByName(cnt.Env, schedupdate.PFPStatusDumpEnvVar)
+			gomega.Expect(gotEv).ToNot(gomega.BeNil(), "missing environment variable %q in %q", schedupdate.PFPStatusDumpEnvVar, cnt.Name)
+
+			sidecar := depobjupdate.FindContainerByName(dp.Spec.Template.Spec.Containers, schedupdate.PFPStatusServerContainerName)
+			gomega.Expect(sidecar).ToNot(gomega.BeNil(), "cannot find container %q in deployment", schedupdate.PFPStatusServerContainerName)
+			gomega.Expect(sidecar.Image).To(gomega.Equal(reconciler.StatusServerImage))
+
+			svcKey := client.ObjectKeyFromObject(reconciler.SchedulerManifests.StatusService)
+			svc := &corev1.Service{}
+			gomega.Expect(reconciler.Client.Get(context.TODO(), svcKey, svc)).To(gomega.Succeed())
+			gomega.Expect(svc.Annotations).To(gomega.HaveKeyWithValue("service.beta.openshift.io/serving-cert-secret-name", schedupdate.PFPStatusSecretName))
+			gomega.Expect(metav1.IsControlledBy(svc, nrs)).To(gomega.BeTrue())
+
+			ginkgo.By("going back to dump the state on files only")
+			debugDumpJSONFile := nropv1.CacheResyncDebugDumpJSONFile
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			nrs.Spec.CacheResyncDebug = &debugDumpJSONFile
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), dpKey, dp)).ToNot(gomega.HaveOccurred())
+			gomega.Expect(depobjupdate.FindContainerByName(dp.Spec.Template.Spec.Containers, schedupdate.PFPStatusServerContainerName)).To(gomega.BeNil())
+			err = reconciler.Client.Get(context.TODO(), svcKey, svc)
+			gomega.Expect(apierrors.IsNotFound(err)).To(gomega.BeTrue(), "unexpected error: %v", err)
+		})
+
+		ginkgo.It("should degrade serving the scheduler cache state off OpenShift", func() {
+			debugServeHTTP := nropv1.CacheResyncDebugServeHTTP
+			nrs := nrs.DeepCopy()
+			nrs.Spec.CacheResyncDebug = &debugServeHTTP
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+			reconciler.Platform = platform.Kubernetes
+			reconciler.StatusServerImage = "quay.io/openshift-kni/numaresources-operator:test"
+
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			degradedCondition := getConditionByType(nrs.Status.Conditions, status.ConditionDegraded)
+			gomega.Expect(degradedCondition.Status).To(gomega.Equal(metav1.ConditionTrue))
+			gomega.Expect(degradedCondition.Reason).To(gomega.Equal(validation.SchedulerSpecError))
+
+			dp := &appsv1.Deployment{}
+			err = reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(reconciler.SchedulerManifests.Deployment), dp)
+			gomega.Expect(apierrors.IsNotFound(err)).To(gomega.BeTrue(), "unexpected error: %v", err)
+		})
+
 		ginkgo.It("should configure by default the relaxed resync detection mode in configmap", func() {
 			key := client.ObjectKeyFromObject(nrs)
 			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
@@ -574,6 +725,633 @@ var _ = ginkgo.Describe("Test NUMAResourcesScheduler Reconcile", func() {
 			expectScoringStrategyParams(reconciler.Client, depmanifests.ScoringStrategyMostAllocated, resources)
 
 		})
+
+		ginkgo.It("should render the additional scheduler profiles and report them in status", func() {
+			nrs := nrs.DeepCopy()
+			mostAllocated := nropv1.MostAllocated
+			nrs.Spec.Profiles = []nropv1.SchedulerProfile{
+				{
+					SchedulerName:     "packing-scheduler",
+					CacheResyncPeriod: &metav1.Duration{Duration: 5 * time.Second},
+					ScoringStrategy: &nropv1.ScoringStrategyParams{
+						Type: mostAllocated,
+					},
+				},
+			}
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			cmKey := client.ObjectKey{
+				Name:      "topo-aware-scheduler-config",
+				Namespace: testNamespace,
+			}
+			cm := corev1.ConfigMap{}
+			gomega.Expect(reconciler.Client.Get(context.TODO(), cmKey, &cm)).To(gomega.Succeed())
+
+			cfgs, err := depmanifests.DecodeSchedulerProfilesFromData([]byte(cm.Data[sched.SchedulerConfigFileName]))
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(cfgs).To(gomega.HaveLen(2))
+			gomega.Expect(cfgs[0].ProfileName).To(gomega.Equal(testSchedulerName))
+			gomega.Expect(cfgs[0].ScoringStrategy.Type).To(gomega.Equal(depmanifests.ScoringStrategyLeastAllocated))
+			gomega.Expect(*cfgs[0].Cache.ResyncPeriodSeconds).To(gomega.Equal(int64(11)))
+			gomega.Expect(cfgs[1].ProfileName).To(gomega.Equal("packing-scheduler"))
+			gomega.Expect(cfgs[1].ScoringStrategy.Type).To(gomega.Equal(depmanifests.ScoringStrategyMostAllocated))
+			gomega.Expect(*cfgs[1].Cache.ResyncPeriodSeconds).To(gomega.Equal(int64(5)))
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			gomega.Expect(nrs.Status.SchedulerName).To(gomega.Equal(testSchedulerName))
+			gomega.Expect(nrs.Status.Profiles).To(gomega.HaveLen(2))
+			gomega.Expect(nrs.Status.Profiles[0].SchedulerName).To(gomega.Equal(testSchedulerName))
+			gomega.Expect(nrs.Status.Profiles[1].SchedulerName).To(gomega.Equal("packing-scheduler"))
+			gomega.Expect(nrs.Status.Profiles[1].ScoringStrategy.Type).To(gomega.Equal(nropv1.MostAllocated))
+			gomega.Expect(nrs.Status.Profiles[1].CacheResyncPeriod.Duration).To(gomega.Equal(5 * time.Second))
+		})
+
+		ginkgo.It("should degrade if the scheduler profiles names are not unique", func() {
+			nrs := nrs.DeepCopy()
+			nrs.Spec.Profiles = []nropv1.SchedulerProfile{
+				{
+					SchedulerName: testSchedulerName,
+				},
+			}
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).To(gomega.HaveOccurred())
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			degradedCondition := getConditionByType(nrs.Status.Conditions, status.ConditionDegraded)
+			gomega.Expect(degradedCondition.Status).To(gomega.Equal(metav1.ConditionTrue))
+			gomega.Expect(degradedCondition.Message).To(gomega.ContainSubstring("duplicate scheduler profile"))
+		})
+
+		ginkgo.It("should allow to change the ScoringStrategy to RequestedToCapacityRatio", func() {
+			nrs := nrs.DeepCopy()
+			nrs.Spec.ScoringStrategy = &nropv1.ScoringStrategyParams{
+				Type: nropv1.RequestedToCapacityRatio,
+				RequestedToCapacityRatio: &nropv1.RequestedToCapacityRatioParams{
+					Shape: []nropv1.UtilizationShapePoint{
+						{Utilization: 0, Score: 0},
+						{Utilization: 100, Score: 10},
+					},
+				},
+			}
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			scoringStrategy := getScoringStrategyArgs(reconciler.Client, testSchedulerName)
+			gomega.Expect(scoringStrategy["type"]).To(gomega.Equal("RequestedToCapacityRatio"))
+			gomega.Expect(scoringStrategy["requestedToCapacityRatio"]).To(gomega.Equal(map[string]interface{}{
+				"shape": []interface{}{
+					map[string]interface{}{"utilization": float64(0), "score": float64(0)},
+					map[string]interface{}{"utilization": float64(100), "score": float64(10)},
+				},
+			}))
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			gomega.Expect(nrs.Status.SchedulerName).To(gomega.Equal(testSchedulerName))
+		})
+
+		ginkgo.It("should degrade if the RequestedToCapacityRatio ScoringStrategy has no shape", func() {
+			nrs := nrs.DeepCopy()
+			nrs.Spec.ScoringStrategy = &nropv1.ScoringStrategyParams{
+				Type: nropv1.RequestedToCapacityRatio,
+			}
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			degradedCondition := getConditionByType(nrs.Status.Conditions, status.ConditionDegraded)
+			gomega.Expect(degradedCondition.Status).To(gomega.Equal(metav1.ConditionTrue))
+			gomega.Expect(degradedCondition.Reason).To(gomega.Equal(validation.SchedulerSpecError))
+		})
+
+		ginkgo.It("should merge the scheduler config overlay", func() {
+			overlay := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sched-overlay",
+					Namespace: testNamespace,
+				},
+				Data: map[string]string{
+					sched.SchedulerConfigFileName: "percentageOfNodesToScore: 50\n",
+				},
+			}
+			gomega.Expect(reconciler.Client.Create(context.TODO(), overlay)).To(gomega.Succeed())
+
+			nrs := nrs.DeepCopy()
+			nrs.Spec.ConfigOverlayRef = &corev1.LocalObjectReference{Name: overlay.Name}
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			conf := getSchedulerConfig(reconciler.Client)
+			gomega.Expect(conf["percentageOfNodesToScore"]).To(gomega.Equal(float64(50)))
+
+			gomega.Expect(reconciler.configOverlayToNUMAResourcesScheduler(context.TODO(), overlay)).To(gomega.Equal([]reconcile.Request{{NamespacedName: key}}))
+		})
+
+		ginkgo.It("should degrade if the scheduler config overlay changes the fields managed by the operator", func() {
+			overlay := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sched-overlay",
+					Namespace: testNamespace,
+				},
+				Data: map[string]string{
+					sched.SchedulerConfigFileName: "leaderElection:\n  leaderElect: true\n",
+				},
+			}
+			gomega.Expect(reconciler.Client.Create(context.TODO(), overlay)).To(gomega.Succeed())
+
+			nrs := nrs.DeepCopy()
+			nrs.Spec.ConfigOverlayRef = &corev1.LocalObjectReference{Name: overlay.Name}
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).To(gomega.HaveOccurred())
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			degradedCondition := getConditionByType(nrs.Status.Conditions, status.ConditionDegraded)
+			gomega.Expect(degradedCondition.Status).To(gomega.Equal(metav1.ConditionTrue))
+			gomega.Expect(degradedCondition.Reason).To(gomega.Equal(status.ReasonSchedulerConfigOverlayError))
+			gomega.Expect(degradedCondition.Message).To(gomega.ContainSubstring("leaderElection"))
+		})
+
+		ginkgo.It("should degrade if the scheduler config overlay does not exist", func() {
+			nrs := nrs.DeepCopy()
+			nrs.Spec.ConfigOverlayRef = &corev1.LocalObjectReference{Name: "missing-overlay"}
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).To(gomega.HaveOccurred())
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			degradedCondition := getConditionByType(nrs.Status.Conditions, status.ConditionDegraded)
+			gomega.Expect(degradedCondition.Status).To(gomega.Equal(metav1.ConditionTrue))
+			gomega.Expect(degradedCondition.Reason).To(gomega.Equal(status.ReasonSchedulerConfigOverlayError))
+		})
+
+		ginkgo.It("should report the effective configuration and the replicas state in status", func() {
+			labels := reconciler.SchedulerManifests.Deployment.Spec.Selector.MatchLabels
+			for _, ready := range []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse} {
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secondary-scheduler-" + strings.ToLower(string(ready)),
+						Namespace: testNamespace,
+						Labels:    labels,
+					},
+					Spec: corev1.PodSpec{
+						NodeName: "node-0",
+					},
+					Status: corev1.PodStatus{
+						Phase: corev1.PodRunning,
+						Conditions: []corev1.PodCondition{
+							{
+								Type:   corev1.PodReady,
+								Status: ready,
+							},
+						},
+					},
+				}
+				gomega.Expect(reconciler.Client.Create(context.TODO(), pod)).To(gomega.Succeed())
+			}
+
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			gomega.Expect(nrs.Status.Config).ToNot(gomega.BeNil())
+			gomega.Expect(*nrs.Status.Config).To(gomega.Equal(nrs.Spec.Normalize()))
+
+			dpKey := client.ObjectKeyFromObject(reconciler.SchedulerManifests.Deployment)
+			dp := &appsv1.Deployment{}
+			gomega.Expect(reconciler.Client.Get(context.TODO(), dpKey, dp)).To(gomega.Succeed())
+			gomega.Expect(nrs.Status.ConfigHash).ToNot(gomega.BeEmpty())
+			gomega.Expect(nrs.Status.ConfigHash).To(gomega.Equal(dp.Spec.Template.Annotations[hash.ConfigMapAnnotation]))
+
+			gomega.Expect(nrs.Status.Replicas).To(gomega.Equal([]nropv1.SchedulerReplicaStatus{
+				{
+					Name:     "secondary-scheduler-false",
+					NodeName: "node-0",
+					Ready:    false,
+				},
+				{
+					Name:     "secondary-scheduler-true",
+					NodeName: "node-0",
+					Ready:    true,
+				},
+			}))
+		})
+
+		ginkgo.It("should apply the placement and resources overrides and report them in status", func() {
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			dpKey := client.ObjectKeyFromObject(reconciler.SchedulerManifests.Deployment)
+			dp := &appsv1.Deployment{}
+			gomega.Expect(reconciler.Client.Get(context.TODO(), dpKey, dp)).To(gomega.Succeed())
+			defaultPodSpec := dp.Spec.Template.Spec.DeepCopy()
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			gomega.Expect(nrs.Status.DeploymentSettings).ToNot(gomega.BeNil())
+			gomega.Expect(nrs.Status.DeploymentSettings.Affinity).To(gomega.Equal(defaultPodSpec.Affinity))
+
+			ginkgo.By("pinning the scheduler on the infra nodes")
+			resources := corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("4Gi"),
+				},
+			}
+			nrs.Spec.NodeSelector = map[string]string{
+				"node-role.kubernetes.io/infra": "",
+			}
+			nrs.Spec.Tolerations = []corev1.Toleration{
+				{
+					Key:    "node-role.kubernetes.io/infra",
+					Effect: corev1.TaintEffectNoSchedule,
+				},
+			}
+			nrs.Spec.PriorityClassName = "system-cluster-critical"
+			nrs.Spec.Resources = &resources
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), dpKey, dp)).To(gomega.Succeed())
+			podSpec := &dp.Spec.Template.Spec
+			gomega.Expect(podSpec.NodeSelector).To(gomega.Equal(nrs.Spec.NodeSelector))
+			gomega.Expect(podSpec.Affinity).To(gomega.BeNil())
+			gomega.Expect(podSpec.Tolerations).To(gomega.Equal(nrs.Spec.Tolerations))
+			gomega.Expect(podSpec.PriorityClassName).To(gomega.Equal("system-cluster-critical"))
+			cnt := depobjupdate.FindContainerByName(podSpec.Containers, schedupdate.MainContainerName)
+			gomega.Expect(cnt).ToNot(gomega.BeNil())
+			gomega.Expect(cnt.Resources).To(gomega.Equal(resources))
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			settings := nrs.Status.DeploymentSettings
+			gomega.Expect(settings).ToNot(gomega.BeNil())
+			gomega.Expect(settings.NodeSelector).To(gomega.Equal(nrs.Spec.NodeSelector))
+			gomega.Expect(settings.Tolerations).To(gomega.Equal(nrs.Spec.Tolerations))
+			gomega.Expect(settings.PriorityClassName).To(gomega.Equal("system-cluster-critical"))
+			gomega.Expect(settings.Resources).To(gomega.Equal(resources))
+
+			ginkgo.By("removing the overrides")
+			nrs.Spec.NodeSelector = nil
+			nrs.Spec.Tolerations = nil
+			nrs.Spec.PriorityClassName = ""
+			nrs.Spec.Resources = nil
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), dpKey, dp)).To(gomega.Succeed())
+			gomega.Expect(dp.Spec.Template.Spec.NodeSelector).To(gomega.BeEmpty())
+			gomega.Expect(dp.Spec.Template.Spec.Affinity).To(gomega.Equal(defaultPodSpec.Affinity))
+			gomega.Expect(dp.Spec.Template.Spec.Tolerations).To(gomega.Equal(defaultPodSpec.Tolerations))
+			gomega.Expect(dp.Spec.Template.Spec.PriorityClassName).To(gomega.BeEmpty())
+			gomega.Expect(dp.Spec.Template.Spec.Containers).To(gomega.Equal(defaultPodSpec.Containers))
+		})
+
+		ginkgo.It("should run a single replica without leader election by default", func() {
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			dp := &appsv1.Deployment{}
+			gomega.Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(reconciler.SchedulerManifests.Deployment), dp)).To(gomega.Succeed())
+			gomega.Expect(*dp.Spec.Replicas).To(gomega.Equal(int32(1)))
+
+			expectLeaderElection(reconciler.Client, map[string]interface{}{
+				"leaderElect": false,
+			})
+
+			pdb := &policyv1.PodDisruptionBudget{}
+			err = reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(reconciler.SchedulerManifests.PodDisruptionBudget), pdb)
+			gomega.Expect(apierrors.IsNotFound(err)).To(gomega.BeTrue(), "unexpected error: %v", err)
+		})
+
+		ginkgo.It("should enable leader election and the disruption budget with multiple replicas", func() {
+			replicas := int32(3)
+			nrs.Spec.Replicas = &replicas
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			dp := &appsv1.Deployment{}
+			gomega.Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(reconciler.SchedulerManifests.Deployment), dp)).To(gomega.Succeed())
+			gomega.Expect(*dp.Spec.Replicas).To(gomega.Equal(replicas))
+
+			expectLeaderElection(reconciler.Client, map[string]interface{}{
+				"leaderElect":       true,
+				"resourceLock":      "leases",
+				"resourceNamespace": testNamespace,
+				"resourceName":      dp.Name,
+			})
+
+			pdbKey := client.ObjectKeyFromObject(reconciler.SchedulerManifests.PodDisruptionBudget)
+			pdb := &policyv1.PodDisruptionBudget{}
+			gomega.Expect(reconciler.Client.Get(context.TODO(), pdbKey, pdb)).To(gomega.Succeed())
+			gomega.Expect(pdb.Spec.MinAvailable.IntValue()).To(gomega.Equal(1))
+			gomega.Expect(pdb.Spec.Selector.MatchLabels).To(gomega.Equal(dp.Spec.Selector.MatchLabels))
+			gomega.Expect(metav1.IsControlledBy(pdb, nrs)).To(gomega.BeTrue())
+
+			ginkgo.By("scaling back to a single replica")
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			replicas = 1
+			nrs.Spec.Replicas = &replicas
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			expectLeaderElection(reconciler.Client, map[string]interface{}{
+				"leaderElect": false,
+			})
+			err = reconciler.Client.Get(context.TODO(), pdbKey, pdb)
+			gomega.Expect(apierrors.IsNotFound(err)).To(gomega.BeTrue(), "unexpected error: %v", err)
+		})
+	})
+
+	ginkgo.Context("with the cache desync detection", func() {
+		var nrs *nropv1.NUMAResourcesScheduler
+		var reconciler *NUMAResourcesSchedulerReconciler
+		var unsynced map[string]sets.Set[string]
+
+		ginkgo.BeforeEach(func() {
+			nrs = testobjs.NewNUMAResourcesScheduler("numaresourcesscheduler", "some/url:latest", testSchedulerName, 11*time.Second)
+			var objs []runtime.Object
+			for _, nodeName := range []string{"node-0", "node-1"} {
+				objs = append(objs, &nrtv1alpha2.NodeResourceTopology{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: nodeName,
+					},
+				})
+			}
+
+			var err error
+			reconciler, err = NewFakeNUMAResourcesSchedulerReconciler(append(objs, nrs)...)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			unsynced = map[string]sets.Set[string]{}
+			reconciler.CacheSyncCheck = func(ctx context.Context, pod *corev1.Pod, nodeNames []string) (map[string]sets.Set[string], error) {
+				gomega.Expect(nodeNames).To(gomega.ConsistOf("node-0", "node-1"))
+				return unsynced, nil
+			}
+
+			// the fake client creates the deployment with the status of the manifest
+			reconciler.SchedulerManifests.Deployment.Status.Conditions = []appsv1.DeploymentCondition{
+				{
+					Type:   appsv1.DeploymentAvailable,
+					Status: corev1.ConditionTrue,
+				},
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secondary-scheduler-0",
+					Namespace: testNamespace,
+					Labels:    reconciler.SchedulerManifests.Deployment.Spec.Selector.MatchLabels,
+				},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+				},
+			}
+			gomega.Expect(reconciler.Client.Create(context.TODO(), pod)).To(gomega.Succeed())
+		})
+
+		ginkgo.It("should report the nodes with desynced cache", func() {
+			unsynced["node-1"] = sets.New[string]("ns1/pod1", "ns2/pod2")
+
+			key := client.ObjectKeyFromObject(nrs)
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(result.RequeueAfter).To(gomega.Equal(cacheDesyncCheckPeriod))
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			cond := getConditionByType(nrs.Status.Conditions, status.ConditionTypeCacheDesynced)
+			gomega.Expect(cond).ToNot(gomega.BeNil())
+			gomega.Expect(cond.Status).To(gomega.Equal(metav1.ConditionTrue))
+			gomega.Expect(cond.Reason).To(gomega.Equal(status.ReasonCacheNodesDesynced))
+			gomega.Expect(cond.Message).To(gomega.ContainSubstring("node-1 (detected pods: 2)"))
+			gomega.Expect(cond.Message).ToNot(gomega.ContainSubstring("node-0"))
+
+			fakeRecorder, ok := reconciler.Recorder.(*record.FakeRecorder)
+			gomega.Expect(ok).To(gomega.BeTrue())
+			gomega.Expect(<-fakeRecorder.Events).To(gomega.ContainSubstring("CacheDesynced"))
+
+			ginkgo.By("changing the desynced pods")
+			unsynced["node-1"] = sets.New[string]("ns1/pod1")
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			cond = getConditionByType(nrs.Status.Conditions, status.ConditionTypeCacheDesynced)
+			gomega.Expect(cond.Message).To(gomega.ContainSubstring("node-1 (detected pods: 1)"))
+			gomega.Expect(drainEvents(fakeRecorder)).ToNot(gomega.ContainElement(gomega.ContainSubstring("CacheDesynced")))
+
+			ginkgo.By("resyncing the cache")
+			delete(unsynced, "node-1")
+			_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			cond = getConditionByType(nrs.Status.Conditions, status.ConditionTypeCacheDesynced)
+			gomega.Expect(cond).ToNot(gomega.BeNil())
+			gomega.Expect(cond.Status).To(gomega.Equal(metav1.ConditionFalse))
+			gomega.Expect(<-fakeRecorder.Events).To(gomega.ContainSubstring("CacheSynced"))
+		})
+
+		ginkgo.It("should cap the nodes listed in the condition message", func() {
+			desynced := map[string]sets.Set[string]{}
+			for idx := 0; idx < maxDesyncedNodesInMessage+3; idx++ {
+				desynced[fmt.Sprintf("node-%02d", idx)] = sets.New[string]("ns1/pod1", "ns2/pod2", "ns3/pod3")
+			}
+
+			cond := cacheDesyncedCondition(desynced)
+			gomega.Expect(cond.Message).To(gomega.HavePrefix(fmt.Sprintf("the scheduler cache is not in sync on %d nodes: node-00 (detected pods: 3)", maxDesyncedNodesInMessage+3)))
+			gomega.Expect(cond.Message).To(gomega.HaveSuffix("and 3 more"))
+			gomega.Expect(cond.Message).ToNot(gomega.ContainSubstring("ns1/pod1"))
+			gomega.Expect(cond.Message).ToNot(gomega.ContainSubstring(fmt.Sprintf("node-%02d", maxDesyncedNodesInMessage)))
+		})
+
+		ginkgo.It("should not check the cache if the scheduler does not report its state", func() {
+			resyncDebug := nropv1.CacheResyncDebugDisabled
+			nrs.Spec.CacheResyncDebug = &resyncDebug
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+			reconciler.CacheSyncCheck = func(ctx context.Context, pod *corev1.Pod, nodeNames []string) (map[string]sets.Set[string], error) {
+				ginkgo.Fail("unexpected cache check")
+				return nil, nil
+			}
+
+			key := client.ObjectKeyFromObject(nrs)
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(result).To(gomega.Equal(reconcile.Result{}))
+
+			gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+			gomega.Expect(getConditionByType(nrs.Status.Conditions, status.ConditionTypeCacheDesynced)).To(gomega.BeNil())
+		})
+
+		ginkgo.It("should check only the cache of the leader with multiple replicas", func() {
+			replicas := int32(2)
+			nrs.Spec.Replicas = &replicas
+			gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+			standby := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secondary-scheduler-1",
+					Namespace: testNamespace,
+					Labels:    reconciler.SchedulerManifests.Deployment.Spec.Selector.MatchLabels,
+				},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+				},
+			}
+			gomega.Expect(reconciler.Client.Create(context.TODO(), standby)).To(gomega.Succeed())
+
+			holder := "secondary-scheduler-0_6d1f6a5e-2f4c-4a4b-9d38-0c2b9f5e6b11"
+			lease := &coordinationv1.Lease{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      reconciler.SchedulerManifests.Deployment.Name,
+					Namespace: testNamespace,
+				},
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity: &holder,
+				},
+			}
+			gomega.Expect(reconciler.Client.Create(context.TODO(), lease)).To(gomega.Succeed())
+
+			var checked []string
+			reconciler.CacheSyncCheck = func(ctx context.Context, pod *corev1.Pod, nodeNames []string) (map[string]sets.Set[string], error) {
+				checked = append(checked, pod.Name)
+				return unsynced, nil
+			}
+
+			key := client.ObjectKeyFromObject(nrs)
+			_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(checked).To(gomega.Equal([]string{"secondary-scheduler-0"}))
+		})
+
+		ginkgo.Context("with the cache desync remediation", func() {
+			setRemediation := func(mode nropv1.CacheDesyncRemediationMode, desyncedSince time.Time) {
+				ginkgo.GinkgoHelper()
+
+				nrs.Spec.CacheDesyncRemediation = &nropv1.CacheDesyncRemediation{
+					Mode: &mode,
+				}
+				gomega.Expect(reconciler.Client.Update(context.TODO(), nrs)).To(gomega.Succeed())
+
+				cond := cacheDesyncedCondition(unsynced)
+				cond.LastTransitionTime = metav1.NewTime(desyncedSince)
+				nrs.Status.Conditions = []metav1.Condition{cond}
+				gomega.Expect(reconciler.Client.Status().Update(context.TODO(), nrs)).To(gomega.Succeed())
+			}
+
+			ginkgo.It("should not remediate before the desync persisted beyond the threshold", func() {
+				unsynced["node-1"] = sets.New[string]("ns1/pod1")
+				setRemediation(nropv1.CacheDesyncRemediationRestartPod, time.Now().Add(-time.Minute))
+
+				key := client.ObjectKeyFromObject(nrs)
+				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+				pod := &corev1.Pod{}
+				gomega.Expect(reconciler.Client.Get(context.TODO(), client.ObjectKey{Namespace: testNamespace, Name: "secondary-scheduler-0"}, pod)).To(gomega.Succeed())
+
+				gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+				gomega.Expect(nrs.Status.LastCacheDesyncRemediation).To(gomega.BeNil())
+			})
+
+			ginkgo.It("should restart the desynced replicas once, then rate limit", func() {
+				unsynced["node-1"] = sets.New[string]("ns1/pod1")
+				setRemediation(nropv1.CacheDesyncRemediationRestartPod, time.Now().Add(-time.Hour))
+
+				key := client.ObjectKeyFromObject(nrs)
+				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+				podKey := client.ObjectKey{Namespace: testNamespace, Name: "secondary-scheduler-0"}
+				pod := &corev1.Pod{}
+				err = reconciler.Client.Get(context.TODO(), podKey, pod)
+				gomega.Expect(apierrors.IsNotFound(err)).To(gomega.BeTrue(), "unexpected error: %v", err)
+
+				gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+				last := nrs.Status.LastCacheDesyncRemediation
+				gomega.Expect(last).ToNot(gomega.BeNil())
+				gomega.Expect(last.Mode).To(gomega.Equal(nropv1.CacheDesyncRemediationRestartPod))
+				gomega.Expect(last.Nodes).To(gomega.Equal([]string{"node-1"}))
+
+				fakeRecorder, ok := reconciler.Recorder.(*record.FakeRecorder)
+				gomega.Expect(ok).To(gomega.BeTrue())
+				gomega.Expect(<-fakeRecorder.Events).To(gomega.ContainSubstring("Deleted scheduler replica " + testNamespace + "/secondary-scheduler-0"))
+
+				ginkgo.By("checking the remediation is rate limited")
+				pod = &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secondary-scheduler-1",
+						Namespace: testNamespace,
+						Labels:    reconciler.SchedulerManifests.Deployment.Spec.Selector.MatchLabels,
+					},
+					Status: corev1.PodStatus{
+						Phase: corev1.PodRunning,
+					},
+				}
+				gomega.Expect(reconciler.Client.Create(context.TODO(), pod)).To(gomega.Succeed())
+
+				_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+				gomega.Expect(reconciler.Client.Get(context.TODO(), client.ObjectKeyFromObject(pod), pod)).To(gomega.Succeed())
+
+				gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+				gomega.Expect(nrs.Status.LastCacheDesyncRemediation.Time.Equal(&last.Time)).To(gomega.BeTrue())
+			})
+
+			ginkgo.It("should force the cache resync annotating the pod template", func() {
+				unsynced["node-1"] = sets.New[string]("ns1/pod1")
+				setRemediation(nropv1.CacheDesyncRemediationForceResync, time.Now().Add(-time.Hour))
+
+				key := client.ObjectKeyFromObject(nrs)
+				_, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+				gomega.Expect(reconciler.Client.Get(context.TODO(), key, nrs)).To(gomega.Succeed())
+				last := nrs.Status.LastCacheDesyncRemediation
+				gomega.Expect(last).ToNot(gomega.BeNil())
+				gomega.Expect(last.Mode).To(gomega.Equal(nropv1.CacheDesyncRemediationForceResync))
+
+				dp := &appsv1.Deployment{}
+				gomega.Expect(reconciler.Client.Get(context.TODO(), client.ObjectKey(nrs.Status.Deployment), dp)).To(gomega.Succeed())
+				gomega.Expect(dp.Spec.Template.Annotations).To(gomega.HaveKeyWithValue(schedupdate.CacheResyncRequestAnnotation, last.Time.UTC().Format(time.RFC3339)))
+
+				ginkgo.By("checking the annotation is kept on the next reconcile")
+				unsynced = map[string]sets.Set[string]{}
+				_, err = reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+				gomega.Expect(reconciler.Client.Get(context.TODO(), client.ObjectKey(nrs.Status.Deployment), dp)).To(gomega.Succeed())
+				gomega.Expect(dp.Spec.Template.Annotations).To(gomega.HaveKeyWithValue(schedupdate.CacheResyncRequestAnnotation, last.Time.UTC().Format(time.RFC3339)))
+			})
+		})
 	})
 })
 
@@ -595,6 +1373,67 @@ func diffYAML(want, got string) (string, error) {
 	return cmp.Diff(cfgWant, cfgGot), nil
 }
 
+func expectLeaderElection(cli client.Client, expected map[string]interface{}) {
+	ginkgo.GinkgoHelper()
+
+	conf := getSchedulerConfig(cli)
+	gomega.Expect(conf["leaderElection"]).To(gomega.Equal(expected))
+}
+
+func getSchedulerConfig(cli client.Client) map[string]interface{} {
+	ginkgo.GinkgoHelper()
+
+	key := client.ObjectKey{
+		Name:      "topo-aware-scheduler-config",
+		Namespace: testNamespace,
+	}
+
+	cm := corev1.ConfigMap{}
+	gomega.Expect(cli.Get(context.TODO(), key, &cm)).To(gomega.Succeed())
+
+	var conf map[string]interface{}
+	gomega.Expect(yaml.Unmarshal([]byte(cm.Data[sched.SchedulerConfigFileName]), &conf)).To(gomega.Succeed())
+	return conf
+}
+
+func getScoringStrategyArgs(cli client.Client, profileName string) map[string]interface{} {
+	ginkgo.GinkgoHelper()
+
+	key := client.ObjectKey{
+		Name:      "topo-aware-scheduler-config",
+		Namespace: testNamespace,
+	}
+
+	cm := corev1.ConfigMap{}
+	gomega.Expect(cli.Get(context.TODO(), key, &cm)).To(gomega.Succeed())
+
+	var conf struct {
+		Profiles []struct {
+			SchedulerName string `json:"schedulerName"`
+			PluginConfig  []struct {
+				Name string                 `json:"name"`
+				Args map[string]interface{} `json:"args"`
+			} `json:"pluginConfig"`
+		} `json:"profiles"`
+	}
+	gomega.Expect(yaml.Unmarshal([]byte(cm.Data[sched.SchedulerConfigFileName]), &conf)).To(gomega.Succeed())
+	for _, profile := range conf.Profiles {
+		if profile.SchedulerName != profileName {
+			continue
+		}
+		for _, pluginConf := range profile.PluginConfig {
+			if pluginConf.Name != sched.SchedulerPluginName {
+				continue
+			}
+			scoringStrategy, ok := pluginConf.Args["scoringStrategy"].(map[string]interface{})
+			gomega.Expect(ok).To(gomega.BeTrue(), "missing scoringStrategy args in profile %q", profileName)
+			return scoringStrategy
+		}
+	}
+	ginkgo.Fail("cannot find the scheduler profile " + profileName)
+	return nil
+}
+
 func expectCacheParams(cli client.Client, resyncMethod, foreignPodsDetect string, informerMode string) {
 	ginkgo.GinkgoHelper()
 
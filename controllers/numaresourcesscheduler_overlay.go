@@ -0,0 +1,83 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	schedstate "github.com/openshift-kni/numaresources-operator/pkg/numaresourcesscheduler/objectstate/sched"
+	schedupdate "github.com/openshift-kni/numaresources-operator/pkg/objectupdate/sched"
+	"github.com/openshift-kni/numaresources-operator/pkg/status"
+)
+
+// applySchedulerConfigOverlay merges the scheduler config overlay referenced by the spec, if any, into the scheduler config map.
+// The failures are caused by the user-supplied overlay, which the user needs to fix, and are reported as such.
+func (r *NUMAResourcesSchedulerReconciler) applySchedulerConfigOverlay(ctx context.Context, ref *corev1.LocalObjectReference, cm *corev1.ConfigMap) error {
+	if ref == nil {
+		return nil
+	}
+
+	key := client.ObjectKey{
+		Namespace: r.Namespace,
+		Name:      ref.Name,
+	}
+	overlayCM := corev1.ConfigMap{}
+	if err := r.Get(ctx, key, &overlayCM); err != nil {
+		return status.NewReasonedError(status.ReasonSchedulerConfigOverlayError, fmt.Errorf("cannot get the scheduler config overlay %s: %w", key.String(), err))
+	}
+	data, ok := overlayCM.Data[schedstate.SchedulerConfigFileName]
+	if !ok {
+		return status.NewReasonedError(status.ReasonSchedulerConfigOverlayError, fmt.Errorf("no data key named: %s found in the scheduler config overlay %s", schedstate.SchedulerConfigFileName, key.String()))
+	}
+	if err := schedupdate.SchedulerConfigOverlay(cm, data); err != nil {
+		return status.NewReasonedError(status.ReasonSchedulerConfigOverlayError, fmt.Errorf("cannot apply the scheduler config overlay %s: %w", key.String(), err))
+	}
+	klog.V(4).InfoS("applied scheduler config overlay", "configMap", key.String())
+	return nil
+}
+
+// configOverlayToNUMAResourcesScheduler triggers the reconciliation of the NUMAResourcesScheduler objects referencing the config map as overlay.
+func (r *NUMAResourcesSchedulerReconciler) configOverlayToNUMAResourcesScheduler(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetNamespace() != r.Namespace {
+		return nil
+	}
+
+	nrss := &nropv1.NUMAResourcesSchedulerList{}
+	if err := r.List(ctx, nrss); err != nil {
+		klog.ErrorS(err, "failed to list the NUMAResourcesScheduler objects")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for idx := range nrss.Items {
+		nrs := &nrss.Items[idx]
+		if nrs.Spec.ConfigOverlayRef == nil || nrs.Spec.ConfigOverlayRef.Name != obj.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(nrs),
+		})
+	}
+	return requests
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apiextensionv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	nodegroupv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1/helper/nodegroup"
+	nropv1alpha1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1alpha1"
+	"github.com/openshift-kni/numaresources-operator/pkg/status"
+)
+
+const (
+	crdNameNUMAResourcesOperator  = "numaresourcesoperators.nodetopology.openshift.io"
+	crdNameNUMAResourcesScheduler = "numaresourcesschedulers.nodetopology.openshift.io"
+)
+
+// operatorUpgradeableCondition tells if the operator can be safely upgraded, considering the state of the
+// NUMAResourcesOperator object after the reconciliation reported as cond.
+func operatorUpgradeableCondition(ctx context.Context, cli client.Client, instance *nropv1.NUMAResourcesOperator, trees []nodegroupv1.Tree, cond status.ConditionInfo) metav1.Condition {
+	if cond.Type == status.ConditionDegraded {
+		return status.NewUpgradeableCondition(cond.Reason, cond.Message)
+	}
+	// an upgrade would restart the rollout of the machine configs, causing more node reboots
+	if mcpNames := machineConfigPoolsUpdating(instance.Name, trees); len(mcpNames) > 0 {
+		return status.NewUpgradeableCondition(status.ReasonMachineConfigPoolUpdating, fmt.Sprintf("MachineConfigPools %s are applying the machine configuration", strings.Join(mcpNames, ",")))
+	}
+	if cond.Type == status.ConditionProgressing && cond.Reason == status.ReasonDaemonSetNotReady {
+		return status.NewUpgradeableCondition(status.ReasonDaemonSetRollingOut, cond.Message)
+	}
+	for _, ngStatus := range instance.Status.NodeGroups {
+		ngCond := status.FindCondition(ngStatus.Conditions, status.ConditionProgressing)
+		if ngCond != nil && ngCond.Status == metav1.ConditionTrue && ngCond.Reason == status.ReasonDaemonSetRollingOut {
+			return status.NewUpgradeableCondition(status.ReasonDaemonSetRollingOut, ngCond.Message)
+		}
+	}
+	return storedVersionUpgradeableCondition(ctx, cli, crdNameNUMAResourcesOperator, &nropv1.NUMAResourcesOperatorList{}, instance)
+}
+
+// schedulerUpgradeableCondition tells if the operator can be safely upgraded, considering the state of the
+// NUMAResourcesScheduler object after the reconciliation reported as cond.
+func schedulerUpgradeableCondition(ctx context.Context, cli client.Client, instance *nropv1.NUMAResourcesScheduler, cond status.ConditionInfo) metav1.Condition {
+	switch cond.Type {
+	case status.ConditionDegraded:
+		return status.NewUpgradeableCondition(cond.Reason, cond.Message)
+	case status.ConditionProgressing:
+		// the pods would lose the NUMA-aware scheduling while both the old and the new scheduler are unavailable
+		return status.NewUpgradeableCondition(status.ReasonSchedulerNotAvailable, cond.Message)
+	}
+	return storedVersionUpgradeableCondition(ctx, cli, crdNameNUMAResourcesScheduler, &nropv1.NUMAResourcesSchedulerList{}, instance)
+}
+
+// storedVersionUpgradeableCondition migrates the objects of the CRD away from the deprecated v1alpha1 API version,
+// which the future versions of the operator will stop serving, and blocks the upgrades until the migration succeeds.
+// instance is the object being reconciled: its resourceVersion is kept current if the migration rewrites it.
+func storedVersionUpgradeableCondition(ctx context.Context, cli client.Client, crdName string, list client.ObjectList, instance client.Object) metav1.Condition {
+	if err := migrateStoredVersions(ctx, cli, crdName, list, instance); err != nil {
+		klog.ErrorS(err, "cannot migrate the stored versions", "crd", crdName)
+		return status.NewUpgradeableCondition(status.ReasonDeprecatedVersionStored, fmt.Sprintf("%s objects may be stored as %s, failed to migrate them: %v", crdName, nropv1alpha1.GroupVersion.Version, err))
+	}
+	return status.NewUpgradeableCondition("", "")
+}
+
+// migrateStoredVersions rewrites all the objects of the CRD, so the API server stores them with the current storage
+// version, then drops the deprecated v1alpha1 version from the stored versions of the CRD.
+func migrateStoredVersions(ctx context.Context, cli client.Client, crdName string, list client.ObjectList, instance client.Object) error {
+	crd := apiextensionv1.CustomResourceDefinition{}
+	if err := cli.Get(ctx, client.ObjectKey{Name: crdName}, &crd); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("cannot get the stored versions: %w", err)
+	}
+	storedVersions := make([]string, 0, len(crd.Status.StoredVersions))
+	for _, ver := range crd.Status.StoredVersions {
+		if ver != nropv1alpha1.GroupVersion.Version {
+			storedVersions = append(storedVersions, ver)
+		}
+	}
+	if len(storedVersions) == len(crd.Status.StoredVersions) {
+		return nil
+	}
+
+	if err := cli.List(ctx, list); err != nil {
+		return fmt.Errorf("cannot list the objects to migrate: %w", err)
+	}
+	objs, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	for _, item := range objs {
+		obj, ok := item.(client.Object)
+		if !ok {
+			return fmt.Errorf("unexpected object %T", item)
+		}
+		// an unchanged object is still written in the current storage version
+		if err := cli.Update(ctx, obj); err != nil {
+			return fmt.Errorf("cannot migrate %s: %w", obj.GetName(), err)
+		}
+		if client.ObjectKeyFromObject(obj) == client.ObjectKeyFromObject(instance) {
+			instance.SetResourceVersion(obj.GetResourceVersion())
+		}
+		klog.V(2).InfoS("migrated the stored version", "crd", crdName, "name", obj.GetName())
+	}
+
+	if len(storedVersions) == 0 {
+		storedVersions = append(storedVersions, nropv1.GroupVersion.Version)
+	}
+	crd.Status.StoredVersions = storedVersions
+	if err := cli.Status().Update(ctx, &crd); err != nil {
+		return fmt.Errorf("cannot update the stored versions: %w", err)
+	}
+	klog.InfoS("dropped the deprecated stored version", "crd", crdName, "version", nropv1alpha1.GroupVersion.Version)
+	return nil
+}
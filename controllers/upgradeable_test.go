@@ -0,0 +1,149 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	"github.com/openshift-kni/numaresources-operator/pkg/objectnames"
+	"github.com/openshift-kni/numaresources-operator/pkg/status"
+
+	testobjs "github.com/openshift-kni/numaresources-operator/internal/objects"
+)
+
+var _ = Describe("Test Upgradeable condition", func() {
+	newCRD := func(name string, storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				StoredVersions: storedVersions,
+			},
+		}
+	}
+
+	Context("with the deprecated API version", func() {
+		It("should be upgradeable if the CRD is missing", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			cond := storedVersionUpgradeableCondition(context.TODO(), cli, crdNameNUMAResourcesOperator, &nropv1.NUMAResourcesOperatorList{}, &nropv1.NUMAResourcesOperator{})
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal(status.ReasonAsExpected))
+		})
+
+		It("should be upgradeable if only the current version is stored", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(newCRD(crdNameNUMAResourcesOperator, "v1")).Build()
+			cond := storedVersionUpgradeableCondition(context.TODO(), cli, crdNameNUMAResourcesOperator, &nropv1.NUMAResourcesOperatorList{}, &nropv1.NUMAResourcesOperator{})
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		})
+
+		It("should migrate the objects and drop the deprecated stored version", func() {
+			crd := newCRD(crdNameNUMAResourcesOperator, "v1alpha1", "v1")
+			nro := testobjs.NewNUMAResourcesOperator(objectnames.DefaultNUMAResourcesOperatorCrName, nil)
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(crd, nro).WithStatusSubresource(crd, nro).Build()
+
+			instance := &nropv1.NUMAResourcesOperator{}
+			Expect(cli.Get(context.TODO(), client.ObjectKeyFromObject(nro), instance)).To(Succeed())
+			oldResourceVersion := instance.ResourceVersion
+
+			cond := storedVersionUpgradeableCondition(context.TODO(), cli, crdNameNUMAResourcesOperator, &nropv1.NUMAResourcesOperatorList{}, instance)
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+
+			updatedCRD := &apiextensionsv1.CustomResourceDefinition{}
+			Expect(cli.Get(context.TODO(), client.ObjectKeyFromObject(crd), updatedCRD)).To(Succeed())
+			Expect(updatedCRD.Status.StoredVersions).To(Equal([]string{"v1"}))
+
+			// the instance must be usable to update the status after the migration rewrote it
+			Expect(instance.ResourceVersion).ToNot(Equal(oldResourceVersion))
+			Expect(cli.Status().Update(context.TODO(), instance)).To(Succeed())
+		})
+
+		It("should not be upgradeable if the migration fails", func() {
+			crd := newCRD(crdNameNUMAResourcesOperator, "v1alpha1", "v1")
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(crd).WithInterceptorFuncs(interceptor.Funcs{
+				SubResourceUpdate: func(ctx context.Context, client client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+					return fmt.Errorf("fake failure")
+				},
+			}).Build()
+			cond := storedVersionUpgradeableCondition(context.TODO(), cli, crdNameNUMAResourcesOperator, &nropv1.NUMAResourcesOperatorList{}, &nropv1.NUMAResourcesOperator{})
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(status.ReasonDeprecatedVersionStored))
+		})
+	})
+
+	Context("with the NUMAResourcesOperator", func() {
+		It("should not be upgradeable if degraded", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			cond := operatorUpgradeableCondition(context.TODO(), cli, &nropv1.NUMAResourcesOperator{}, nil, status.Degraded(status.ReasonDaemonSetSyncFailed, "fake failure"))
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(status.ReasonDaemonSetSyncFailed))
+		})
+
+		It("should not be upgradeable while a node group is rolling out", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			nro := &nropv1.NUMAResourcesOperator{}
+			ngConds, _ := status.GetUpdatedConditions(nil, status.ConditionProgressing, status.ReasonDaemonSetRollingOut, "rolling out", 1)
+			nro.Status.NodeGroups = []nropv1.NodeGroupStatus{
+				{
+					Name:       "fake-group",
+					Conditions: ngConds,
+				},
+			}
+			cond := operatorUpgradeableCondition(context.TODO(), cli, nro, nil, status.Available())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(status.ReasonDaemonSetRollingOut))
+		})
+
+		It("should be upgradeable if available", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			cond := operatorUpgradeableCondition(context.TODO(), cli, &nropv1.NUMAResourcesOperator{}, nil, status.Available())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+		})
+	})
+
+	Context("with the NUMAResourcesScheduler", func() {
+		It("should not be upgradeable while the scheduler is not available", func() {
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+			cond := schedulerUpgradeableCondition(context.TODO(), cli, &nropv1.NUMAResourcesScheduler{}, status.Progressing(status.ReasonDeploymentNotReady, "waiting"))
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(status.ReasonSchedulerNotAvailable))
+		})
+
+		It("should be upgradeable once the deprecated stored version is migrated", func() {
+			crd := newCRD(crdNameNUMAResourcesScheduler, "v1alpha1")
+			cli := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(crd).WithStatusSubresource(crd).Build()
+			cond := schedulerUpgradeableCondition(context.TODO(), cli, &nropv1.NUMAResourcesScheduler{}, status.Available())
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+
+			updatedCRD := &apiextensionsv1.CustomResourceDefinition{}
+			Expect(cli.Get(context.TODO(), client.ObjectKeyFromObject(crd), updatedCRD)).To(Succeed())
+			Expect(updatedCRD.Status.StoredVersions).To(Equal([]string{"v1"}))
+		})
+	})
+})
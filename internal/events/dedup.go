@@ -0,0 +1,78 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type eventKey struct {
+	uid    types.UID
+	object types.NamespacedName
+	reason string
+}
+
+// Deduplicator drops the events identical to the last one recorded for the same object and reason.
+// The reconcilers observe the same state over and over while they wait for the cluster to settle,
+// and without deduplication they would record the same event at each reconcile.
+// The zero value is ready to use.
+type Deduplicator struct {
+	lock sync.Mutex
+	last map[eventKey]string
+}
+
+// Eventf records the event on obj, unless the last event recorded for obj with the same reason had the same message.
+// Returns true if the event was recorded.
+func (d *Deduplicator) Eventf(rec record.EventRecorder, obj client.Object, eventtype, reason, messageFmt string, args ...interface{}) bool {
+	message := fmt.Sprintf(messageFmt, args...)
+	key := keyFor(obj, reason)
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if msg, ok := d.last[key]; ok && msg == message {
+		return false
+	}
+	if d.last == nil {
+		d.last = make(map[eventKey]string)
+	}
+	d.last[key] = message
+	rec.Event(obj, eventtype, reason, message)
+	return true
+}
+
+// Forget drops the memory of the events recorded on obj with the given reasons, so the next ones are recorded
+// even if identical. Should be called once the state reported by the events is over.
+func (d *Deduplicator) Forget(obj client.Object, reasons ...string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for _, reason := range reasons {
+		delete(d.last, keyFor(obj, reason))
+	}
+}
+
+func keyFor(obj client.Object, reason string) eventKey {
+	return eventKey{
+		uid:    obj.GetUID(),
+		object: client.ObjectKeyFromObject(obj),
+		reason: reason,
+	}
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestDeduplicatorEventf(t *testing.T) {
+	objA := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "a", UID: "uid-a"}}
+	objB := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "b", UID: "uid-b"}}
+
+	type entry struct {
+		obj     *corev1.ConfigMap
+		reason  string
+		message string
+		forget  bool
+	}
+	testCases := []struct {
+		name     string
+		records  []entry
+		expected []bool
+	}{
+		{
+			name: "repeated event",
+			records: []entry{
+				{obj: objA, reason: "Waiting", message: "waiting for foo"},
+				{obj: objA, reason: "Waiting", message: "waiting for foo"},
+			},
+			expected: []bool{true, false},
+		},
+		{
+			name: "changed message",
+			records: []entry{
+				{obj: objA, reason: "Waiting", message: "waiting for foo"},
+				{obj: objA, reason: "Waiting", message: "waiting for bar"},
+				{obj: objA, reason: "Waiting", message: "waiting for foo"},
+			},
+			expected: []bool{true, true, true},
+		},
+		{
+			name: "different reasons and objects",
+			records: []entry{
+				{obj: objA, reason: "Waiting", message: "waiting for foo"},
+				{obj: objA, reason: "Failed", message: "waiting for foo"},
+				{obj: objB, reason: "Waiting", message: "waiting for foo"},
+			},
+			expected: []bool{true, true, true},
+		},
+		{
+			name: "forgotten event",
+			records: []entry{
+				{obj: objA, reason: "Waiting", message: "waiting for foo"},
+				{obj: objA, reason: "Waiting", forget: true},
+				{obj: objA, reason: "Waiting", message: "waiting for foo"},
+			},
+			expected: []bool{true, false, true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := record.NewFakeRecorder(16)
+			dedup := Deduplicator{}
+			for idx, rr := range tc.records {
+				got := false
+				if rr.forget {
+					dedup.Forget(rr.obj, rr.reason)
+				} else {
+					got = dedup.Eventf(rec, rr.obj, corev1.EventTypeNormal, rr.reason, "%s", rr.message)
+				}
+				if got != tc.expected[idx] {
+					t.Errorf("record #%d: got %v expected %v", idx, got, tc.expected[idx])
+				}
+			}
+
+			expectedEvents := 0
+			for _, exp := range tc.expected {
+				if exp {
+					expectedEvents++
+				}
+			}
+			if len(rec.Events) != expectedEvents {
+				t.Errorf("recorded %d events expected %d", len(rec.Events), expectedEvents)
+			}
+		})
+	}
+}
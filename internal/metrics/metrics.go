@@ -0,0 +1,128 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package metrics exposes the state managed by the operator controllers as prometheus metrics,
+// served by the controller-runtime metrics server.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	"github.com/openshift-kni/numaresources-operator/pkg/status"
+)
+
+// controller names, as reported in the metrics labels
+const (
+	ControllerNUMAResourcesOperator  = "numaresourcesoperator"
+	ControllerNUMAResourcesScheduler = "numaresourcesscheduler"
+	ControllerKubeletConfig          = "kubeletconfig"
+)
+
+// RTE daemonset pods states, as reported in the metrics labels
+const (
+	PodsDesired = "desired"
+	PodsReady   = "ready"
+	PodsUpdated = "updated"
+)
+
+var (
+	ReconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nrop_reconcile_total",
+		Help: "The total number of reconciliations, by controller and by reported condition and reason",
+	}, []string{"controller", "condition", "reason"})
+
+	NodeGroups = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "nrop_node_groups",
+		Help: "The number of node groups, one per MachineConfigPool for the node groups selecting them",
+	})
+
+	MachineConfigPoolsUpdating = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "nrop_machineconfigpools_updating",
+		Help: "The number of MachineConfigPools still applying the operator machine configuration",
+	})
+
+	RTEDaemonSetPods = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nrop_rte_daemonset_pods",
+		Help: "The number of RTE pods per node group, by state",
+	}, []string{"node_group", "state"})
+
+	SchedulerAvailable = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "nrop_scheduler_available",
+		Help: "Whether the NUMA-aware scheduler is available (1) or not (0)",
+	})
+
+	KubeletConfigSyncFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nrop_kubeletconfig_sync_failures_total",
+		Help: "The total number of failures syncing a KubeletConfig to the RTE ConfigMap",
+	}, []string{"kubeletconfig"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		ReconcileTotal,
+		NodeGroups,
+		MachineConfigPoolsUpdating,
+		RTEDaemonSetPods,
+		SchedulerAvailable,
+		KubeletConfigSyncFailures,
+	)
+}
+
+// UpdateReconcileMetric records a reconciliation of the given controller, which reported cond
+func UpdateReconcileMetric(controller string, cond status.ConditionInfo) {
+	reason := cond.Reason
+	if reason == "" {
+		reason = status.ReasonAsExpected
+	}
+	ReconcileTotal.With(prometheus.Labels{
+		"controller": controller,
+		"condition":  cond.Type,
+		"reason":     reason,
+	}).Inc()
+}
+
+// UpdateNodeGroupsMetrics records the state of the node groups reported in the NUMAResourcesOperator status
+func UpdateNodeGroupsMetrics(ngStatuses []nropv1.NodeGroupStatus) {
+	NodeGroups.Set(float64(len(ngStatuses)))
+	// drop the node groups which don't exist anymore
+	RTEDaemonSetPods.Reset()
+	for _, ngStatus := range ngStatuses {
+		RTEDaemonSetPods.WithLabelValues(ngStatus.Name, PodsDesired).Set(float64(ngStatus.DesiredPods))
+		RTEDaemonSetPods.WithLabelValues(ngStatus.Name, PodsReady).Set(float64(ngStatus.ReadyPods))
+		RTEDaemonSetPods.WithLabelValues(ngStatus.Name, PodsUpdated).Set(float64(ngStatus.UpdatedPods))
+	}
+}
+
+// UpdateMachineConfigPoolsUpdatingMetric records the number of MachineConfigPools still applying the operator machine configuration
+func UpdateMachineConfigPoolsUpdatingMetric(count int) {
+	MachineConfigPoolsUpdating.Set(float64(count))
+}
+
+// UpdateSchedulerAvailableMetric records the availability of the NUMA-aware scheduler
+func UpdateSchedulerAvailableMetric(available bool) {
+	if available {
+		SchedulerAvailable.Set(1)
+		return
+	}
+	SchedulerAvailable.Set(0)
+}
+
+// UpdateKubeletConfigSyncFailuresMetric records a failure syncing the given KubeletConfig
+func UpdateKubeletConfigSyncFailuresMetric(kubeletConfigName string) {
+	KubeletConfigSyncFailures.WithLabelValues(kubeletConfigName).Inc()
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	"github.com/openshift-kni/numaresources-operator/pkg/status"
+)
+
+func TestUpdateReconcileMetric(t *testing.T) {
+	ReconcileTotal.Reset()
+
+	UpdateReconcileMetric(ControllerNUMAResourcesOperator, status.Available())
+	UpdateReconcileMetric(ControllerNUMAResourcesOperator, status.Available())
+	UpdateReconcileMetric(ControllerNUMAResourcesOperator, status.Degraded(status.ReasonDaemonSetSyncFailed, "fake failure"))
+
+	if got := testutil.ToFloat64(ReconcileTotal.WithLabelValues(ControllerNUMAResourcesOperator, status.ConditionAvailable, status.ReasonAsExpected)); got != 2 {
+		t.Errorf("unexpected available reconciles: got %v expected 2", got)
+	}
+	if got := testutil.ToFloat64(ReconcileTotal.WithLabelValues(ControllerNUMAResourcesOperator, status.ConditionDegraded, status.ReasonDaemonSetSyncFailed)); got != 1 {
+		t.Errorf("unexpected degraded reconciles: got %v expected 1", got)
+	}
+}
+
+func TestUpdateNodeGroupsMetrics(t *testing.T) {
+	UpdateNodeGroupsMetrics([]nropv1.NodeGroupStatus{
+		{
+			Name:        "group-a",
+			DesiredPods: 3,
+			ReadyPods:   2,
+			UpdatedPods: 1,
+		},
+		{
+			Name:        "group-b",
+			DesiredPods: 2,
+			ReadyPods:   2,
+			UpdatedPods: 2,
+		},
+	})
+
+	if got := testutil.ToFloat64(NodeGroups); got != 2 {
+		t.Errorf("unexpected node groups: got %v expected 2", got)
+	}
+	if got := testutil.ToFloat64(RTEDaemonSetPods.WithLabelValues("group-a", PodsReady)); got != 2 {
+		t.Errorf("unexpected ready pods: got %v expected 2", got)
+	}
+	if got := testutil.ToFloat64(RTEDaemonSetPods.WithLabelValues("group-a", PodsUpdated)); got != 1 {
+		t.Errorf("unexpected updated pods: got %v expected 1", got)
+	}
+
+	UpdateNodeGroupsMetrics([]nropv1.NodeGroupStatus{
+		{
+			Name:        "group-b",
+			DesiredPods: 2,
+			ReadyPods:   2,
+			UpdatedPods: 2,
+		},
+	})
+
+	if got := testutil.ToFloat64(NodeGroups); got != 1 {
+		t.Errorf("unexpected node groups: got %v expected 1", got)
+	}
+	// one series per state of the remaining node group
+	if got := testutil.CollectAndCount(RTEDaemonSetPods); got != 3 {
+		t.Errorf("unexpected RTE pods series: got %v expected 3", got)
+	}
+}
+
+func TestUpdateSchedulerAvailableMetric(t *testing.T) {
+	UpdateSchedulerAvailableMetric(true)
+	if got := testutil.ToFloat64(SchedulerAvailable); got != 1 {
+		t.Errorf("unexpected scheduler availability: got %v expected 1", got)
+	}
+	UpdateSchedulerAvailableMetric(false)
+	if got := testutil.ToFloat64(SchedulerAvailable); got != 0 {
+		t.Errorf("unexpected scheduler availability: got %v expected 0", got)
+	}
+}
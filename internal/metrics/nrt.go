@@ -0,0 +1,190 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nrtv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	intnrt "github.com/openshift-kni/numaresources-operator/internal/noderesourcetopology"
+)
+
+const (
+	// nrtListTimeout bounds the time spent listing the NRT objects at each scrape
+	nrtListTimeout = 10 * time.Second
+
+	// zoneTypeNode is the type of the zones representing the NUMA nodes
+	zoneTypeNode = "Node"
+)
+
+var (
+	nrtCapacityDesc = prometheus.NewDesc(
+		"nrop_nrt_resource_capacity",
+		"The capacity of the resource in the NUMA zone, as reported in the NodeResourceTopology objects",
+		[]string{"node", "zone", "resource"}, nil,
+	)
+	nrtAllocatableDesc = prometheus.NewDesc(
+		"nrop_nrt_resource_allocatable",
+		"The allocatable amount of the resource in the NUMA zone, as reported in the NodeResourceTopology objects",
+		[]string{"node", "zone", "resource"}, nil,
+	)
+	nrtAvailableDesc = prometheus.NewDesc(
+		"nrop_nrt_resource_available",
+		"The available amount of the resource in the NUMA zone, as reported in the NodeResourceTopology objects",
+		[]string{"node", "zone", "resource"}, nil,
+	)
+	nrtTopologyManagerDesc = prometheus.NewDesc(
+		"nrop_nrt_topology_manager_info",
+		"The topology manager configuration of the node, as reported in the NodeResourceTopology objects",
+		[]string{"node", "policy", "scope"}, nil,
+	)
+)
+
+// NRTOptions controls the cardinality of the NRT metrics
+type NRTOptions struct {
+	// Resources are the names of the resources to report. All the resources are reported if empty.
+	Resources []string
+	// AggregateZones reports the resources summed across all the zones of a node, with an empty zone label,
+	// instead of reporting them per zone.
+	AggregateZones bool
+}
+
+// NRTCollector exports the capacity of the NUMA zones reported in the NodeResourceTopology objects.
+// The objects are read at each scrape, so the nodes and the zones which disappear stop being reported.
+type NRTCollector struct {
+	cli       client.Reader
+	resources sets.Set[string]
+	aggregate bool
+}
+
+// NewNRTCollector creates a collector reading the NodeResourceTopology objects using the given client,
+// which is expected to be backed by a cache.
+func NewNRTCollector(cli client.Reader, opts NRTOptions) *NRTCollector {
+	return &NRTCollector{
+		cli:       cli,
+		resources: sets.New[string](opts.Resources...),
+		aggregate: opts.AggregateZones,
+	}
+}
+
+func (nc *NRTCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- nrtCapacityDesc
+	ch <- nrtAllocatableDesc
+	ch <- nrtAvailableDesc
+	ch <- nrtTopologyManagerDesc
+}
+
+func (nc *NRTCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), nrtListTimeout)
+	defer cancel()
+
+	nrts := nrtv1alpha2.NodeResourceTopologyList{}
+	if err := nc.cli.List(ctx, &nrts); err != nil {
+		if !meta.IsNoMatchError(err) {
+			klog.ErrorS(err, "cannot list the NodeResourceTopology objects")
+		}
+		// the API may not be installed yet
+		return
+	}
+
+	for idx := range nrts.Items {
+		nrt := &nrts.Items[idx] // shortcut
+		nc.collectTopologyManager(ch, nrt)
+		if nc.aggregate {
+			nc.collectNodeResources(ch, nrt)
+			continue
+		}
+		for _, zone := range nrt.Zones {
+			if zone.Type != zoneTypeNode {
+				continue
+			}
+			for _, res := range zone.Resources {
+				if !nc.wantsResource(res.Name) {
+					continue
+				}
+				collectResource(ch, nrt.Name, zone.Name, res.Name, res.Capacity, res.Allocatable, res.Available)
+			}
+		}
+	}
+}
+
+func (nc *NRTCollector) collectTopologyManager(ch chan<- prometheus.Metric, nrt *nrtv1alpha2.NodeResourceTopology) {
+	var policy, scope string
+	for _, attr := range nrt.Attributes {
+		switch attr.Name {
+		case intnrt.TopologyManagerPolicyAttribute:
+			policy = attr.Value
+		case intnrt.TopologyManagerScopeAttribute:
+			scope = attr.Value
+		}
+	}
+	if policy == "" {
+		// older RTEs don't report the attributes
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(nrtTopologyManagerDesc, prometheus.GaugeValue, 1, nrt.Name, policy, scope)
+}
+
+func (nc *NRTCollector) collectNodeResources(ch chan<- prometheus.Metric, nrt *nrtv1alpha2.NodeResourceTopology) {
+	type quantities struct {
+		capacity, allocatable, available resource.Quantity
+	}
+	var names []string
+	totals := make(map[string]*quantities)
+	for _, zone := range nrt.Zones {
+		if zone.Type != zoneTypeNode {
+			continue
+		}
+		for _, res := range zone.Resources {
+			if !nc.wantsResource(res.Name) {
+				continue
+			}
+			tot, ok := totals[res.Name]
+			if !ok {
+				tot = &quantities{}
+				totals[res.Name] = tot
+				names = append(names, res.Name)
+			}
+			tot.capacity.Add(res.Capacity)
+			tot.allocatable.Add(res.Allocatable)
+			tot.available.Add(res.Available)
+		}
+	}
+	for _, name := range names {
+		tot := totals[name]
+		collectResource(ch, nrt.Name, "", name, tot.capacity, tot.allocatable, tot.available)
+	}
+}
+
+func (nc *NRTCollector) wantsResource(name string) bool {
+	return nc.resources.Len() == 0 || nc.resources.Has(name)
+}
+
+func collectResource(ch chan<- prometheus.Metric, nodeName, zoneName, resName string, capacity, allocatable, available resource.Quantity) {
+	ch <- prometheus.MustNewConstMetric(nrtCapacityDesc, prometheus.GaugeValue, capacity.AsApproximateFloat64(), nodeName, zoneName, resName)
+	ch <- prometheus.MustNewConstMetric(nrtAllocatableDesc, prometheus.GaugeValue, allocatable.AsApproximateFloat64(), nodeName, zoneName, resName)
+	ch <- prometheus.MustNewConstMetric(nrtAvailableDesc, prometheus.GaugeValue, available.AsApproximateFloat64(), nodeName, zoneName, resName)
+}
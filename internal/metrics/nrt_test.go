@@ -0,0 +1,141 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	nrtv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+
+	intnrt "github.com/openshift-kni/numaresources-operator/internal/noderesourcetopology"
+)
+
+func newTestNRT(name string) *nrtv1alpha2.NodeResourceTopology {
+	newZone := func(zoneName, cpus, mem string) nrtv1alpha2.Zone {
+		return nrtv1alpha2.Zone{
+			Name: zoneName,
+			Type: "Node",
+			Resources: nrtv1alpha2.ResourceInfoList{
+				{
+					Name:        string(corev1.ResourceCPU),
+					Capacity:    resource.MustParse(cpus),
+					Allocatable: resource.MustParse(cpus),
+					Available:   resource.MustParse("2"),
+				},
+				{
+					Name:        string(corev1.ResourceMemory),
+					Capacity:    resource.MustParse(mem),
+					Allocatable: resource.MustParse(mem),
+					Available:   resource.MustParse(mem),
+				},
+			},
+		}
+	}
+	return &nrtv1alpha2.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Attributes: nrtv1alpha2.AttributeList{
+			{
+				Name:  intnrt.TopologyManagerPolicyAttribute,
+				Value: "single-numa-node",
+			},
+			{
+				Name:  intnrt.TopologyManagerScopeAttribute,
+				Value: "container",
+			},
+		},
+		Zones: nrtv1alpha2.ZoneList{
+			newZone("node-0", "8", "16Gi"),
+			newZone("node-1", "8", "16Gi"),
+			{
+				Name: "cache-0",
+				Type: "Cache",
+			},
+		},
+	}
+}
+
+func newTestNRTCollector(t *testing.T, opts NRTOptions) *NRTCollector {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := nrtv1alpha2.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot setup the scheme: %v", err)
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newTestNRT("worker-0")).Build()
+	return NewNRTCollector(cli, opts)
+}
+
+func TestNRTCollectorPerZone(t *testing.T) {
+	nc := newTestNRTCollector(t, NRTOptions{})
+
+	// 2 NUMA zones, 2 resources, 3 metrics per resource, plus the topology manager info
+	if got := testutil.CollectAndCount(nc); got != 13 {
+		t.Errorf("unexpected series: got %v expected 13", got)
+	}
+
+	expected := `
+# HELP nrop_nrt_resource_available The available amount of the resource in the NUMA zone, as reported in the NodeResourceTopology objects
+# TYPE nrop_nrt_resource_available gauge
+nrop_nrt_resource_available{node="worker-0",resource="cpu",zone="node-0"} 2
+nrop_nrt_resource_available{node="worker-0",resource="cpu",zone="node-1"} 2
+nrop_nrt_resource_available{node="worker-0",resource="memory",zone="node-0"} 1.7179869184e+10
+nrop_nrt_resource_available{node="worker-0",resource="memory",zone="node-1"} 1.7179869184e+10
+# HELP nrop_nrt_topology_manager_info The topology manager configuration of the node, as reported in the NodeResourceTopology objects
+# TYPE nrop_nrt_topology_manager_info gauge
+nrop_nrt_topology_manager_info{node="worker-0",policy="single-numa-node",scope="container"} 1
+`
+	if err := testutil.CollectAndCompare(nc, strings.NewReader(expected), "nrop_nrt_resource_available", "nrop_nrt_topology_manager_info"); err != nil {
+		t.Errorf("unexpected metrics: %v", err)
+	}
+}
+
+func TestNRTCollectorCardinality(t *testing.T) {
+	nc := newTestNRTCollector(t, NRTOptions{
+		Resources:      []string{string(corev1.ResourceCPU)},
+		AggregateZones: true,
+	})
+
+	expected := `
+# HELP nrop_nrt_resource_capacity The capacity of the resource in the NUMA zone, as reported in the NodeResourceTopology objects
+# TYPE nrop_nrt_resource_capacity gauge
+nrop_nrt_resource_capacity{node="worker-0",resource="cpu",zone=""} 16
+# HELP nrop_nrt_resource_available The available amount of the resource in the NUMA zone, as reported in the NodeResourceTopology objects
+# TYPE nrop_nrt_resource_available gauge
+nrop_nrt_resource_available{node="worker-0",resource="cpu",zone=""} 4
+`
+	if err := testutil.CollectAndCompare(nc, strings.NewReader(expected), "nrop_nrt_resource_capacity", "nrop_nrt_resource_available"); err != nil {
+		t.Errorf("unexpected metrics: %v", err)
+	}
+}
+
+func TestNRTCollectorMissingAPI(t *testing.T) {
+	// the NRT API is not registered, so the objects cannot be listed
+	cli := fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build()
+	nc := NewNRTCollector(cli, NRTOptions{})
+	if got := testutil.CollectAndCount(nc); got != 0 {
+		t.Errorf("unexpected series: got %v expected 0", got)
+	}
+}
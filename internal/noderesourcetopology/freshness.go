@@ -0,0 +1,75 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package noderesourcetopology
+
+import (
+	"time"
+
+	nrtv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/k8sannotations"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	"github.com/openshift-kni/numaresources-operator/rte/pkg/heartbeat"
+)
+
+// StaleFactor is how many refresh periods can be missed before a NRT object is considered stale
+const StaleFactor = 3
+
+// ExpectedUpdateInterval returns the interval at which RTE is expected to refresh the NRT object,
+// or 0 if no periodic refresh is expected. The interval advertised by RTE itself (--expose-timing)
+// takes precedence over the node group configuration.
+func ExpectedUpdateInterval(nrt *nrtv1alpha2.NodeResourceTopology, conf nropv1.NodeGroupConfig) time.Duration {
+	if conf.InfoRefreshPause != nil && *conf.InfoRefreshPause == nropv1.InfoRefreshPauseEnabled {
+		return 0
+	}
+	if conf.InfoRefreshMode != nil && *conf.InfoRefreshMode == nropv1.InfoRefreshEvents {
+		return 0
+	}
+	// updates triggered by events advertise a zero interval
+	if interval, ok := annotationDuration(nrt, k8sannotations.UpdateInterval); ok && interval > 0 {
+		return interval
+	}
+	if conf.InfoRefreshPeriod == nil {
+		return 0
+	}
+	return conf.InfoRefreshPeriod.Duration
+}
+
+// IsStale returns true if RTE did not publish any update (--expose-last-update) for more than StaleFactor
+// refresh periods before now, which happens when RTE is paused, crashed or stuck.
+// RTE stamps every update with its time, so the updates of idle nodes are written too and keep the object fresh.
+// NRT objects without the last update annotation, e.g. published by older RTE versions, are never considered stale.
+func IsStale(nrt *nrtv1alpha2.NodeResourceTopology, conf nropv1.NodeGroupConfig, now time.Time) bool {
+	interval := ExpectedUpdateInterval(nrt, conf)
+	if interval <= 0 {
+		return false
+	}
+	lastUpdate, ok := heartbeat.LastUpdate(nrt.Annotations)
+	return ok && now.Sub(lastUpdate) > StaleFactor*interval
+}
+
+func annotationDuration(nrt *nrtv1alpha2.NodeResourceTopology, key string) (time.Duration, bool) {
+	val, ok := nrt.Annotations[key]
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
@@ -0,0 +1,193 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package noderesourcetopology
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nrtv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
+	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/k8sannotations"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	"github.com/openshift-kni/numaresources-operator/rte/pkg/heartbeat"
+)
+
+func TestIsStale(t *testing.T) {
+	pauseEnabled := nropv1.InfoRefreshPauseEnabled
+	refreshEvents := nropv1.InfoRefreshEvents
+
+	confWithPause := nropv1.DefaultNodeGroupConfig()
+	confWithPause.InfoRefreshPause = &pauseEnabled
+	confWithEvents := nropv1.DefaultNodeGroupConfig()
+	confWithEvents.InfoRefreshMode = &refreshEvents
+	confWithLongPeriod := nropv1.DefaultNodeGroupConfig()
+	confWithLongPeriod.InfoRefreshPeriod = &metav1.Duration{Duration: 2 * time.Hour}
+
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	updatedAgo := func(d time.Duration) string {
+		return now.Add(-d).Format(time.RFC3339)
+	}
+
+	testCases := []struct {
+		name        string
+		nrt         nrtv1alpha2.NodeResourceTopology
+		conf        nropv1.NodeGroupConfig
+		expected    bool
+		expectedInt time.Duration
+	}{
+		{
+			name:        "no annotations",
+			nrt:         makeNRT(nil),
+			conf:        nropv1.DefaultNodeGroupConfig(),
+			expected:    false,
+			expectedInt: 10 * time.Second,
+		},
+		{
+			name: "no last update annotation",
+			nrt: makeNRT(map[string]string{
+				k8sannotations.UpdateInterval: "10s",
+				k8sannotations.SleepDuration:  "1m0s",
+			}),
+			conf:        nropv1.DefaultNodeGroupConfig(),
+			expected:    false,
+			expectedInt: 10 * time.Second,
+		},
+		{
+			name: "refreshed on time",
+			nrt: makeNRT(map[string]string{
+				k8sannotations.UpdateInterval:  "10s",
+				k8sannotations.SleepDuration:   "10s",
+				heartbeat.LastUpdateAnnotation: updatedAgo(5 * time.Second),
+			}),
+			conf:        nropv1.DefaultNodeGroupConfig(),
+			expected:    false,
+			expectedInt: 10 * time.Second,
+		},
+		{
+			name: "refreshed on time after a long sleep",
+			nrt: makeNRT(map[string]string{
+				k8sannotations.UpdateInterval:  "10s",
+				k8sannotations.SleepDuration:   "5m0s",
+				heartbeat.LastUpdateAnnotation: updatedAgo(time.Second),
+			}),
+			conf:        nropv1.DefaultNodeGroupConfig(),
+			expected:    false,
+			expectedInt: 10 * time.Second,
+		},
+		{
+			name: "stopped updating",
+			nrt: makeNRT(map[string]string{
+				k8sannotations.UpdateInterval:  "10s",
+				k8sannotations.SleepDuration:   "10s",
+				heartbeat.LastUpdateAnnotation: updatedAgo(time.Minute),
+			}),
+			conf:        nropv1.DefaultNodeGroupConfig(),
+			expected:    true,
+			expectedInt: 10 * time.Second,
+		},
+		{
+			name: "stopped updating, interval from the configuration",
+			nrt: makeNRT(map[string]string{
+				heartbeat.LastUpdateAnnotation: updatedAgo(time.Minute),
+			}),
+			conf:        nropv1.DefaultNodeGroupConfig(),
+			expected:    true,
+			expectedInt: 10 * time.Second,
+		},
+		{
+			name: "refreshed by an event, interval from the configuration",
+			nrt: makeNRT(map[string]string{
+				k8sannotations.UpdateInterval:  "0s",
+				k8sannotations.SleepDuration:   "2s",
+				heartbeat.LastUpdateAnnotation: updatedAgo(time.Minute),
+			}),
+			conf:        nropv1.DefaultNodeGroupConfig(),
+			expected:    true,
+			expectedInt: 10 * time.Second,
+		},
+		{
+			name: "not updated recently, long period",
+			nrt: makeNRT(map[string]string{
+				heartbeat.LastUpdateAnnotation: updatedAgo(time.Minute),
+			}),
+			conf:        confWithLongPeriod,
+			expected:    false,
+			expectedInt: 2 * time.Hour,
+		},
+		{
+			name: "not updated recently, interval advertised by RTE",
+			nrt: makeNRT(map[string]string{
+				k8sannotations.UpdateInterval:  "2h0m0s",
+				heartbeat.LastUpdateAnnotation: updatedAgo(time.Minute),
+			}),
+			conf:        nropv1.DefaultNodeGroupConfig(),
+			expected:    false,
+			expectedInt: 2 * time.Hour,
+		},
+		{
+			name: "stopped updating, paused",
+			nrt: makeNRT(map[string]string{
+				heartbeat.LastUpdateAnnotation: updatedAgo(time.Hour),
+			}),
+			conf:        confWithPause,
+			expected:    false,
+			expectedInt: 0,
+		},
+		{
+			name: "stopped updating, events only",
+			nrt: makeNRT(map[string]string{
+				heartbeat.LastUpdateAnnotation: updatedAgo(time.Hour),
+			}),
+			conf:        confWithEvents,
+			expected:    false,
+			expectedInt: 0,
+		},
+		{
+			name: "malformed last update",
+			nrt: makeNRT(map[string]string{
+				heartbeat.LastUpdateAnnotation: "yesterday",
+			}),
+			conf:        nropv1.DefaultNodeGroupConfig(),
+			expected:    false,
+			expectedInt: 10 * time.Second,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			gotInt := ExpectedUpdateInterval(&tt.nrt, tt.conf)
+			if gotInt != tt.expectedInt {
+				t.Errorf("ExpectedUpdateInterval error: got=%v expected=%v", gotInt, tt.expectedInt)
+			}
+			got := IsStale(&tt.nrt, tt.conf, now)
+			if got != tt.expected {
+				t.Errorf("IsStale error: got=%v expected=%v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func makeNRT(annotations map[string]string) nrtv1alpha2.NodeResourceTopology {
+	return nrtv1alpha2.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node-0",
+			Annotations: annotations,
+		},
+	}
+}
@@ -71,6 +71,27 @@ func NewNUMAResourcesOperatorWithNodeGroupConfig(name string, selector *metav1.L
 	}
 }
 
+func NewNUMAResourcesOperatorWithNodeSelector(name, nodeGroupName string, selector *metav1.LabelSelector, conf *nropv1.NodeGroupConfig) *nropv1.NUMAResourcesOperator {
+	return &nropv1.NUMAResourcesOperator{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NUMAResourcesOperator",
+			APIVersion: nropv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: nropv1.NUMAResourcesOperatorSpec{
+			NodeGroups: []nropv1.NodeGroup{
+				{
+					Name:         nodeGroupName,
+					NodeSelector: selector,
+					Config:       conf,
+				},
+			},
+		},
+	}
+}
+
 func NewNUMAResourcesScheduler(name, imageSpec, schedulerName string, resyncPeriod time.Duration) *nropv1.NUMAResourcesScheduler {
 	return &nropv1.NUMAResourcesScheduler{
 		TypeMeta: metav1.TypeMeta{
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schedcache
+
+import (
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	"k8s.io/klog/v2"
+)
+
+// ForwardedAuthorizationHeader carries the credentials of the clients fetching the status through the API server pods proxy.
+// The API server consumes the Authorization header of the requests it authenticates, so it never reaches the proxied pod.
+const ForwardedAuthorizationHeader = "X-Forwarded-Authorization"
+
+// Reviewer delegates the authentication and the authorization of the status requests to the API server.
+// A client is allowed to fetch the status if it can proxy to the pod serving it, which is the access
+// the operator already needs to reach the scheduler replicas.
+type Reviewer struct {
+	TokenReviews         authenticationv1client.TokenReviewInterface
+	SubjectAccessReviews authorizationv1client.SubjectAccessReviewInterface
+	// Namespace and PodName identify the pod serving the status
+	Namespace string
+	PodName   string
+}
+
+// WithDelegatedAuth wraps the given handler so that every request but the liveness probe must carry
+// a bearer token, which the API server must authenticate (401 otherwise) and authorize (403 otherwise).
+// The token is taken from the Authorization header, or from the ForwardedAuthorizationHeader if missing.
+func WithDelegatedAuth(hnd http.Handler, rev Reviewer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == HealthzPath {
+			hnd.ServeHTTP(w, req)
+			return
+		}
+
+		token, ok := bearerToken(req)
+		if !ok {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		tr, err := rev.TokenReviews.Create(req.Context(), &authenticationv1.TokenReview{
+			Spec: authenticationv1.TokenReviewSpec{
+				Token: token,
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			klog.ErrorS(err, "cannot review the request token")
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if !tr.Status.Authenticated {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		user := tr.Status.User
+		extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+		for key, val := range user.Extra {
+			extra[key] = authorizationv1.ExtraValue(val)
+		}
+		sar, err := rev.SubjectAccessReviews.Create(req.Context(), &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:   user.Username,
+				UID:    user.UID,
+				Groups: user.Groups,
+				Extra:  extra,
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace:   rev.Namespace,
+					Verb:        "get",
+					Resource:    "pods",
+					Subresource: "proxy",
+					Name:        rev.PodName,
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			klog.ErrorS(err, "cannot review the request access", "user", user.Username)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if !sar.Status.Allowed {
+			klog.V(2).InfoS("denied the access to the scheduler cache state", "user", user.Username, "reason", sar.Status.Reason)
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		hnd.ServeHTTP(w, req)
+	})
+}
+
+func bearerToken(req *http.Request) (string, bool) {
+	auth := req.Header.Get("Authorization")
+	if auth == "" {
+		auth = req.Header.Get(ForwardedAuthorizationHeader)
+	}
+	scheme, token, ok := strings.Cut(auth, " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
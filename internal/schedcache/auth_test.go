@@ -0,0 +1,179 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schedcache
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	operatorToken = "operator-token"
+	otherToken    = "other-token"
+	operatorUser  = "system:serviceaccount:numaresources:numaresources-controller-manager"
+)
+
+type fakeTokenReviews struct {
+	reviewed int
+}
+
+func (ftr *fakeTokenReviews) Create(_ context.Context, tr *authenticationv1.TokenReview, _ metav1.CreateOptions) (*authenticationv1.TokenReview, error) {
+	ftr.reviewed++
+	ret := tr.DeepCopy()
+	switch tr.Spec.Token {
+	case operatorToken:
+		ret.Status.Authenticated = true
+		ret.Status.User.Username = operatorUser
+	case otherToken:
+		ret.Status.Authenticated = true
+		ret.Status.User.Username = "system:serviceaccount:default:default"
+	}
+	return ret, nil
+}
+
+type fakeSubjectAccessReviews struct {
+	lastAttrs *authorizationv1.ResourceAttributes
+	err       error
+}
+
+func (fsar *fakeSubjectAccessReviews) Create(_ context.Context, sar *authorizationv1.SubjectAccessReview, _ metav1.CreateOptions) (*authorizationv1.SubjectAccessReview, error) {
+	if fsar.err != nil {
+		return nil, fsar.err
+	}
+	fsar.lastAttrs = sar.Spec.ResourceAttributes
+	ret := sar.DeepCopy()
+	ret.Status.Allowed = sar.Spec.User == operatorUser
+	return ret, nil
+}
+
+func TestWithDelegatedAuth(t *testing.T) {
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name          string
+		path          string
+		authorization string
+		forwarded     string
+		sarErr        error
+		expectedCode  int
+		expectReview  bool
+	}{
+		{
+			name:         "healthz without token",
+			path:         HealthzPath,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "no token",
+			path:         StatusPath("worker.0"),
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:          "not a bearer token",
+			path:          StatusPath("worker.0"),
+			authorization: "Basic dXNlcjpwYXNz",
+			expectedCode:  http.StatusUnauthorized,
+		},
+		{
+			name:          "invalid token",
+			path:          StatusPath("worker.0"),
+			authorization: "Bearer bogus",
+			expectedCode:  http.StatusUnauthorized,
+			expectReview:  true,
+		},
+		{
+			name:          "unauthorized user",
+			path:          StatusPath("worker.0"),
+			authorization: "Bearer " + otherToken,
+			expectedCode:  http.StatusForbidden,
+			expectReview:  true,
+		},
+		{
+			name:          "authorized user",
+			path:          StatusPath("worker.0"),
+			authorization: "Bearer " + operatorToken,
+			expectedCode:  http.StatusOK,
+			expectReview:  true,
+		},
+		{
+			name:         "authorized user through the pods proxy",
+			path:         StatusPath("worker.0"),
+			forwarded:    "Bearer " + operatorToken,
+			expectedCode: http.StatusOK,
+			expectReview: true,
+		},
+		{
+			name:         "unauthorized user through the pods proxy",
+			path:         StatusPath("worker.0"),
+			forwarded:    "Bearer " + otherToken,
+			expectedCode: http.StatusForbidden,
+			expectReview: true,
+		},
+		{
+			name:          "authorization failure",
+			path:          StatusPath("worker.0"),
+			authorization: "Bearer " + operatorToken,
+			sarErr:        errors.New("fake error"),
+			expectedCode:  http.StatusInternalServerError,
+			expectReview:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ftr := &fakeTokenReviews{}
+			fsar := &fakeSubjectAccessReviews{err: tc.sarErr}
+			handler := WithDelegatedAuth(okHandler, Reviewer{
+				TokenReviews:         ftr,
+				SubjectAccessReviews: fsar,
+				Namespace:            "numaresources",
+				PodName:              "secondary-scheduler-0",
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			if tc.forwarded != "" {
+				req.Header.Set(ForwardedAuthorizationHeader, tc.forwarded)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.expectedCode {
+				t.Fatalf("unexpected code: got=%d expected=%d", rec.Code, tc.expectedCode)
+			}
+			if reviewed := ftr.reviewed > 0; reviewed != tc.expectReview {
+				t.Errorf("unexpected token review: got=%v expected=%v", reviewed, tc.expectReview)
+			}
+			if tc.expectedCode == http.StatusOK && tc.expectReview {
+				attrs := fsar.lastAttrs
+				if attrs == nil || attrs.Namespace != "numaresources" || attrs.Name != "secondary-scheduler-0" || attrs.Resource != "pods" || attrs.Subresource != "proxy" || attrs.Verb != "get" {
+					t.Errorf("unexpected access review attributes: %+v", attrs)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schedcache
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// StatusPathPrefix is the HTTP path under which the status files are served
+	StatusPathPrefix = "/pfpstatus/"
+	// HealthzPath is the HTTP path of the liveness endpoint of the status server
+	HealthzPath = "/healthz"
+
+	statusFileExt = ".json"
+)
+
+// NewStatusHandler returns a handler which serves read-only the status files found in dir,
+// as GET <StatusPathPrefix><file>.json. Only the files directly in dir can be fetched.
+// The handler performs no authentication nor authorization: use WithDelegatedAuth to add them.
+func NewStatusHandler(dir string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthzPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc(StatusPathPrefix, func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		name, ok := statusFileName(req.URL.Path)
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				http.NotFound(w, req)
+				return
+			}
+			klog.ErrorS(err, "cannot read the status file", "name", name)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	})
+	return mux
+}
+
+// ServeStatus serves over HTTPS the status files found in dir on the given address until the context is done.
+// The requests are authenticated and authorized by the given reviewer.
+func ServeStatus(ctx context.Context, addr, dir, certFile, keyFile string, rev Reviewer) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           WithDelegatedAuth(NewStatusHandler(dir), rev),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	klog.InfoS("serving the scheduler cache state", "addr", addr, "dir", dir)
+	err := srv.ListenAndServeTLS(certFile, keyFile)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// StatusPath returns the HTTP path of the status file for the given node
+func StatusPath(nodeName string) string {
+	return StatusPathPrefix + nodeNameToFileName(nodeName) + statusFileExt
+}
+
+func statusFileName(urlPath string) (string, bool) {
+	name := strings.TrimPrefix(path.Clean(urlPath), StatusPathPrefix)
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, statusFileExt) {
+		return "", false
+	}
+	return name, true
+}
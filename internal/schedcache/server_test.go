@@ -0,0 +1,119 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schedcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatusHandler(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "pfpstatus")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "worker_0.json"), []byte(`{"nodeName":"worker.0"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "secret.json"), []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewStatusHandler(dir)
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "healthz",
+			method:       http.MethodGet,
+			path:         HealthzPath,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "existing node",
+			method:       http.MethodGet,
+			path:         StatusPath("worker.0"),
+			expectedCode: http.StatusOK,
+			expectedBody: `{"nodeName":"worker.0"}`,
+		},
+		{
+			name:         "missing node",
+			method:       http.MethodGet,
+			path:         StatusPath("worker.1"),
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "not a status file",
+			method:       http.MethodGet,
+			path:         StatusPathPrefix + "worker_0.txt",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "escaping the directory",
+			method:       http.MethodGet,
+			path:         StatusPathPrefix + "../secret.json",
+			expectedCode: http.StatusMovedPermanently, // to the clean path, outside the served directory,
+		},
+		{
+			name:         "escaping the directory encoded",
+			method:       http.MethodGet,
+			path:         StatusPathPrefix + "..%2fsecret.json",
+			expectedCode: http.StatusMovedPermanently, // to the clean path, outside the served directory,
+		},
+		{
+			name:         "outside the served prefix",
+			method:       http.MethodGet,
+			path:         "/secret.json",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "directory listing",
+			method:       http.MethodGet,
+			path:         StatusPathPrefix,
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "write attempt",
+			method:       http.MethodPut,
+			path:         StatusPath("worker.0"),
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.expectedCode {
+				t.Fatalf("unexpected code: got=%d expected=%d", rec.Code, tc.expectedCode)
+			}
+			if tc.expectedBody != "" && rec.Body.String() != tc.expectedBody {
+				t.Errorf("unexpected body: got=%q expected=%q", rec.Body.String(), tc.expectedBody)
+			}
+		})
+	}
+}
@@ -21,19 +21,27 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
 
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/transport"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/k8stopologyawareschedwg/podfingerprint"
 	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	schedstate "github.com/openshift-kni/numaresources-operator/pkg/numaresourcesscheduler/objectstate/sched"
 	"github.com/openshift-kni/numaresources-operator/pkg/objectnames"
+	schedupdate "github.com/openshift-kni/numaresources-operator/pkg/objectupdate/sched"
 	"github.com/openshift-kni/numaresources-operator/pkg/status"
 
 	"github.com/openshift-kni/numaresources-operator/internal/podlist"
@@ -48,7 +56,9 @@ type Env struct {
 	Ctx    context.Context
 	Cli    client.Client
 	K8sCli kubernetes.Interface
-	Log    logr.Logger
+	// StatusClient fetches the state from the replicas serving it over HTTPS. If nil, the state is always read using exec.
+	StatusClient *StatusClient
+	Log          logr.Logger
 }
 
 func HasSynced(env *Env, nodeNames []string) (bool, map[string]sets.Set[string], error) {
@@ -79,6 +89,10 @@ func HasSynced(env *Env, nodeNames []string) (bool, map[string]sets.Set[string],
 
 	//nolint: ineffassign,staticcheck,wastedassign
 	podList, err := podlist.With(env.Cli).ByDeployment(env.Ctx, *dp)
+	podList, err = LeaderPods(env.Ctx, env.Cli, dp, podList)
+	if err != nil {
+		return false, nil, err
+	}
 	for idx := range podList {
 		pod := &podList[idx]
 
@@ -92,6 +106,31 @@ func HasSynced(env *Env, nodeNames []string) (bool, map[string]sets.Set[string],
 	return len(unsynced) == 0, unsynced, nil
 }
 
+// LeaderPods filters the scheduler replicas whose cache is relevant. With more than a replica,
+// only the leader schedules pods, so the caches of the standby replicas are not considered.
+func LeaderPods(ctx context.Context, cli client.Client, dp *appsv1.Deployment, pods []corev1.Pod) ([]corev1.Pod, error) {
+	if dp.Spec.Replicas == nil || *dp.Spec.Replicas <= 1 {
+		return pods, nil
+	}
+
+	lease := coordinationv1.Lease{}
+	if err := cli.Get(ctx, client.ObjectKey(schedstate.LeaderElectionLease(dp)), &lease); err != nil {
+		return nil, err
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" {
+		return nil, fmt.Errorf("no scheduler leader elected yet")
+	}
+	// the scheduler identity is "<hostname>_<uuid>", and the hostname of a pod is its name
+	leaderName, _, _ := strings.Cut(*lease.Spec.HolderIdentity, "_")
+
+	for idx := range pods {
+		if pods[idx].Name == leaderName {
+			return pods[idx : idx+1], nil
+		}
+	}
+	return nil, fmt.Errorf("cannot find the scheduler leader %q", leaderName)
+}
+
 func ReplicaHasSynced(env *Env, pod *corev1.Pod, nodeNames []string) (map[string]sets.Set[string], error) {
 	unsynced := make(map[string]sets.Set[string])
 	for _, nodeName := range nodeNames {
@@ -110,7 +149,7 @@ func ReplicaHasSynced(env *Env, pod *corev1.Pod, nodeNames []string) (map[string
 
 func ReplicaHasSyncedForNode(env *Env, pod *corev1.Pod, nodeName string) (bool, sets.Set[string], error) {
 	detectedPods := sets.New[string]()
-	stdout, _, err := remoteexec.CommandOnPod(env.Ctx, env.K8sCli, pod, "/bin/cat", filepath.Join(TracingDirectory, nodeNameToFileName(nodeName)+".json"))
+	stdout, err := fetchReplicaStatus(env, pod, nodeName)
 	if err != nil {
 		return false, detectedPods, err
 	}
@@ -137,6 +176,86 @@ func ReplicaHasSyncedForNode(env *Env, pod *corev1.Pod, nodeName string) (bool,
 	return hasSync, detectedPods, nil
 }
 
+// fetchReplicaStatus gets the cache state of the given node from the scheduler replica. If the replica serves
+// its state over HTTPS the state is fetched through the API server pods proxy, otherwise the file is read using exec.
+func fetchReplicaStatus(env *Env, pod *corev1.Pod, nodeName string) ([]byte, error) {
+	if env.StatusClient == nil || !hasStatusServer(pod) {
+		stdout, _, err := remoteexec.CommandOnPod(env.Ctx, env.K8sCli, pod, "/bin/cat", filepath.Join(TracingDirectory, nodeNameToFileName(nodeName)+".json"))
+		return stdout, err
+	}
+	data, err := env.StatusClient.Get(env.Ctx, pod, StatusPath(nodeName))
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch the cache state of %q from pod %s/%s: %w", nodeName, pod.Namespace, pod.Name, err)
+	}
+	return data, nil
+}
+
+// StatusClient fetches the state from the scheduler replicas through the API server pods proxy.
+// The API server authorizes the access to the proxy, then the status server authorizes the forwarded
+// credentials again, because it is reachable from the pod network as well.
+type StatusClient struct {
+	restCli rest.Interface
+	token   func() (string, error)
+}
+
+// NewStatusClient returns a client to fetch the state from the scheduler replicas with the credentials of cfg.
+// The status server accepts only bearer tokens, so cfg must provide one.
+func NewStatusClient(cfg *rest.Config) (*StatusClient, error) {
+	var token func() (string, error)
+	switch {
+	case cfg.BearerTokenFile != "":
+		// the projected service account tokens are rotated, so the file must be read again once in a while
+		tokens := transport.NewCachedFileTokenSource(cfg.BearerTokenFile)
+		token = func() (string, error) {
+			tok, err := tokens.Token()
+			if err != nil {
+				return "", err
+			}
+			return tok.AccessToken, nil
+		}
+	case cfg.BearerToken != "":
+		bearerToken := cfg.BearerToken
+		token = func() (string, error) {
+			return bearerToken, nil
+		}
+	default:
+		return nil, fmt.Errorf("cannot forward the client credentials: missing bearer token")
+	}
+	k8sCli, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &StatusClient{
+		restCli: k8sCli.CoreV1().RESTClient(),
+		token:   token,
+	}, nil
+}
+
+// Get fetches the document at the given path from the status server of the given pod
+func (sc *StatusClient) Get(ctx context.Context, pod *corev1.Pod, path string) ([]byte, error) {
+	token, err := sc.token()
+	if err != nil {
+		return nil, err
+	}
+	return sc.restCli.Get().
+		Namespace(pod.Namespace).
+		Resource("pods").
+		Name(utilnet.JoinSchemeNamePort("https", pod.Name, strconv.Itoa(schedupdate.PFPStatusServerPort))).
+		SubResource("proxy").
+		Suffix(path).
+		SetHeader(ForwardedAuthorizationHeader, "Bearer "+token).
+		DoRaw(ctx)
+}
+
+func hasStatusServer(pod *corev1.Pod) bool {
+	for _, cnt := range pod.Spec.Containers {
+		if cnt.Name == schedupdate.PFPStatusServerContainerName {
+			return true
+		}
+	}
+	return false
+}
+
 func GetUpdaterFingerprintStatus(env *Env, podNamespace, podName, cntName string) (podfingerprint.Status, error) {
 	var st podfingerprint.Status
 	stdout, _, err := remoteexec.CommandOnPodByNames(env.Ctx, env.K8sCli, podNamespace, podName, cntName, "/bin/cat", filepath.Join(TracingDirectory, "dump.json"))
@@ -157,3 +276,22 @@ func mergeUnsynced(total, partial map[string]sets.Set[string]) {
 func nodeNameToFileName(name string) string {
 	return strings.ReplaceAll(name, ".", "_")
 }
+
+// ReplicaSyncFunc returns the nodes whose cache is not in sync on the given scheduler replica,
+// along with the pods detected on each of them.
+type ReplicaSyncFunc func(ctx context.Context, pod *corev1.Pod, nodeNames []string) (map[string]sets.Set[string], error)
+
+// NewReplicaSyncFunc returns a ReplicaSyncFunc which inspects the scheduler replicas using ReplicaHasSynced.
+// statusCli may be nil, in which case the state is always read using exec.
+func NewReplicaSyncFunc(cli client.Client, k8sCli kubernetes.Interface, statusCli *StatusClient) ReplicaSyncFunc {
+	return func(ctx context.Context, pod *corev1.Pod, nodeNames []string) (map[string]sets.Set[string], error) {
+		env := Env{
+			Ctx:          ctx,
+			Cli:          cli,
+			K8sCli:       k8sCli,
+			StatusClient: statusCli,
+			Log:          logr.Discard(),
+		}
+		return ReplicaHasSynced(&env, pod, nodeNames)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schedcache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+func TestStatusClientGet(t *testing.T) {
+	var gotPath, gotForwarded, gotAuthorization string
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotPath = req.URL.Path
+		gotForwarded = req.Header.Get(ForwardedAuthorizationHeader)
+		gotAuthorization = req.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"nodeName":"worker.0"}`))
+	}))
+	defer apiServer.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte(operatorToken), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sc, err := NewStatusClient(&rest.Config{
+		Host:            apiServer.URL,
+		BearerTokenFile: tokenFile,
+	})
+	if err != nil {
+		t.Fatalf("NewStatusClient() failed: %v", err)
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "numaresources",
+			Name:      "secondary-scheduler-0",
+		},
+	}
+	data, err := sc.Get(context.Background(), pod, StatusPath("worker.0"))
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if string(data) != `{"nodeName":"worker.0"}` {
+		t.Errorf("unexpected data: %q", string(data))
+	}
+	if expected := "/api/v1/namespaces/numaresources/pods/https:secondary-scheduler-0:8090/proxy/pfpstatus/worker_0.json"; gotPath != expected {
+		t.Errorf("unexpected path: got=%q expected=%q", gotPath, expected)
+	}
+	if expected := "Bearer " + operatorToken; gotAuthorization != expected || gotForwarded != expected {
+		t.Errorf("unexpected credentials: authorization=%q forwarded=%q expected=%q", gotAuthorization, gotForwarded, expected)
+	}
+}
+
+func TestNewStatusClientWithoutToken(t *testing.T) {
+	_, err := NewStatusClient(&rest.Config{
+		Host: "https://127.0.0.1:6443",
+		TLSClientConfig: rest.TLSClientConfig{
+			CertFile: "/path/to/client.crt",
+			KeyFile:  "/path/to/client.key",
+		},
+	})
+	if err == nil {
+		t.Fatalf("NewStatusClient() succeeded without a bearer token")
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strings"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -37,6 +38,7 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/textlogger"
@@ -44,6 +46,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
@@ -52,13 +55,18 @@ import (
 	apimanifests "github.com/k8stopologyawareschedwg/deployer/pkg/manifests/api"
 	rtemanifests "github.com/k8stopologyawareschedwg/deployer/pkg/manifests/rte"
 	"github.com/k8stopologyawareschedwg/deployer/pkg/options"
+	nrtv1alpha2 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha2"
 
 	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
 	nropv1alpha1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1alpha1"
 	"github.com/openshift-kni/numaresources-operator/controllers"
+	nropmetrics "github.com/openshift-kni/numaresources-operator/internal/metrics"
+	"github.com/openshift-kni/numaresources-operator/internal/schedcache"
 	"github.com/openshift-kni/numaresources-operator/pkg/features"
 	"github.com/openshift-kni/numaresources-operator/pkg/hash"
 	"github.com/openshift-kni/numaresources-operator/pkg/images"
+	"github.com/openshift-kni/numaresources-operator/pkg/numaresourcesoperator/manifests/rtemetrics"
+	nropwebhook "github.com/openshift-kni/numaresources-operator/pkg/numaresourcesoperator/webhook"
 	rteupdate "github.com/openshift-kni/numaresources-operator/pkg/objectupdate/rte"
 	schedupdate "github.com/openshift-kni/numaresources-operator/pkg/objectupdate/sched"
 	"github.com/openshift-kni/numaresources-operator/pkg/version"
@@ -86,6 +94,7 @@ func init() {
 	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 	utilruntime.Must(nropv1.AddToScheme(scheme))
 	utilruntime.Must(nropv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(nrtv1alpha2.AddToScheme(scheme))
 	utilruntime.Must(machineconfigv1.Install(scheme))
 	utilruntime.Must(securityv1.Install(scheme))
 	//+kubebuilder:scaffold:scheme
@@ -102,6 +111,19 @@ type RenderParams struct {
 	Image     ImageParams
 }
 
+type PFPStatusParams struct {
+	Dir         string
+	BindAddress string
+	CertFile    string
+	KeyFile     string
+}
+
+type NRTMetricsParams struct {
+	Enabled        bool
+	Resources      string
+	AggregateZones bool
+}
+
 type Params struct {
 	webhookPort           int
 	metricsAddr           string
@@ -120,12 +142,17 @@ type Params struct {
 	enableMCPCondsForward bool
 	image                 ImageParams
 	inspectFeatures       bool
+	servePFPStatus        bool
+	pfpStatus             PFPStatusParams
+	nrtMetrics            NRTMetricsParams
 }
 
 func (pa *Params) SetDefaults() {
 	pa.metricsAddr = defaultMetricsAddr
 	pa.probeAddr = defaultProbeAddr
 	pa.render.Namespace = defaultNamespace
+	pa.pfpStatus.Dir = schedupdate.PFPStatusDir
+	pa.pfpStatus.BindAddress = fmt.Sprintf(":%d", schedupdate.PFPStatusServerPort)
 }
 
 func (pa *Params) FromFlags() {
@@ -143,13 +170,21 @@ func (pa *Params) FromFlags() {
 	flag.StringVar(&pa.render.Image.Scheduler, "render-image-scheduler", pa.render.Image.Scheduler, "outputs the manifests rendered using the given image for the scheduler")
 	flag.BoolVar(&pa.showVersion, "version", pa.showVersion, "outputs the version and exit")
 	flag.BoolVar(&pa.enableScheduler, "enable-scheduler", pa.enableScheduler, "enable support for the NUMAResourcesScheduler object")
-	flag.BoolVar(&pa.enableWebhooks, "enable-webhooks", pa.enableWebhooks, "enable conversion webhooks")
+	flag.BoolVar(&pa.enableWebhooks, "enable-webhooks", pa.enableWebhooks, "enable conversion and validation webhooks")
 	flag.IntVar(&pa.webhookPort, "webhook-port", defaultWebhookPort, "The port the operator webhook should listen to.")
 	flag.BoolVar(&pa.enableMetrics, "enable-metrics", pa.enableMetrics, "enable metrics server")
 	flag.BoolVar(&pa.enableHTTP2, "enable-http2", pa.enableHTTP2, "If HTTP/2 should be enabled for the webhook servers.")
 	flag.BoolVar(&pa.enableMCPCondsForward, "enable-mcp-conds-fwd", pa.enableMCPCondsForward, "enable MCP Status Condition forwarding")
 	flag.StringVar(&pa.image.Exporter, "image-exporter", pa.image.Exporter, "use this image as default for the RTE")
 	flag.StringVar(&pa.image.Scheduler, "image-scheduler", pa.image.Scheduler, "use this image as default for the scheduler")
+	flag.BoolVar(&pa.servePFPStatus, "serve-pfpstatus", pa.servePFPStatus, "serve the scheduler cache state files over HTTP, then exits")
+	flag.StringVar(&pa.pfpStatus.Dir, "pfpstatus-dir", pa.pfpStatus.Dir, "The directory containing the scheduler cache state files to serve.")
+	flag.StringVar(&pa.pfpStatus.BindAddress, "pfpstatus-bind-address", pa.pfpStatus.BindAddress, "The address the scheduler cache state endpoint binds to.")
+	flag.StringVar(&pa.pfpStatus.CertFile, "pfpstatus-tls-cert-file", pa.pfpStatus.CertFile, "The TLS certificate the scheduler cache state endpoint serves.")
+	flag.StringVar(&pa.pfpStatus.KeyFile, "pfpstatus-tls-key-file", pa.pfpStatus.KeyFile, "The TLS key of the scheduler cache state endpoint certificate.")
+	flag.BoolVar(&pa.nrtMetrics.Enabled, "enable-nrt-metrics", pa.nrtMetrics.Enabled, "export the NUMA zones capacity reported in the NodeResourceTopology objects as metrics")
+	flag.StringVar(&pa.nrtMetrics.Resources, "nrt-metrics-resources", pa.nrtMetrics.Resources, "comma-separated list of the resources to export in the NodeResourceTopology metrics - leave empty to export all of them")
+	flag.BoolVar(&pa.nrtMetrics.AggregateZones, "nrt-metrics-aggregate-zones", pa.nrtMetrics.AggregateZones, "export the NodeResourceTopology metrics summed per node instead of per NUMA zone")
 
 	flag.Parse()
 
@@ -157,6 +192,7 @@ func (pa *Params) FromFlags() {
 
 	if !pa.enableMetrics {
 		pa.metricsAddr = "0"
+		pa.nrtMetrics.Enabled = false
 	}
 }
 
@@ -181,6 +217,10 @@ func main() {
 
 	klog.InfoS("starting", "program", version.ProgramName(), "version", version.Get(), "gitcommit", version.GetGitCommit(), "golang", runtime.Version(), "vl", klogV, "auxv", config.Verbosity().String())
 
+	if params.servePFPStatus {
+		os.Exit(manageStatusServing(params.pfpStatus))
+	}
+
 	clusterPlatform, clusterPlatformVersion, err := version.DiscoverCluster(context.Background(), params.platformName, params.platformVersion)
 	if err != nil {
 		os.Exit(1)
@@ -239,6 +279,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	if params.nrtMetrics.Enabled {
+		nrtOpts := nropmetrics.NRTOptions{
+			Resources:      splitCommaList(params.nrtMetrics.Resources),
+			AggregateZones: params.nrtMetrics.AggregateZones,
+		}
+		klog.InfoS("NodeResourceTopology metrics", "resources", nrtOpts.Resources, "aggregateZones", nrtOpts.AggregateZones)
+		ctrlmetrics.Registry.MustRegister(nropmetrics.NewNRTCollector(mgr.GetClient(), nrtOpts))
+	}
+
 	imgs, pullPolicy := images.Discover(context.Background(), params.image.Exporter)
 
 	rteManifestsRendered, err := renderRTEManifests(rteManifests, namespace, imgs)
@@ -247,28 +296,54 @@ func main() {
 		os.Exit(1)
 	}
 
+	rteMetricsManifests, err := rtemetrics.GetManifests(namespace)
+	if err != nil {
+		klog.ErrorS(err, "unable to load the RTE metrics manifests", "controller", "NUMAResourcesOperator")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.NUMAResourcesOperatorReconciler{
-		Client:          mgr.GetClient(),
-		Scheme:          mgr.GetScheme(),
-		Recorder:        mgr.GetEventRecorderFor("numaresources-controller"),
-		APIManifests:    apiManifests,
-		RTEManifests:    rteManifestsRendered,
-		Platform:        clusterPlatform,
-		Images:          imgs,
-		ImagePullPolicy: pullPolicy,
-		Namespace:       namespace,
-		ForwardMCPConds: params.enableMCPCondsForward,
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		Recorder:            mgr.GetEventRecorderFor("numaresources-controller"),
+		APIManifests:        apiManifests,
+		RTEManifests:        rteManifestsRendered,
+		RTEMetricsManifests: rteMetricsManifests,
+		Platform:            clusterPlatform,
+		Images:              imgs,
+		ImagePullPolicy:     pullPolicy,
+		Namespace:           namespace,
+		ForwardMCPConds:     params.enableMCPCondsForward,
 	}).SetupWithManager(mgr); err != nil {
 		klog.ErrorS(err, "unable to create controller", "controller", "NUMAResourcesOperator")
 		os.Exit(1)
 	}
-	if err = (&controllers.KubeletConfigReconciler{
-		Client:    mgr.GetClient(),
-		Scheme:    mgr.GetScheme(),
-		Recorder:  mgr.GetEventRecorderFor("kubeletconfig-controller"),
-		Namespace: namespace,
+	// on kubernetes there is no MCO KubeletConfig to track, the kubelet configuration is read from /configz
+	if clusterPlatform == platform.OpenShift {
+		if err = (&controllers.KubeletConfigReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    mgr.GetScheme(),
+			Recorder:  mgr.GetEventRecorderFor("kubeletconfig-controller"),
+			Namespace: namespace,
+		}).SetupWithManager(mgr); err != nil {
+			klog.ErrorS(err, "unable to create controller", "controller", "KubeletConfig")
+			os.Exit(1)
+		}
+	}
+	k8sCli, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		klog.ErrorS(err, "unable to create kubernetes client")
+		os.Exit(1)
+	}
+	if err = (&controllers.KubeletConfigzReconciler{
+		Client:     mgr.GetClient(),
+		Scheme:     mgr.GetScheme(),
+		Recorder:   mgr.GetEventRecorderFor("kubeletconfigz-controller"),
+		Namespace:  namespace,
+		Platform:   clusterPlatform,
+		RESTClient: k8sCli.CoreV1().RESTClient(),
 	}).SetupWithManager(mgr); err != nil {
-		klog.ErrorS(err, "unable to create controller", "controller", "KubeletConfig")
+		klog.ErrorS(err, "unable to create controller", "controller", "KubeletConfigz")
 		os.Exit(1)
 	}
 
@@ -280,11 +355,22 @@ func main() {
 		}
 		klog.InfoS("manifests loaded", "component", "Scheduler")
 
+		statusCli, err := schedcache.NewStatusClient(mgr.GetConfig())
+		if err != nil {
+			// not fatal: the scheduler cache state is read using exec instead
+			klog.ErrorS(err, "unable to create the scheduler cache state client")
+			statusCli = nil
+		}
+
 		if err = (&controllers.NUMAResourcesSchedulerReconciler{
 			Client:             mgr.GetClient(),
 			Scheme:             mgr.GetScheme(),
 			SchedulerManifests: schedMf,
 			Namespace:          namespace,
+			Recorder:           mgr.GetEventRecorderFor("numaresourcesscheduler-controller"),
+			CacheSyncCheck:     schedcache.NewReplicaSyncFunc(mgr.GetClient(), k8sCli, statusCli),
+			StatusServerImage:  operatorImage(imgs),
+			Platform:           clusterPlatform,
 		}).SetupWithManager(mgr); err != nil {
 			klog.ErrorS(err, "unable to create controller", "controller", "NUMAResourcesScheduler")
 			os.Exit(1)
@@ -292,7 +378,11 @@ func main() {
 	}
 
 	if params.enableWebhooks {
-		if err = SetupOperatorWebhookWithManager(mgr, &nropv1.NUMAResourcesOperator{}); err != nil {
+		validator := &nropwebhook.NUMAResourcesOperatorValidator{
+			Client:   mgr.GetClient(),
+			Platform: clusterPlatform,
+		}
+		if err = SetupOperatorWebhookWithManager(mgr, &nropv1.NUMAResourcesOperator{}, validator); err != nil {
 			klog.Exitf("unable to create NUMAResourcesOperator v1 webhook : %v", err)
 		}
 		if err = SetupSchedulerWebhookWithManager(mgr, &nropv1.NUMAResourcesScheduler{}); err != nil {
@@ -388,6 +478,27 @@ func renderObjects(objs []client.Object) error {
 	return nil
 }
 
+// operatorImage returns the image of the operator itself. The user-provided image is meant for the RTE, so it is ignored.
+func operatorImage(imgs images.Data) string {
+	if imgs.Self != "" {
+		return imgs.Self
+	}
+	return imgs.Builtin
+}
+
+// splitCommaList splits a comma-separated flag value, skipping the empty items.
+func splitCommaList(val string) []string {
+	var items []string
+	for _, item := range strings.Split(val, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
 // renderRTEManifests renders the reconciler manifests so they can be deployed on the cluster.
 func renderRTEManifests(rteManifests rtemanifests.Manifests, namespace string, imgs images.Data) (rtemanifests.Manifests, error) {
 	klog.InfoS("Updating RTE manifests")
@@ -418,6 +529,27 @@ func renderRTEManifests(rteManifests rtemanifests.Manifests, namespace string, i
 	return mf, err
 }
 
+// manageStatusServing serves the scheduler cache state dumped in the shared directory until the process is signalled.
+// The authentication and the authorization of the requests are delegated to the API server.
+func manageStatusServing(params PFPStatusParams) int {
+	k8sCli, err := kubernetes.NewForConfig(ctrl.GetConfigOrDie())
+	if err != nil {
+		klog.ErrorS(err, "unable to create the kubernetes client")
+		return 1
+	}
+	rev := schedcache.Reviewer{
+		TokenReviews:         k8sCli.AuthenticationV1().TokenReviews(),
+		SubjectAccessReviews: k8sCli.AuthorizationV1().SubjectAccessReviews(),
+		Namespace:            os.Getenv("POD_NAMESPACE"),
+		PodName:              os.Getenv("POD_NAME"),
+	}
+	if err := schedcache.ServeStatus(ctrl.SetupSignalHandler(), params.BindAddress, params.Dir, params.CertFile, params.KeyFile, rev); err != nil {
+		klog.ErrorS(err, "unable to serve the scheduler cache state")
+		return 1
+	}
+	return 0
+}
+
 func renderSchedulerManifests(schedManifests schedmanifests.Manifests, imageSpec string) (schedmanifests.Manifests, error) {
 	klog.InfoS("Updating scheduler manifests")
 	mf := schedManifests.Clone()
@@ -429,10 +561,11 @@ func renderSchedulerManifests(schedManifests schedmanifests.Manifests, imageSpec
 	return mf, nil
 }
 
-// SetupWebhookWithManager enables Webhooks - needed for version conversion
-func SetupOperatorWebhookWithManager(mgr ctrl.Manager, r *nropv1.NUMAResourcesOperator) error {
+// SetupWebhookWithManager enables Webhooks - needed for version conversion and for the validation
+func SetupOperatorWebhookWithManager(mgr ctrl.Manager, r *nropv1.NUMAResourcesOperator, validator *nropwebhook.NUMAResourcesOperatorValidator) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(validator).
 		Complete()
 }
 
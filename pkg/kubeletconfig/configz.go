@@ -0,0 +1,54 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kubeletconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/client-go/rest"
+	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+)
+
+// configzResponse is the payload served by the kubelet /configz endpoint
+type configzResponse struct {
+	KubeletConfig *kubeletconfigv1beta1.KubeletConfiguration `json:"kubeletconfig"`
+}
+
+// FetchFromConfigz gets the effective kubelet configuration of the given node
+// reading its /configz endpoint through the API server node proxy.
+// The client is expected to be the core/v1 REST client.
+func FetchFromConfigz(ctx context.Context, cli rest.Interface, nodeName string) (*kubeletconfigv1beta1.KubeletConfiguration, error) {
+	data, err := cli.Get().Resource("nodes").Name(nodeName).SubResource("proxy").Suffix("configz").DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configz from node %q: %w", nodeName, err)
+	}
+	return DecodeConfigz(data)
+}
+
+// DecodeConfigz decodes the payload served by the kubelet /configz endpoint
+func DecodeConfigz(data []byte) (*kubeletconfigv1beta1.KubeletConfiguration, error) {
+	resp := configzResponse{}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	if resp.KubeletConfig == nil {
+		return nil, MissingPayloadError
+	}
+	return resp.KubeletConfig, nil
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package kubeletconfig
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	restfake "k8s.io/client-go/rest/fake"
+)
+
+const configzData = `{"kubeletconfig":{"cpuManagerPolicy":"static","topologyManagerPolicy":"single-numa-node","topologyManagerScope":"pod"}}`
+
+func TestDecodeConfigz(t *testing.T) {
+	testCases := []struct {
+		name           string
+		data           string
+		expectedPolicy string
+		expectedScope  string
+		expectedErr    error
+		expectedAnyErr bool
+	}{
+		{
+			name:           "valid",
+			data:           configzData,
+			expectedPolicy: "single-numa-node",
+			expectedScope:  "pod",
+		},
+		{
+			name:        "missing payload",
+			data:        `{"foo":{}}`,
+			expectedErr: MissingPayloadError,
+		},
+		{
+			name:           "malformed",
+			data:           `{"kubeletconfig":`,
+			expectedAnyErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kc, err := DecodeConfigz([]byte(tc.data))
+			if tc.expectedErr != nil || tc.expectedAnyErr {
+				if err == nil {
+					t.Fatalf("expected error, succeeded")
+				}
+				if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if kc.TopologyManagerPolicy != tc.expectedPolicy || kc.TopologyManagerScope != tc.expectedScope {
+				t.Errorf("unexpected topology manager settings: policy=%q scope=%q", kc.TopologyManagerPolicy, kc.TopologyManagerScope)
+			}
+		})
+	}
+}
+
+func TestFetchFromConfigz(t *testing.T) {
+	var gotPath string
+	cli := &restfake.RESTClient{
+		GroupVersion:         schema.GroupVersion{Version: "v1"},
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			gotPath = req.URL.Path
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(bytes.NewBufferString(configzData)),
+			}, nil
+		}),
+	}
+
+	kc, err := FetchFromConfigz(context.TODO(), cli, "node-0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/nodes/node-0/proxy/configz" {
+		t.Errorf("unexpected request path: %q", gotPath)
+	}
+	if kc.TopologyManagerPolicy != "single-numa-node" {
+		t.Errorf("unexpected topology manager policy: %q", kc.TopologyManagerPolicy)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rtemetrics
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//go:embed yaml
+var src embed.FS
+
+// Manifests are the objects which make the RTE metrics available to the monitoring stack
+type Manifests struct {
+	Service *corev1.Service
+	// ServiceMonitor is unstructured because its API is provided by the monitoring stack, which may be missing
+	ServiceMonitor *unstructured.Unstructured
+	NetworkPolicy  *networkingv1.NetworkPolicy
+}
+
+func (mf Manifests) ToObjects() []client.Object {
+	return []client.Object{
+		mf.Service,
+		mf.ServiceMonitor,
+		mf.NetworkPolicy,
+	}
+}
+
+func (mf Manifests) Clone() Manifests {
+	return Manifests{
+		Service:        mf.Service.DeepCopy(),
+		ServiceMonitor: mf.ServiceMonitor.DeepCopy(),
+		NetworkPolicy:  mf.NetworkPolicy.DeepCopy(),
+	}
+}
+
+func GetManifests(namespace string) (Manifests, error) {
+	var err error
+	mf := Manifests{}
+
+	mf.Service, err = Service(namespace)
+	if err != nil {
+		return mf, err
+	}
+
+	mf.ServiceMonitor, err = ServiceMonitor(namespace, mf.Service.Name)
+	if err != nil {
+		return mf, err
+	}
+
+	mf.NetworkPolicy, err = NetworkPolicy(namespace)
+	if err != nil {
+		return mf, err
+	}
+
+	return mf, nil
+}
+
+func Service(namespace string) (*corev1.Service, error) {
+	obj, err := loadObject(filepath.Join("yaml", "service.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	svc, ok := obj.(*corev1.Service)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type, got %t", obj)
+	}
+	if namespace != "" {
+		svc.Namespace = namespace
+	}
+	return svc, nil
+}
+
+// ServiceMonitor returns the ServiceMonitor scraping the given service, which must serve a certificate
+// valid for the service name.
+func ServiceMonitor(namespace, serviceName string) (*unstructured.Unstructured, error) {
+	data, err := src.ReadFile(filepath.Join("yaml", "servicemonitor.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sm := &unstructured.Unstructured{}
+	if err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), len(data)).Decode(&sm.Object); err != nil {
+		return nil, err
+	}
+	if namespace == "" {
+		return sm, nil
+	}
+
+	sm.SetNamespace(namespace)
+	if err := unstructured.SetNestedStringSlice(sm.Object, []string{namespace}, "spec", "namespaceSelector", "matchNames"); err != nil {
+		return nil, err
+	}
+	endpoints, _, err := unstructured.NestedSlice(sm.Object, "spec", "endpoints")
+	if err != nil {
+		return nil, err
+	}
+	for idx := range endpoints {
+		endpoint, ok := endpoints[idx].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected endpoint type, got %t", endpoints[idx])
+		}
+		serverName := fmt.Sprintf("%s.%s.svc", serviceName, namespace)
+		if err := unstructured.SetNestedField(endpoint, serverName, "tlsConfig", "serverName"); err != nil {
+			return nil, err
+		}
+	}
+	if err := unstructured.SetNestedSlice(sm.Object, endpoints, "spec", "endpoints"); err != nil {
+		return nil, err
+	}
+	return sm, nil
+}
+
+func NetworkPolicy(namespace string) (*networkingv1.NetworkPolicy, error) {
+	obj, err := loadObject(filepath.Join("yaml", "networkpolicy.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	np, ok := obj.(*networkingv1.NetworkPolicy)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type, got %t", obj)
+	}
+	if namespace != "" {
+		np.Namespace = namespace
+	}
+	return np, nil
+}
+
+func deserializeObjectFromData(data []byte) (runtime.Object, error) {
+	decode := scheme.Codecs.UniversalDeserializer().Decode
+	obj, _, err := decode(data, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func loadObject(path string) (runtime.Object, error) {
+	data, err := src.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return deserializeObjectFromData(data)
+}
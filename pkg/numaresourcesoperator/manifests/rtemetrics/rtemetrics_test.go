@@ -0,0 +1,77 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rtemetrics
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestGetManifests(t *testing.T) {
+	mf, err := GetManifests("")
+	if err != nil {
+		t.Fatalf("GetManifests() failed: err=%v", err)
+	}
+
+	for _, obj := range mf.ToObjects() {
+		if obj == nil {
+			t.Fatalf("GetManifests(): loaded nil manifest")
+		}
+	}
+	if gvk := mf.ServiceMonitor.GroupVersionKind(); gvk.Kind != "ServiceMonitor" || gvk.Group != "monitoring.coreos.com" {
+		t.Errorf("GetManifests(): unexpected ServiceMonitor kind: %v", gvk)
+	}
+}
+
+func TestCloneManifests(t *testing.T) {
+	mf, err := GetManifests("")
+	if err != nil {
+		t.Fatalf("GetManifests() failed: err=%v", err)
+	}
+
+	mf2 := mf.Clone()
+	if !reflect.DeepEqual(mf, mf2) {
+		t.Fatalf("Clone() returned manifests failing DeepEqual")
+	}
+}
+
+func TestServiceMonitorNamespace(t *testing.T) {
+	mf, err := GetManifests("test-ns")
+	if err != nil {
+		t.Fatalf("GetManifests() failed: err=%v", err)
+	}
+
+	sm := mf.ServiceMonitor
+	if sm.GetNamespace() != "test-ns" {
+		t.Errorf("unexpected namespace: %q", sm.GetNamespace())
+	}
+	matchNames, _, _ := unstructured.NestedStringSlice(sm.Object, "spec", "namespaceSelector", "matchNames")
+	if !reflect.DeepEqual(matchNames, []string{"test-ns"}) {
+		t.Errorf("unexpected namespace selector: %v", matchNames)
+	}
+	endpoints, _, _ := unstructured.NestedSlice(sm.Object, "spec", "endpoints")
+	if len(endpoints) != 1 {
+		t.Fatalf("unexpected endpoints: %v", endpoints)
+	}
+	expected := mf.Service.Name + ".test-ns.svc"
+	serverName, _, _ := unstructured.NestedString(endpoints[0].(map[string]interface{}), "tlsConfig", "serverName")
+	if serverName != expected {
+		t.Errorf("unexpected server name: got %q expected %q", serverName, expected)
+	}
+}
@@ -0,0 +1,170 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/k8stopologyawareschedwg/deployer/pkg/deployer/platform"
+	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	nodegroupv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1/helper/nodegroup"
+	"github.com/openshift-kni/numaresources-operator/pkg/objectnames"
+	"github.com/openshift-kni/numaresources-operator/pkg/validation"
+)
+
+//+kubebuilder:webhook:path=/validate-nodetopology-openshift-io-v1-numaresourcesoperator,mutating=false,failurePolicy=fail,sideEffects=None,groups=nodetopology.openshift.io,resources=numaresourcesoperators,verbs=create;update,versions=v1,name=vnumaresourcesoperator.nodetopology.openshift.io,admissionReviewVersions=v1
+
+// NUMAResourcesOperatorValidator rejects the NUMAResourcesOperator objects the controller would only
+// report as Degraded later, so the mistakes are noticed when the object is applied.
+type NUMAResourcesOperatorValidator struct {
+	Client   client.Reader
+	Platform platform.Platform
+}
+
+var _ admission.CustomValidator = &NUMAResourcesOperatorValidator{}
+
+func (v *NUMAResourcesOperatorValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	nro, ok := obj.(*nropv1.NUMAResourcesOperator)
+	if !ok {
+		return nil, fmt.Errorf("expected a NUMAResourcesOperator object, got %T", obj)
+	}
+	return v.validate(ctx, nro)
+}
+
+func (v *NUMAResourcesOperatorValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	nro, ok := newObj.(*nropv1.NUMAResourcesOperator)
+	if !ok {
+		return nil, fmt.Errorf("expected a NUMAResourcesOperator object, got %T", newObj)
+	}
+	return v.validate(ctx, nro)
+}
+
+func (v *NUMAResourcesOperatorValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *NUMAResourcesOperatorValidator) validate(ctx context.Context, nro *nropv1.NUMAResourcesOperator) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.RequestKind != nil && req.RequestKind.Version != nropv1.GroupVersion.Version {
+		warnings = append(warnings, fmt.Sprintf("%s/%s NUMAResourcesOperator is deprecated, use %s", req.RequestKind.Group, req.RequestKind.Version, nropv1.GroupVersion.String()))
+	}
+
+	var errs field.ErrorList
+	if nro.Name != objectnames.DefaultNUMAResourcesOperatorCrName {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), nro.Name, fmt.Sprintf("must be %q", objectnames.DefaultNUMAResourcesOperatorCrName)))
+	}
+
+	specPath := field.NewPath("spec")
+	nodeGroupsPath := specPath.Child("nodeGroups")
+	nodeGroupsErrs := validateNodeGroups(nro.Spec.NodeGroups, v.Platform, nodeGroupsPath)
+	errs = append(errs, nodeGroupsErrs...)
+	for idx := range nro.Spec.NodeGroups {
+		conf := nro.Spec.NodeGroups[idx].Config
+		if err := validation.NodeGroupConfig(conf); err != nil {
+			errs = append(errs, field.Invalid(nodeGroupsPath.Index(idx).Child("config"), conf, err.Error()))
+		}
+		warnings = append(warnings, nodeGroupConfigWarnings(conf, nodeGroupsPath.Index(idx).Child("config"))...)
+	}
+	if err := validation.PodExcludes(nro.Spec.PodExcludes); err != nil {
+		errs = append(errs, field.Invalid(specPath.Child("podExcludes"), nro.Spec.PodExcludes, err.Error()))
+	}
+	if err := validation.RTEMetricsPlatform(nro.Spec.RTEMetrics, v.Platform); err != nil {
+		errs = append(errs, field.Invalid(specPath.Child("rteMetrics"), nro.Spec.RTEMetrics, err.Error()))
+	}
+
+	// the cluster state is meaningful only once the node groups are well formed
+	if len(nodeGroupsErrs) == 0 {
+		clusterWarnings, err := v.validateNodeGroupsInCluster(ctx, nro.Spec.NodeGroups)
+		if err != nil {
+			errs = append(errs, field.Invalid(nodeGroupsPath, nro.Spec.NodeGroups, err.Error()))
+		}
+		warnings = append(warnings, clusterWarnings...)
+	}
+
+	if len(errs) == 0 {
+		return warnings, nil
+	}
+	return warnings, apierrors.NewInvalid(nropv1.GroupVersion.WithKind("NUMAResourcesOperator").GroupKind(), nro.Name, errs)
+}
+
+func validateNodeGroups(nodeGroups []nropv1.NodeGroup, plat platform.Platform, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if err := validation.NodeGroups(nodeGroups); err != nil {
+		errs = append(errs, field.Invalid(path, nodeGroups, err.Error()))
+	}
+	if err := validation.NodeGroupsSelectorsNotEmpty(nodeGroups); err != nil {
+		errs = append(errs, field.Invalid(path, nodeGroups, err.Error()))
+	}
+	if err := validation.NodeGroupsPlatform(nodeGroups, plat); err != nil {
+		errs = append(errs, field.Invalid(path, nodeGroups, err.Error()))
+	}
+	return errs
+}
+
+// validateNodeGroupsInCluster checks the node groups against the MachineConfigPools and the nodes they select.
+// The MachineConfigPools may be created after the NUMAResourcesOperator, so the missing ones are only warned about.
+func (v *NUMAResourcesOperatorValidator) validateNodeGroupsInCluster(ctx context.Context, nodeGroups []nropv1.NodeGroup) (admission.Warnings, error) {
+	mcps := &machineconfigv1.MachineConfigPoolList{}
+	if v.Platform == platform.OpenShift {
+		if err := v.Client.List(ctx, mcps); err != nil {
+			return nil, err
+		}
+	}
+	trees, err := nodegroupv1.FindTrees(mcps, nodeGroups)
+	if err != nil {
+		klog.V(4).InfoS("cannot resolve the node groups", "error", err)
+		return admission.Warnings{err.Error()}, nil
+	}
+
+	if err := validation.MachineConfigPoolDuplicates(trees); err != nil {
+		return nil, err
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := v.Client.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+	return nil, validation.NodeSelectorGroups(trees, nodes.Items)
+}
+
+// nodeGroupConfigWarnings reports the settings which are accepted but have no effect
+func nodeGroupConfigWarnings(conf *nropv1.NodeGroupConfig, path *field.Path) admission.Warnings {
+	if conf == nil || conf.InfoRefreshMode == nil || conf.InfoRefreshPeriod == nil {
+		return nil
+	}
+	var warnings admission.Warnings
+	switch *conf.InfoRefreshMode {
+	case nropv1.InfoRefreshEvents:
+		warnings = append(warnings, fmt.Sprintf("%s: infoRefreshPeriod is ignored with the %s infoRefreshMode", path.String(), nropv1.InfoRefreshEvents))
+	case nropv1.InfoRefreshPeriodicAndEvents:
+		if conf.InfoRefreshPeriod.Duration == 0 {
+			warnings = append(warnings, fmt.Sprintf("%s: infoRefreshPeriod 0 makes the %s infoRefreshMode behave like %s", path.String(), nropv1.InfoRefreshPeriodicAndEvents, nropv1.InfoRefreshEvents))
+		}
+	}
+	return warnings
+}
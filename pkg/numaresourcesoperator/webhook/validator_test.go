@@ -0,0 +1,270 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package webhook
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/k8stopologyawareschedwg/deployer/pkg/deployer/platform"
+	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
+	testobjs "github.com/openshift-kni/numaresources-operator/internal/objects"
+	"github.com/openshift-kni/numaresources-operator/pkg/objectnames"
+)
+
+func TestValidateCreate(t *testing.T) {
+	label1 := map[string]string{"test1": "test1"}
+	label2 := map[string]string{"test2": "test2"}
+	mcp1 := testobjs.NewMachineConfigPool("test1", label1, &metav1.LabelSelector{MatchLabels: label1}, &metav1.LabelSelector{MatchLabels: label1})
+	mcp2 := testobjs.NewMachineConfigPool("test2", label2, &metav1.LabelSelector{MatchLabels: label2}, &metav1.LabelSelector{MatchLabels: label2})
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-0",
+			Labels: map[string]string{"test1": "test1", "custom": "custom"},
+		},
+	}
+	zero := metav1.Duration{}
+	periodic := nropv1.InfoRefreshPeriodic
+	events := nropv1.InfoRefreshEvents
+
+	testCases := []struct {
+		name             string
+		nro              *nropv1.NUMAResourcesOperator
+		expectedError    string
+		expectedWarnings []string
+	}{
+		{
+			name: "valid",
+			nro: newTestNRO(objectnames.DefaultNUMAResourcesOperatorCrName, nropv1.NodeGroup{
+				MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: label1},
+			}),
+		},
+		{
+			name: "unexpected name",
+			nro: newTestNRO("test", nropv1.NodeGroup{
+				MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: label1},
+			}),
+			expectedError: "metadata.name",
+		},
+		{
+			name:          "missing selector",
+			nro:           newTestNRO(objectnames.DefaultNUMAResourcesOperatorCrName, nropv1.NodeGroup{}),
+			expectedError: "does not have machineConfigPoolSelector nor nodeSelector",
+		},
+		{
+			name: "empty selector",
+			nro: newTestNRO(objectnames.DefaultNUMAResourcesOperatorCrName, nropv1.NodeGroup{
+				MachineConfigPoolSelector: &metav1.LabelSelector{},
+			}),
+			expectedError: "empty machineConfigPoolSelector",
+		},
+		{
+			name: "duplicated selector",
+			nro: newTestNRO(objectnames.DefaultNUMAResourcesOperatorCrName,
+				nropv1.NodeGroup{MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: label1}},
+				nropv1.NodeGroup{MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: label1}},
+			),
+			expectedError: "has duplicates",
+		},
+		{
+			name: "overlapping machine config pools",
+			nro: newTestNRO(objectnames.DefaultNUMAResourcesOperatorCrName,
+				nropv1.NodeGroup{MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: label1}},
+				nropv1.NodeGroup{MachineConfigPoolSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "test1", Operator: metav1.LabelSelectorOpExists},
+					},
+				}},
+			),
+			expectedError: "selected by at least two node groups",
+		},
+		{
+			name: "overlapping nodes",
+			nro: newTestNRO(objectnames.DefaultNUMAResourcesOperatorCrName,
+				nropv1.NodeGroup{MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: label1}},
+				nropv1.NodeGroup{Name: "custom", NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"custom": "custom"}}},
+			),
+			expectedError: `the node "node-0" is selected by more than one node group`,
+		},
+		{
+			name: "periodic refresh disabled",
+			nro: newTestNRO(objectnames.DefaultNUMAResourcesOperatorCrName, nropv1.NodeGroup{
+				MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: label1},
+				Config: &nropv1.NodeGroupConfig{
+					InfoRefreshMode:   &periodic,
+					InfoRefreshPeriod: &zero,
+				},
+			}),
+			expectedError: "spec.nodeGroups[0].config",
+		},
+		{
+			name: "malformed pod excludes",
+			nro: func() *nropv1.NUMAResourcesOperator {
+				nro := newTestNRO(objectnames.DefaultNUMAResourcesOperatorCrName, nropv1.NodeGroup{
+					MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: label1},
+				})
+				nro.Spec.PodExcludes = []nropv1.NamespacedName{{Namespace: "[kube", Name: "*"}}
+				return nro
+			}(),
+			expectedError: "malformed namespace pattern",
+		},
+		{
+			name: "malformed resource excludes node name pattern",
+			nro: newTestNRO(objectnames.DefaultNUMAResourcesOperatorCrName, nropv1.NodeGroup{
+				MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: label1},
+				Config: &nropv1.NodeGroupConfig{
+					ResourceExcludes: map[string][]string{"worker-[": {"memory"}},
+				},
+			}),
+			expectedError: "spec.nodeGroups[0].config",
+		},
+		{
+			name: "ignored refresh period",
+			nro: newTestNRO(objectnames.DefaultNUMAResourcesOperatorCrName, nropv1.NodeGroup{
+				MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: label1},
+				Config: &nropv1.NodeGroupConfig{
+					InfoRefreshMode:   &events,
+					InfoRefreshPeriod: &zero,
+				},
+			}),
+			expectedWarnings: []string{"infoRefreshPeriod is ignored"},
+		},
+		{
+			name: "missing machine config pool",
+			nro: newTestNRO(objectnames.DefaultNUMAResourcesOperatorCrName, nropv1.NodeGroup{
+				MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"missing": "missing"}},
+			}),
+			expectedWarnings: []string{"failed to find MachineConfigPool"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := newTestValidator(t, mcp1, mcp2, node)
+			warnings, err := v.ValidateCreate(context.TODO(), tc.nro)
+			if tc.expectedError == "" && err != nil {
+				t.Fatalf("expected success, failed: %v", err)
+			}
+			if tc.expectedError != "" {
+				if err == nil {
+					t.Fatalf("expected error, succeeded")
+				}
+				if !apierrors.IsInvalid(err) {
+					t.Errorf("unexpected error type: %v", err)
+				}
+				if !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("unexpected error message: %v", err)
+				}
+			}
+			expectWarnings(t, warnings, tc.expectedWarnings)
+		})
+	}
+}
+
+func TestValidateUpdateDeprecatedVersion(t *testing.T) {
+	label1 := map[string]string{"test1": "test1"}
+	mcp1 := testobjs.NewMachineConfigPool("test1", label1, &metav1.LabelSelector{MatchLabels: label1}, &metav1.LabelSelector{MatchLabels: label1})
+	nro := newTestNRO(objectnames.DefaultNUMAResourcesOperatorCrName, nropv1.NodeGroup{
+		MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: label1},
+	})
+
+	req := admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			RequestKind: &metav1.GroupVersionKind{
+				Group:   nropv1.GroupVersion.Group,
+				Version: "v1alpha1",
+				Kind:    "NUMAResourcesOperator",
+			},
+		},
+	}
+	ctx := admission.NewContextWithRequest(context.TODO(), req)
+
+	v := newTestValidator(t, mcp1)
+	warnings, err := v.ValidateUpdate(ctx, nro, nro)
+	if err != nil {
+		t.Fatalf("expected success, failed: %v", err)
+	}
+	expectWarnings(t, warnings, []string{"v1alpha1 NUMAResourcesOperator is deprecated"})
+}
+
+func TestValidateCreateRTEMetricsOnKubernetes(t *testing.T) {
+	nro := newTestNRO(objectnames.DefaultNUMAResourcesOperatorCrName, nropv1.NodeGroup{
+		Name:         "custom",
+		NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"custom": "custom"}},
+	})
+	nro.Spec.RTEMetrics = nropv1.RTEMetricsEnabled
+
+	v := newTestValidator(t)
+	v.Platform = platform.Kubernetes
+	_, err := v.ValidateCreate(context.TODO(), nro)
+	if err == nil {
+		t.Fatalf("expected error, succeeded")
+	}
+	if !apierrors.IsInvalid(err) || !strings.Contains(err.Error(), "spec.rteMetrics") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func newTestValidator(t *testing.T, objs ...client.Object) *NUMAResourcesOperatorValidator {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("cannot setup the scheme: %v", err)
+	}
+	if err := machineconfigv1.Install(scheme); err != nil {
+		t.Fatalf("cannot setup the scheme: %v", err)
+	}
+	return &NUMAResourcesOperatorValidator{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		Platform: platform.OpenShift,
+	}
+}
+
+func newTestNRO(name string, nodeGroups ...nropv1.NodeGroup) *nropv1.NUMAResourcesOperator {
+	return &nropv1.NUMAResourcesOperator{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: nropv1.NUMAResourcesOperatorSpec{
+			NodeGroups: nodeGroups,
+		},
+	}
+}
+
+func expectWarnings(t *testing.T, warnings admission.Warnings, expected []string) {
+	t.Helper()
+	if len(warnings) != len(expected) {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+	for idx := range expected {
+		if !strings.Contains(warnings[idx], expected[idx]) {
+			t.Errorf("unexpected warning: got %q expected %q", warnings[idx], expected[idx])
+		}
+	}
+}
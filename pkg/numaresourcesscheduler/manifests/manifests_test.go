@@ -17,7 +17,10 @@
 package manifests
 
 import (
+	"strings"
 	"testing"
+
+	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
 )
 
 func TestLoad(t *testing.T) {
@@ -33,10 +36,28 @@ func TestLoad(t *testing.T) {
 	if obj, err := ClusterRoleBindingNRT(""); obj == nil || err != nil {
 		t.Errorf("ClusterRoleBindingNRT() failed: err=%v", err)
 	}
+	if obj, err := ClusterRoleBindingAuthDelegator(""); obj == nil || err != nil {
+		t.Errorf("ClusterRoleBindingAuthDelegator() failed: err=%v", err)
+	}
 	if obj, err := ConfigMap(""); obj == nil || err != nil {
 		t.Errorf("ConfigMap() failed: err=%v", err)
 	}
 	if obj, err := Deployment(""); obj == nil || err != nil {
 		t.Errorf("Deployment() failed: err=%v", err)
 	}
+	if obj, err := PodDisruptionBudget(""); obj == nil || err != nil {
+		t.Errorf("PodDisruptionBudget() failed: err=%v", err)
+	}
+}
+
+func TestConfigMapExcludesStaleNodes(t *testing.T) {
+	cm, err := ConfigMap("")
+	if err != nil {
+		t.Fatalf("ConfigMap() failed: err=%v", err)
+	}
+	for key, data := range cm.Data {
+		if !strings.Contains(data, nropv1.StaleNodeTopologyLabel) {
+			t.Errorf("ConfigMap() data %q does not filter on the %q label", key, nropv1.StaleNodeTopologyLabel)
+		}
+	}
 }
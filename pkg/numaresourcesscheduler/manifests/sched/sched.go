@@ -19,6 +19,7 @@ package sched
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -31,7 +32,13 @@ type Manifests struct {
 	ClusterRole           *rbacv1.ClusterRole
 	ClusterRoleBindingK8S *rbacv1.ClusterRoleBinding
 	ClusterRoleBindingNRT *rbacv1.ClusterRoleBinding
-	Deployment            *appsv1.Deployment
+	// ClusterRoleBindingAuthDelegator lets the cache state server review the requests it gets
+	ClusterRoleBindingAuthDelegator *rbacv1.ClusterRoleBinding
+	Deployment                      *appsv1.Deployment
+	// PodDisruptionBudget is needed only when running more than a replica, hence is not part of ToObjects
+	PodDisruptionBudget *policyv1.PodDisruptionBudget
+	// StatusService is needed only when serving the cache state over HTTP, hence is not part of ToObjects
+	StatusService *corev1.Service
 }
 
 func (mf Manifests) ToObjects() []client.Object {
@@ -41,18 +48,22 @@ func (mf Manifests) ToObjects() []client.Object {
 		mf.ClusterRole,
 		mf.ClusterRoleBindingK8S,
 		mf.ClusterRoleBindingNRT,
+		mf.ClusterRoleBindingAuthDelegator,
 		mf.Deployment,
 	}
 }
 
 func (mf Manifests) Clone() Manifests {
 	return Manifests{
-		ServiceAccount:        mf.ServiceAccount.DeepCopy(),
-		ConfigMap:             mf.ConfigMap.DeepCopy(),
-		ClusterRole:           mf.ClusterRole.DeepCopy(),
-		ClusterRoleBindingK8S: mf.ClusterRoleBindingK8S.DeepCopy(),
-		ClusterRoleBindingNRT: mf.ClusterRoleBindingNRT.DeepCopy(),
-		Deployment:            mf.Deployment.DeepCopy(),
+		ServiceAccount:                  mf.ServiceAccount.DeepCopy(),
+		ConfigMap:                       mf.ConfigMap.DeepCopy(),
+		ClusterRole:                     mf.ClusterRole.DeepCopy(),
+		ClusterRoleBindingK8S:           mf.ClusterRoleBindingK8S.DeepCopy(),
+		ClusterRoleBindingNRT:           mf.ClusterRoleBindingNRT.DeepCopy(),
+		ClusterRoleBindingAuthDelegator: mf.ClusterRoleBindingAuthDelegator.DeepCopy(),
+		Deployment:                      mf.Deployment.DeepCopy(),
+		PodDisruptionBudget:             mf.PodDisruptionBudget.DeepCopy(),
+		StatusService:                   mf.StatusService.DeepCopy(),
 	}
 }
 
@@ -85,10 +96,25 @@ func GetManifests(namespace string) (Manifests, error) {
 		return mf, err
 	}
 
+	mf.ClusterRoleBindingAuthDelegator, err = manifests.ClusterRoleBindingAuthDelegator(namespace)
+	if err != nil {
+		return mf, err
+	}
+
 	mf.Deployment, err = manifests.Deployment(namespace)
 	if err != nil {
 		return mf, err
 	}
 
+	mf.PodDisruptionBudget, err = manifests.PodDisruptionBudget(namespace)
+	if err != nil {
+		return mf, err
+	}
+
+	mf.StatusService, err = manifests.StatusService(namespace)
+	if err != nil {
+		return mf, err
+	}
+
 	return mf, nil
 }
@@ -32,6 +32,12 @@ func TestGetManifests(t *testing.T) {
 			t.Fatalf("GetManifests(): loaded nil manifest")
 		}
 	}
+	if mf.PodDisruptionBudget == nil {
+		t.Fatalf("GetManifests(): loaded nil PodDisruptionBudget")
+	}
+	if mf.StatusService == nil {
+		t.Fatalf("GetManifests(): loaded nil StatusService")
+	}
 }
 
 func TestCloneManifests(t *testing.T) {
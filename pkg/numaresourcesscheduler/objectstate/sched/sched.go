@@ -21,11 +21,12 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-
-	"github.com/k8stopologyawareschedwg/deployer/pkg/manifests"
+	"sigs.k8s.io/yaml"
 
 	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
 	schedmanifests "github.com/openshift-kni/numaresources-operator/pkg/numaresourcesscheduler/manifests/sched"
@@ -47,7 +48,10 @@ type ExistingManifests struct {
 	clusterRoleError           error
 	clusterRoleBindingK8SError error
 	clusterRoleBindingNRTError error
+	clusterRoleBindingADError  error
 	deploymentError            error
+	podDisruptionBudgetError   error
+	statusServiceError         error
 }
 
 func (em ExistingManifests) State(mf schedmanifests.Manifests) []objectstate.ObjectState {
@@ -87,6 +91,13 @@ func (em ExistingManifests) State(mf schedmanifests.Manifests) []objectstate.Obj
 			Compare:  compare.Object,
 			Merge:    merge.MetadataForUpdate,
 		},
+		{
+			Existing: em.Existing.ClusterRoleBindingAuthDelegator,
+			Error:    em.clusterRoleBindingADError,
+			Desired:  mf.ClusterRoleBindingAuthDelegator.DeepCopy(),
+			Compare:  compare.Object,
+			Merge:    merge.MetadataForUpdate,
+		},
 		{
 			Existing: em.Existing.Deployment,
 			Error:    em.deploymentError,
@@ -97,6 +108,30 @@ func (em ExistingManifests) State(mf schedmanifests.Manifests) []objectstate.Obj
 	}
 }
 
+// PodDisruptionBudgetState returns the state of the PodDisruptionBudget, which is managed separately
+// because it is needed only when running more than a replica.
+func (em ExistingManifests) PodDisruptionBudgetState(mf schedmanifests.Manifests) objectstate.ObjectState {
+	return objectstate.ObjectState{
+		Existing: em.Existing.PodDisruptionBudget,
+		Error:    em.podDisruptionBudgetError,
+		Desired:  mf.PodDisruptionBudget.DeepCopy(),
+		Compare:  compare.Object,
+		Merge:    merge.MetadataForUpdate,
+	}
+}
+
+// StatusServiceState returns the state of the service of the cache state server, which is managed separately
+// because it is needed only when serving the cache state over HTTP.
+func (em ExistingManifests) StatusServiceState(mf schedmanifests.Manifests) objectstate.ObjectState {
+	return objectstate.ObjectState{
+		Existing: em.Existing.StatusService,
+		Error:    em.statusServiceError,
+		Desired:  mf.StatusService.DeepCopy(),
+		Compare:  compare.Object,
+		Merge:    merge.ServiceForUpdate,
+	}
+}
+
 func FromClient(ctx context.Context, cli client.Client, mf schedmanifests.Manifests) ExistingManifests {
 	ret := ExistingManifests{
 		Existing: schedmanifests.Manifests{},
@@ -125,14 +160,36 @@ func FromClient(ctx context.Context, cli client.Client, mf schedmanifests.Manife
 	if ret.clusterRoleBindingNRTError = cli.Get(ctx, client.ObjectKeyFromObject(mf.ClusterRoleBindingNRT), crbNRT); ret.clusterRoleBindingNRTError == nil {
 		ret.Existing.ClusterRoleBindingNRT = crbNRT
 	}
+	crbAD := &rbacv1.ClusterRoleBinding{}
+	if ret.clusterRoleBindingADError = cli.Get(ctx, client.ObjectKeyFromObject(mf.ClusterRoleBindingAuthDelegator), crbAD); ret.clusterRoleBindingADError == nil {
+		ret.Existing.ClusterRoleBindingAuthDelegator = crbAD
+	}
 
 	dp := &appsv1.Deployment{}
 	if ret.deploymentError = cli.Get(ctx, client.ObjectKeyFromObject(mf.Deployment), dp); ret.deploymentError == nil {
 		ret.Existing.Deployment = dp
 	}
+
+	pdb := &policyv1.PodDisruptionBudget{}
+	if ret.podDisruptionBudgetError = cli.Get(ctx, client.ObjectKeyFromObject(mf.PodDisruptionBudget), pdb); ret.podDisruptionBudgetError == nil {
+		ret.Existing.PodDisruptionBudget = pdb
+	}
+
+	svc := &corev1.Service{}
+	if ret.statusServiceError = cli.Get(ctx, client.ObjectKeyFromObject(mf.StatusService), svc); ret.statusServiceError == nil {
+		ret.Existing.StatusService = svc
+	}
 	return ret
 }
 
+// LeaderElectionLease returns the lease the replicas of the scheduler deployment use to elect a leader
+func LeaderElectionLease(dp *appsv1.Deployment) nropv1.NamespacedName {
+	return nropv1.NamespacedName{
+		Namespace: dp.Namespace,
+		Name:      dp.Name,
+	}
+}
+
 func DeploymentNamespacedNameFromObject(obj client.Object) (nropv1.NamespacedName, bool) {
 	res := nropv1.NamespacedName{
 		Namespace: obj.GetNamespace(),
@@ -156,19 +213,30 @@ func SchedulerNameFromObject(obj client.Object) (string, bool) {
 		return "", false
 	}
 
-	allParams, err := manifests.DecodeSchedulerProfilesFromData([]byte(data))
-	if err != nil {
+	// we only need the profile names, so we don't decode the plugin args: the deployer
+	// rejects the settings it doesn't know about, like some scoring strategies.
+	var conf map[string]interface{}
+	if err := yaml.Unmarshal([]byte(data), &conf); err != nil {
+		return "", false
+	}
+	profiles, ok, err := unstructured.NestedSlice(conf, "profiles")
+	if !ok || err != nil || len(profiles) == 0 {
+		return "", false
+	}
+	profile, ok := profiles[0].(map[string]interface{})
+	if !ok {
 		return "", false
 	}
-	if len(allParams) == 0 {
+	profileName, ok, err := unstructured.NestedString(profile, "schedulerName")
+	if !ok || err != nil {
 		return "", false
 	}
 
-	params := allParams[0]
-	if len(allParams) > 1 {
-		klog.InfoS("detected more params than expected, using first", "profileName", params.ProfileName, "count", len(allParams))
+	// the main profile is always the first, the others are the additional profiles
+	if len(profiles) > 1 {
+		klog.V(4).InfoS("detected additional scheduler profiles, using first", "profileName", profileName, "count", len(profiles))
 	}
-	return params.ProfileName, true
+	return profileName, true
 }
 
 func NewSchedConfigVolume(schedVolumeConfigName, configMapName string) corev1.Volume {
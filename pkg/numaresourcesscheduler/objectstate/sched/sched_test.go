@@ -85,6 +85,29 @@ profiles:
     - name: NodeResourceTopologyMatch
       args:
         kubeconfigpath: "" # needs to be empty string`
+
+	schedConfigRequestedToCapacityRatio = `apiVersion: kubescheduler.config.k8s.io/v1
+kind: KubeSchedulerConfiguration
+leaderElection:
+  leaderElect: false
+profiles:
+  - schedulerName: test-topo-aware-sched
+    plugins:
+      score:
+        enabled:
+          - name: NodeResourceTopologyMatch
+    pluginConfig:
+    - name: NodeResourceTopologyMatch
+      args:
+        scoringStrategy:
+          type: RequestedToCapacityRatio
+          requestedToCapacityRatio:
+            shape:
+            - utilization: 0
+              score: 10
+            - utilization: 100
+              score: 0
+  - schedulerName: test-packing-sched`
 )
 
 func TestSchedulerNameFromObject(t *testing.T) {
@@ -149,6 +172,20 @@ func TestSchedulerNameFromObject(t *testing.T) {
 			expectedFound: true,
 			expectedName:  "test-topo-aware-sched",
 		},
+		{
+			name: "requested-to-capacity-ratio-config",
+			configMap: corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "requested-to-capacity-ratio-config",
+					Namespace: "test-ns",
+				},
+				Data: map[string]string{
+					"config.yaml": schedConfigRequestedToCapacityRatio,
+				},
+			},
+			expectedFound: true,
+			expectedName:  "test-topo-aware-sched",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -42,6 +42,23 @@ func ServiceAccountForUpdate(current, updated client.Object) (client.Object, err
 	return MetadataForUpdate(current, updated)
 }
 
+// ServiceForUpdate preserves the fields the API server allocates, which can't be changed on update
+func ServiceForUpdate(current, updated client.Object) (client.Object, error) {
+	curSvc, ok := current.(*corev1.Service)
+	if !ok {
+		return updated, ErrWrongObjectType
+	}
+	updSvc, ok := updated.(*corev1.Service)
+	if !ok {
+		return updated, ErrMismatchingObjects
+	}
+	updSvc.Spec.ClusterIP = curSvc.Spec.ClusterIP
+	updSvc.Spec.ClusterIPs = curSvc.Spec.ClusterIPs
+	updSvc.Spec.IPFamilies = curSvc.Spec.IPFamilies
+	updSvc.Spec.IPFamilyPolicy = curSvc.Spec.IPFamilyPolicy
+	return MetadataForUpdate(current, updated)
+}
+
 func ObjectForUpdate(current, updated client.Object) (client.Object, error) {
 	return MetadataForUpdate(current, updated)
 }
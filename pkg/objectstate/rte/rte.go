@@ -23,6 +23,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -69,6 +70,9 @@ type ExistingManifests struct {
 	namespace string
 }
 
+// MachineConfigsState returns the state of the machine configs of the MachineConfigPools of all the trees.
+// The RTE SCC forces the SELinux type the machine config installs the policy of, so the nodes of the node groups
+// defined by NodeSelector need it as well, and get it through the MachineConfigPools they belong to.
 func (em *ExistingManifests) MachineConfigsState(mf rtemanifests.Manifests) []objectstate.ObjectState {
 	var ret []objectstate.ObjectState
 	if mf.MachineConfig == nil {
@@ -125,14 +129,15 @@ func GetMachineConfigLabel(mcp *machineconfigv1.MachineConfigPool) map[string]st
 
 type GeneratedDesiredManifest struct {
 	// context
-	ClusterPlatform   platform.Platform
+	ClusterPlatform platform.Platform
+	// MachineConfigPool is nil for node groups defined by NodeSelector
 	MachineConfigPool *machineconfigv1.MachineConfigPool
 	NodeGroup         *nropv1.NodeGroup
 	// generated manifests
 	DaemonSet *appsv1.DaemonSet
 }
 
-type GenerateDesiredManifestUpdater func(mcpName string, gdm *GeneratedDesiredManifest) error
+type GenerateDesiredManifestUpdater func(poolName string, gdm *GeneratedDesiredManifest) error
 
 func SkipManifestUpdate(gdm *GeneratedDesiredManifest) error {
 	return nil
@@ -193,11 +198,11 @@ func (em *ExistingManifests) State(mf rtemanifests.Manifests, updater GenerateDe
 	}
 
 	for _, tree := range em.trees {
-		for _, mcp := range tree.MachineConfigPools {
+		for _, pool := range tree.Pools() {
 			var existingDs client.Object
 			var loadError error
 
-			generatedName := objectnames.GetComponentName(em.instance.Name, mcp.Name)
+			generatedName := objectnames.GetComponentName(em.instance.Name, pool.Name)
 			existingDaemonSet, ok := em.daemonSets[generatedName]
 			if ok {
 				existingDs = existingDaemonSet.daemonSet
@@ -210,23 +215,23 @@ func (em *ExistingManifests) State(mf rtemanifests.Manifests, updater GenerateDe
 			desiredDaemonSet.Name = generatedName
 
 			var updateError error
-			if mcp.Spec.NodeSelector != nil {
-				desiredDaemonSet.Spec.Template.Spec.NodeSelector = mcp.Spec.NodeSelector.MatchLabels
+			if pool.NodeSelector != nil {
+				SetDaemonSetNodeSelector(desiredDaemonSet, pool.NodeSelector)
 			} else {
-				updateError = fmt.Errorf("the machine config pool %q does not have node selector", mcp.Name)
+				updateError = fmt.Errorf("the pool %q does not have node selector", pool.Name)
 			}
 
 			if updater != nil {
 				gdm := GeneratedDesiredManifest{
 					ClusterPlatform:   em.plat,
-					MachineConfigPool: mcp.DeepCopy(),
+					MachineConfigPool: pool.MachineConfigPool.DeepCopy(),
 					NodeGroup:         tree.NodeGroup.DeepCopy(),
 					DaemonSet:         desiredDaemonSet,
 				}
 
-				err := updater(mcp.Name, &gdm)
+				err := updater(pool.Name, &gdm)
 				if err != nil {
-					updateError = fmt.Errorf("daemonset for MCP %q: update failed: %w", mcp.Name, err)
+					updateError = fmt.Errorf("daemonset for pool %q: update failed: %w", pool.Name, err)
 				}
 			}
 
@@ -292,8 +297,8 @@ func FromClient(ctx context.Context, cli client.Client, plat platform.Platform,
 
 	// should have the amount of resources equals to the amount of node groups
 	for _, tree := range trees {
-		for _, mcp := range tree.MachineConfigPools {
-			generatedName := objectnames.GetComponentName(instance.Name, mcp.Name)
+		for _, pool := range tree.Pools() {
+			generatedName := objectnames.GetComponentName(instance.Name, pool.Name)
 			key := client.ObjectKey{
 				Name:      generatedName,
 				Namespace: namespace,
@@ -304,25 +309,56 @@ func FromClient(ctx context.Context, cli client.Client, plat platform.Platform,
 				dsm.daemonSet = ds
 			}
 			ret.daemonSets[generatedName] = dsm
+		}
 
-			if plat == platform.OpenShift {
-				mcName := objectnames.GetMachineConfigName(instance.Name, mcp.Name)
-				key := client.ObjectKey{
-					Name: mcName,
-				}
-				mc := &machineconfigv1.MachineConfig{}
-				mcm := machineConfigManifest{}
-				if mcm.machineConfigError = cli.Get(ctx, key, mc); mcm.machineConfigError == nil {
-					mcm.machineConfig = mc
-				}
-				ret.machineConfigs[mcName] = mcm
+		if plat != platform.OpenShift {
+			continue
+		}
+		// the node groups defined by NodeSelector need the machine configs too, see MachineConfigsState
+		for _, mcp := range tree.MachineConfigPools {
+			mcName := objectnames.GetMachineConfigName(instance.Name, mcp.Name)
+			key := client.ObjectKey{
+				Name: mcName,
+			}
+			mc := &machineconfigv1.MachineConfig{}
+			mcm := machineConfigManifest{}
+			if mcm.machineConfigError = cli.Get(ctx, key, mc); mcm.machineConfigError == nil {
+				mcm.machineConfig = mc
 			}
+			ret.machineConfigs[mcName] = mcm
 		}
 	}
 
 	return ret
 }
 
+// SetDaemonSetNodeSelector makes the daemonset pods run only on the nodes matching the given selector.
+// MatchLabels are translated to the pod node selector, MatchExpressions to required node affinity terms.
+func SetDaemonSetNodeSelector(ds *appsv1.DaemonSet, sel *metav1.LabelSelector) {
+	podSpec := &ds.Spec.Template.Spec
+	podSpec.NodeSelector = sel.MatchLabels
+	if len(sel.MatchExpressions) == 0 {
+		return
+	}
+
+	term := corev1.NodeSelectorTerm{}
+	for _, expr := range sel.MatchExpressions {
+		term.MatchExpressions = append(term.MatchExpressions, corev1.NodeSelectorRequirement{
+			Key:      expr.Key,
+			Operator: corev1.NodeSelectorOperator(expr.Operator),
+			Values:   expr.Values,
+		})
+	}
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	podSpec.Affinity.NodeAffinity = &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{term},
+		},
+	}
+}
+
 func DaemonSetNamespacedNameFromObject(obj client.Object) (nropv1.NamespacedName, bool) {
 	res := nropv1.NamespacedName{
 		Namespace: obj.GetNamespace(),
@@ -17,6 +17,7 @@
 package rte
 
 import (
+	"reflect"
 	"testing"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -86,3 +87,73 @@ func TestDaemonSetNamespacedNameFromObject(t *testing.T) {
 		})
 	}
 }
+
+func TestSetDaemonSetNodeSelector(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		sel                  *metav1.LabelSelector
+		expectedNodeSelector map[string]string
+		expectedAffinity     *corev1.Affinity
+	}{
+		{
+			name: "match labels only",
+			sel: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"node-role.kubernetes.io/worker": "",
+				},
+			},
+			expectedNodeSelector: map[string]string{
+				"node-role.kubernetes.io/worker": "",
+			},
+		},
+		{
+			name: "match labels and expressions",
+			sel: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"node-role.kubernetes.io/worker": "",
+				},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      "numa-aware",
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   []string{"true"},
+					},
+				},
+			},
+			expectedNodeSelector: map[string]string{
+				"node-role.kubernetes.io/worker": "",
+			},
+			expectedAffinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{
+								MatchExpressions: []corev1.NodeSelectorRequirement{
+									{
+										Key:      "numa-aware",
+										Operator: corev1.NodeSelectorOpIn,
+										Values:   []string{"true"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ds := &appsv1.DaemonSet{}
+			SetDaemonSetNodeSelector(ds, tc.sel)
+			podSpec := ds.Spec.Template.Spec
+			if !reflect.DeepEqual(podSpec.NodeSelector, tc.expectedNodeSelector) {
+				t.Errorf("node selector mismatch: got=%v expected=%v", podSpec.NodeSelector, tc.expectedNodeSelector)
+			}
+			if !reflect.DeepEqual(podSpec.Affinity, tc.expectedAffinity) {
+				t.Errorf("affinity mismatch: got=%v expected=%v", podSpec.Affinity, tc.expectedAffinity)
+			}
+		})
+	}
+}
@@ -44,6 +44,18 @@ const (
 	pfpStatusDir       = "/run/pfpstatus"
 )
 
+// the RTE metrics server settings
+const (
+	MetricsPortName = "metrics-port"
+	MetricsPort     = 2112
+	// MetricsSecretName is the service-serving cert secret the RTE metrics service asks for
+	MetricsSecretName = "rte-metrics-service-cert"
+
+	metricsCertsMountName = "rte-metrics-service-cert"
+	metricsCertsDir       = "/etc/secrets/rte"
+	metricsServingTLS     = "httptls"
+)
+
 func DaemonSetUserImageSettings(ds *appsv1.DaemonSet, userImageSpec, builtinImageSpec string, builtinPullPolicy corev1.PullPolicy) error {
 	cnt := k8swgobjupdate.FindContainerByName(ds.Spec.Template.Spec.Containers, MainContainerName)
 	if cnt == nil {
@@ -147,6 +159,10 @@ func DaemonSetArgs(ds *appsv1.DaemonSet, conf nropv1.NodeGroupConfig) error {
 	}
 
 	flags.SetToggle("--refresh-node-resources")
+	// the operator detects the RTE instances which stopped refreshing the NRT objects from the time of their last update,
+	// compared to the refresh interval they advertise
+	flags.SetToggle("--expose-timing")
+	flags.SetToggle("--expose-last-update")
 
 	notifEnabled := isNotifyFileEnabled(&conf)
 	klog.V(2).InfoS("DaemonSet update: event notification", "daemonset", ds.Name, "enabled", notifEnabled)
@@ -182,6 +198,58 @@ func DaemonSetArgs(ds *appsv1.DaemonSet, conf nropv1.NodeGroupConfig) error {
 	return nil
 }
 
+// DaemonSetMetrics makes the RTE serve its metrics over TLS using the certificate from MetricsSecretName, or
+// stops serving them. The DaemonSet may be updated more than once, so all the changes must be idempotent.
+func DaemonSetMetrics(ds *appsv1.DaemonSet, mode nropv1.RTEMetricsMode) error {
+	cnt := k8swgobjupdate.FindContainerByName(ds.Spec.Template.Spec.Containers, MainContainerName)
+	if cnt == nil {
+		return fmt.Errorf("cannot find container data for %q", MainContainerName)
+	}
+	flags := flagcodec.ParseArgvKeyValue(cnt.Args, flagcodec.WithFlagNormalization)
+	if flags == nil {
+		return fmt.Errorf("cannot modify the arguments for container %s", cnt.Name)
+	}
+
+	podSpec := &ds.Spec.Template.Spec // shortcut
+	enabled := (mode == nropv1.RTEMetricsEnabled)
+	klog.V(2).InfoS("DaemonSet update: metrics serving", "daemonset", ds.Name, "enabled", enabled)
+	if !enabled {
+		flags.Delete("--metrics-mode")
+		flags.Delete("--metrics-tls-cert-file")
+		flags.Delete("--metrics-tls-key-file")
+		cnt.Args = flags.Argv()
+		cnt.Ports = removeContainerPort(cnt.Ports, MetricsPortName)
+		cnt.VolumeMounts = removeVolumeMount(cnt.VolumeMounts, metricsCertsMountName)
+		podSpec.Volumes = removeVolume(podSpec.Volumes, metricsCertsMountName)
+		return nil
+	}
+
+	flags.SetOption("--metrics-mode", metricsServingTLS)
+	flags.SetOption("--metrics-tls-cert-file", filepath.Join(metricsCertsDir, "tls.crt"))
+	flags.SetOption("--metrics-tls-key-file", filepath.Join(metricsCertsDir, "tls.key"))
+	cnt.Args = flags.Argv()
+
+	cnt.Ports = append(removeContainerPort(cnt.Ports, MetricsPortName), corev1.ContainerPort{
+		Name:          MetricsPortName,
+		ContainerPort: MetricsPort,
+		Protocol:      corev1.ProtocolTCP,
+	})
+	cnt.VolumeMounts = append(removeVolumeMount(cnt.VolumeMounts, metricsCertsMountName), corev1.VolumeMount{
+		Name:      metricsCertsMountName,
+		MountPath: metricsCertsDir,
+		ReadOnly:  true,
+	})
+	podSpec.Volumes = append(removeVolume(podSpec.Volumes, metricsCertsMountName), corev1.Volume{
+		Name: metricsCertsMountName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: MetricsSecretName,
+			},
+		},
+	})
+	return nil
+}
+
 func DaemonSetTolerations(ds *appsv1.DaemonSet, userTolerations []corev1.Toleration) {
 	if len(userTolerations) == 0 {
 		return
@@ -219,6 +287,39 @@ func AddVolumeMountMemory(podSpec *corev1.PodSpec, cnt *corev1.Container, mountN
 	)
 }
 
+func removeContainerPort(ports []corev1.ContainerPort, name string) []corev1.ContainerPort {
+	var ret []corev1.ContainerPort
+	for _, port := range ports {
+		if port.Name == name {
+			continue
+		}
+		ret = append(ret, port)
+	}
+	return ret
+}
+
+func removeVolumeMount(mounts []corev1.VolumeMount, name string) []corev1.VolumeMount {
+	var ret []corev1.VolumeMount
+	for _, mount := range mounts {
+		if mount.Name == name {
+			continue
+		}
+		ret = append(ret, mount)
+	}
+	return ret
+}
+
+func removeVolume(volumes []corev1.Volume, name string) []corev1.Volume {
+	var ret []corev1.Volume
+	for _, vol := range volumes {
+		if vol.Name == name {
+			continue
+		}
+		ret = append(ret, vol)
+	}
+	return ret
+}
+
 func isPodFingerprintEnabled(conf *nropv1.NodeGroupConfig) (bool, string) {
 	cfg := nropv1.DefaultNodeGroupConfig()
 	if conf == nil || conf.PodsFingerprinting == nil {
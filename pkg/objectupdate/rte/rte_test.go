@@ -84,7 +84,7 @@ func TestUpdateDaemonSetArgs(t *testing.T) {
 			name: "defaults",
 			conf: nropv1.DefaultNodeGroupConfig(),
 			expectedArgs: []string{
-				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=with-exclusive-resources", "--refresh-node-resources", "--add-nrt-owner=false", "--sleep-interval=10s",
+				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=with-exclusive-resources", "--refresh-node-resources", "--expose-timing", "--expose-last-update", "--add-nrt-owner=false", "--sleep-interval=10s",
 			},
 		},
 		{
@@ -95,7 +95,7 @@ func TestUpdateDaemonSetArgs(t *testing.T) {
 				},
 			},
 			expectedArgs: []string{
-				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=with-exclusive-resources", "--refresh-node-resources", "--add-nrt-owner=false", "--sleep-interval=32s",
+				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=with-exclusive-resources", "--refresh-node-resources", "--expose-timing", "--expose-last-update", "--add-nrt-owner=false", "--sleep-interval=32s",
 			},
 		},
 		{
@@ -104,7 +104,7 @@ func TestUpdateDaemonSetArgs(t *testing.T) {
 				PodsFingerprinting: &pfpEnabled,
 			},
 			expectedArgs: []string{
-				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=all", "--refresh-node-resources", "--add-nrt-owner=false", "--sleep-interval=10s",
+				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=all", "--refresh-node-resources", "--expose-timing", "--expose-last-update", "--add-nrt-owner=false", "--sleep-interval=10s",
 			},
 		},
 		{
@@ -113,7 +113,7 @@ func TestUpdateDaemonSetArgs(t *testing.T) {
 				PodsFingerprinting: &pfpDisabled,
 			},
 			expectedArgs: []string{
-				"--refresh-node-resources", "--add-nrt-owner=false", "--sleep-interval=10s",
+				"--refresh-node-resources", "--expose-timing", "--expose-last-update", "--add-nrt-owner=false", "--sleep-interval=10s",
 			},
 		},
 		{
@@ -122,7 +122,7 @@ func TestUpdateDaemonSetArgs(t *testing.T) {
 				InfoRefreshMode: &refreshEvents,
 			},
 			expectedArgs: []string{
-				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=with-exclusive-resources", "--refresh-node-resources", "--add-nrt-owner=false", "--notify-file=/run/rte/notify",
+				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=with-exclusive-resources", "--refresh-node-resources", "--expose-timing", "--expose-last-update", "--add-nrt-owner=false", "--notify-file=/run/rte/notify",
 			},
 		},
 		{
@@ -131,7 +131,7 @@ func TestUpdateDaemonSetArgs(t *testing.T) {
 				InfoRefreshMode: &refreshPeriodic,
 			},
 			expectedArgs: []string{
-				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=with-exclusive-resources", "--refresh-node-resources", "--add-nrt-owner=false", "--sleep-interval=10s",
+				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=with-exclusive-resources", "--refresh-node-resources", "--expose-timing", "--expose-last-update", "--add-nrt-owner=false", "--sleep-interval=10s",
 			},
 		},
 		{
@@ -140,7 +140,7 @@ func TestUpdateDaemonSetArgs(t *testing.T) {
 				InfoRefreshPause: &infoRefreshPauseEnabled,
 			},
 			expectedArgs: []string{
-				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=with-exclusive-resources", "--no-publish", "--refresh-node-resources", "--add-nrt-owner=false", "--sleep-interval=10s",
+				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=with-exclusive-resources", "--no-publish", "--refresh-node-resources", "--expose-timing", "--expose-last-update", "--add-nrt-owner=false", "--sleep-interval=10s",
 			},
 		},
 		{
@@ -149,7 +149,7 @@ func TestUpdateDaemonSetArgs(t *testing.T) {
 				InfoRefreshPause: &infoRefreshPauseDisabled,
 			},
 			expectedArgs: []string{
-				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=with-exclusive-resources", "--refresh-node-resources", "--add-nrt-owner=false", "--sleep-interval=10s",
+				"--pods-fingerprint", "--pods-fingerprint-status-file=/run/pfpstatus/dump.json", "--pods-fingerprint-method=with-exclusive-resources", "--refresh-node-resources", "--expose-timing", "--expose-last-update", "--add-nrt-owner=false", "--sleep-interval=10s",
 			},
 		},
 	}
@@ -280,6 +280,41 @@ func TestUpdateDaemonSetTolerations(t *testing.T) {
 	}
 }
 
+func TestUpdateDaemonSetMetrics(t *testing.T) {
+	origDs := testDs.DeepCopy()
+	ds := testDs.DeepCopy()
+
+	// must be idempotent, the same DaemonSet can be updated more than once
+	for i := 0; i < 2; i++ {
+		if err := DaemonSetMetrics(ds, nropv1.RTEMetricsEnabled); err != nil {
+			t.Fatalf("enable metrics failed: %v", err)
+		}
+	}
+	expectCommandLine(t, ds, origDs, "metrics enabled", []string{
+		"--metrics-mode=httptls",
+		"--metrics-tls-cert-file=/etc/secrets/rte/tls.crt",
+		"--metrics-tls-key-file=/etc/secrets/rte/tls.key",
+	})
+	cnt := ds.Spec.Template.Spec.Containers[0]
+	if len(cnt.Ports) != 1 || cnt.Ports[0].Name != MetricsPortName || cnt.Ports[0].ContainerPort != MetricsPort {
+		t.Errorf("unexpected container ports: %v", cnt.Ports)
+	}
+	if len(cnt.VolumeMounts) != 1 {
+		t.Errorf("unexpected volume mounts: %v", cnt.VolumeMounts)
+	}
+	vols := ds.Spec.Template.Spec.Volumes
+	if len(vols) != 1 || vols[0].Secret == nil || vols[0].Secret.SecretName != MetricsSecretName {
+		t.Errorf("unexpected volumes: %v", vols)
+	}
+
+	if err := DaemonSetMetrics(ds, nropv1.RTEMetricsDisabled); err != nil {
+		t.Fatalf("disable metrics failed: %v", err)
+	}
+	if !reflect.DeepEqual(ds.Spec.Template.Spec, origDs.Spec.Template.Spec) {
+		t.Errorf("metrics settings not removed: %#v", ds.Spec.Template.Spec)
+	}
+}
+
 func expectCommandLine(t *testing.T, ds, origDs *appsv1.DaemonSet, testName string, expectedArgs []string) {
 	expectedArgs = append(expectedArgs, commonArgs...)
 	actualArgsSet := getSetFromStringList(ds.Spec.Template.Spec.Containers[0].Args)
@@ -0,0 +1,216 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sched
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	k8swgmanifests "github.com/k8stopologyawareschedwg/deployer/pkg/manifests"
+
+	schedstate "github.com/openshift-kni/numaresources-operator/pkg/numaresourcesscheduler/objectstate/sched"
+)
+
+// overlayMergeKeys maps the lists of the scheduler config which are merged by key, like the strategic merge
+// patch does for the core types. All the other lists are replaced.
+var overlayMergeKeys = map[string]string{
+	"profiles":     "schedulerName",
+	"pluginConfig": "name",
+	"enabled":      "name",
+	"disabled":     "name",
+}
+
+// nodeAffinityPluginName is the plugin filtering out the nodes with stale topology data, through the addedAffinity
+// set by the operator. Like NodeResourceTopologyMatch, its configuration can't be changed by the overlay.
+const nodeAffinityPluginName = "NodeAffinity"
+
+// SchedulerConfigOverlay merges the partial scheduler configuration overlayData on top of the rendered configuration.
+// Maps are merged recursively, the profiles are merged by scheduler name, the plugins and their configuration are merged
+// by plugin name. A null value removes the key. The overlay cannot change the fields managed by the operator.
+func SchedulerConfigOverlay(cm *corev1.ConfigMap, overlayData string) error {
+	if cm.Data == nil {
+		return fmt.Errorf("no data found in ConfigMap: %s/%s", cm.Namespace, cm.Name)
+	}
+
+	data, ok := cm.Data[schedstate.SchedulerConfigFileName]
+	if !ok {
+		return fmt.Errorf("no data key named: %s found in ConfigMap: %s/%s", schedstate.SchedulerConfigFileName, cm.Namespace, cm.Name)
+	}
+
+	var conf map[string]interface{}
+	if err := yaml.Unmarshal([]byte(data), &conf); err != nil {
+		return err
+	}
+	var overlay map[string]interface{}
+	if err := yaml.Unmarshal([]byte(overlayData), &overlay); err != nil {
+		return fmt.Errorf("malformed scheduler config overlay: %w", err)
+	}
+
+	if err := validateConfigOverlay(conf, overlay); err != nil {
+		return err
+	}
+
+	merged, err := mergeConfigValues("", conf, overlay)
+	if err != nil {
+		return err
+	}
+
+	newData, err := yaml.Marshal(merged)
+	if err != nil {
+		return err
+	}
+	klog.V(4).InfoS("scheduler config overlay applied", "keys", len(overlay))
+
+	cm.Data[schedstate.SchedulerConfigFileName] = string(newData)
+	return nil
+}
+
+func validateConfigOverlay(conf, overlay map[string]interface{}) error {
+	for _, field := range []string{"apiVersion", "kind"} {
+		val, ok := overlay[field]
+		if ok && val != conf[field] {
+			return fmt.Errorf("the overlay cannot change the %s", field)
+		}
+	}
+	if _, ok := overlay["leaderElection"]; ok {
+		return fmt.Errorf("the overlay cannot change the leaderElection, which is managed by the operator")
+	}
+
+	overlayProfiles, _, err := unstructured.NestedSlice(overlay, "profiles")
+	if err != nil {
+		return fmt.Errorf("malformed overlay profiles: %w", err)
+	}
+	profiles, _, err := unstructured.NestedSlice(conf, "profiles")
+	if err != nil {
+		return err
+	}
+	for idx, prof := range overlayProfiles {
+		profile, ok := prof.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("malformed overlay profile %d", idx)
+		}
+		profileName, ok, err := unstructured.NestedString(profile, "schedulerName")
+		if !ok || err != nil {
+			return fmt.Errorf("the overlay profile %d must have a schedulerName", idx)
+		}
+		if findByKey(profiles, "schedulerName", profileName) < 0 {
+			return fmt.Errorf("the overlay profile %q does not match any scheduler profile; the profiles are managed by the operator", profileName)
+		}
+
+		pluginConfigs, _, err := unstructured.NestedSlice(profile, "pluginConfig")
+		if err != nil {
+			return fmt.Errorf("malformed overlay profile %q pluginConfig: %w", profileName, err)
+		}
+		for _, pluginName := range []string{k8swgmanifests.SchedulerPluginName, nodeAffinityPluginName} {
+			if findByKey(pluginConfigs, "name", pluginName) >= 0 {
+				return fmt.Errorf("the overlay profile %q cannot change the %s args, which are managed by the operator", profileName, pluginName)
+			}
+		}
+
+		plugins, _, err := unstructured.NestedMap(profile, "plugins")
+		if err != nil {
+			return fmt.Errorf("malformed overlay profile %q plugins: %w", profileName, err)
+		}
+		for extPoint := range plugins {
+			disabled, _, err := unstructured.NestedSlice(plugins, extPoint, "disabled")
+			if err != nil {
+				return fmt.Errorf("malformed overlay profile %q plugins %s: %w", profileName, extPoint, err)
+			}
+			for _, pluginName := range []string{k8swgmanifests.SchedulerPluginName, nodeAffinityPluginName} {
+				if findByKey(disabled, "name", pluginName) >= 0 || findByKey(disabled, "name", "*") >= 0 {
+					return fmt.Errorf("the overlay profile %q cannot disable the %s plugin", profileName, pluginName)
+				}
+			}
+			enabled, _, err := unstructured.NestedSlice(plugins, extPoint, "enabled")
+			if err != nil {
+				return fmt.Errorf("malformed overlay profile %q plugins %s: %w", profileName, extPoint, err)
+			}
+			if findByKey(enabled, "name", nodeAffinityPluginName) >= 0 {
+				return fmt.Errorf("the overlay profile %q cannot change the %s plugin, which is managed by the operator", profileName, nodeAffinityPluginName)
+			}
+		}
+	}
+	return nil
+}
+
+func mergeConfigValues(field string, base, overlay interface{}) (interface{}, error) {
+	switch ov := overlay.(type) {
+	case map[string]interface{}:
+		bv, ok := base.(map[string]interface{})
+		if !ok {
+			return ov, nil
+		}
+		for key, val := range ov {
+			if val == nil {
+				delete(bv, key)
+				continue
+			}
+			merged, err := mergeConfigValues(key, bv[key], val)
+			if err != nil {
+				return nil, err
+			}
+			bv[key] = merged
+		}
+		return bv, nil
+	case []interface{}:
+		mergeKey, ok := overlayMergeKeys[field]
+		bv, isList := base.([]interface{})
+		if !ok || !isList {
+			return ov, nil
+		}
+		for idx, item := range ov {
+			elem, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("malformed %s item %d", field, idx)
+			}
+			keyVal, ok := elem[mergeKey].(string)
+			if !ok {
+				return nil, fmt.Errorf("the %s item %d must have a %s", field, idx, mergeKey)
+			}
+			pos := findByKey(bv, mergeKey, keyVal)
+			if pos < 0 {
+				bv = append(bv, elem)
+				continue
+			}
+			merged, err := mergeConfigValues(field, bv[pos], elem)
+			if err != nil {
+				return nil, err
+			}
+			bv[pos] = merged
+		}
+		return bv, nil
+	default:
+		return ov, nil
+	}
+}
+
+func findByKey(items []interface{}, key, value string) int {
+	for idx, item := range items {
+		elem, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if val, ok := elem[key].(string); ok && val == value {
+			return idx
+		}
+	}
+	return -1
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sched
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	schedstate "github.com/openshift-kni/numaresources-operator/pkg/numaresourcesscheduler/objectstate/sched"
+)
+
+const expectedYAMLWithOverlay = `apiVersion: kubescheduler.config.k8s.io/v1
+kind: KubeSchedulerConfiguration
+leaderElection:
+  leaderElect: false
+percentageOfNodesToScore: 50
+profiles:
+- pluginConfig:
+  - args:
+      apiVersion: kubescheduler.config.k8s.io/v1
+      cacheResyncPeriodSeconds: 3
+      kind: NodeResourceTopologyMatchArgs
+      scoringStrategy:
+        resources:
+        - name: cpu
+          weight: 1
+        - name: memory
+          weight: 1
+        type: LeastAllocated
+    name: NodeResourceTopologyMatch
+  - args:
+      hardPodAffinityWeight: 10
+    name: InterPodAffinity
+  plugins:
+    filter:
+      enabled:
+      - name: NodeResourceTopologyMatch
+    reserve:
+      enabled:
+      - name: NodeResourceTopologyMatch
+    score:
+      enabled:
+      - name: NodeResourceTopologyMatch
+        weight: 5
+      - name: InterPodAffinity
+  schedulerName: test-topo-aware-sched
+`
+
+func TestSchedulerConfigOverlay(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		overlay              string
+		expectedYAML         string
+		expectedErrorMessage string
+	}{
+		{
+			name:         "empty overlay",
+			overlay:      "",
+			expectedYAML: "",
+		},
+		{
+			name: "tuning not modelled in the spec",
+			overlay: `percentageOfNodesToScore: 50
+profiles:
+- schedulerName: test-topo-aware-sched
+  plugins:
+    score:
+      enabled:
+      - name: NodeResourceTopologyMatch
+        weight: 5
+      - name: InterPodAffinity
+  pluginConfig:
+  - name: InterPodAffinity
+    args:
+      hardPodAffinityWeight: 10
+`,
+			expectedYAML: expectedYAMLWithOverlay,
+		},
+		{
+			name:                 "malformed overlay",
+			overlay:              "{{{",
+			expectedErrorMessage: "malformed scheduler config overlay",
+		},
+		{
+			name: "leader election",
+			overlay: `leaderElection:
+  leaderElect: true
+`,
+			expectedErrorMessage: "cannot change the leaderElection",
+		},
+		{
+			name: "kind",
+			overlay: `kind: SomethingElse
+`,
+			expectedErrorMessage: "cannot change the kind",
+		},
+		{
+			name: "unknown profile",
+			overlay: `profiles:
+- schedulerName: other-sched
+`,
+			expectedErrorMessage: "does not match any scheduler profile",
+		},
+		{
+			name: "plugin args managed by the operator",
+			overlay: `profiles:
+- schedulerName: test-topo-aware-sched
+  pluginConfig:
+  - name: NodeResourceTopologyMatch
+    args:
+      cacheResyncPeriodSeconds: 10
+`,
+			expectedErrorMessage: "cannot change the NodeResourceTopologyMatch args",
+		},
+		{
+			name: "stale node filter args managed by the operator",
+			overlay: `profiles:
+- schedulerName: test-topo-aware-sched
+  pluginConfig:
+  - name: NodeAffinity
+    args:
+      addedAffinity: null
+`,
+			expectedErrorMessage: "cannot change the NodeAffinity args",
+		},
+		{
+			name: "stale node filter enablement managed by the operator",
+			overlay: `profiles:
+- schedulerName: test-topo-aware-sched
+  plugins:
+    filter:
+      enabled:
+      - name: NodeAffinity
+        weight: 2
+`,
+			expectedErrorMessage: "cannot change the NodeAffinity plugin",
+		},
+		{
+			name: "stale node filter disabled",
+			overlay: `profiles:
+- schedulerName: test-topo-aware-sched
+  plugins:
+    filter:
+      disabled:
+      - name: NodeAffinity
+`,
+			expectedErrorMessage: "cannot disable the NodeAffinity plugin",
+		},
+		{
+			name: "plugin disabled",
+			overlay: `profiles:
+- schedulerName: test-topo-aware-sched
+  plugins:
+    filter:
+      disabled:
+      - name: "*"
+`,
+			expectedErrorMessage: "cannot disable the NodeResourceTopologyMatch plugin",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cm := corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cm",
+					Namespace: "test-ns",
+				},
+				Data: map[string]string{
+					"config.yaml": schedConfig,
+				},
+			}
+
+			err := SchedulerConfigOverlay(&cm, tc.overlay)
+			if tc.expectedErrorMessage != "" {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				if !strings.Contains(err.Error(), tc.expectedErrorMessage) {
+					t.Fatalf("unexpected error message: got=%q expected to contain=%q", err.Error(), tc.expectedErrorMessage)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			gotYAML := cm.Data[schedstate.SchedulerConfigFileName]
+			if tc.expectedYAML == "" {
+				return
+			}
+			yamlCompare(t, tc.name, gotYAML, tc.expectedYAML)
+		})
+	}
+}
@@ -18,10 +18,15 @@ package sched
 
 import (
 	"fmt"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
 
 	k8swgmanifests "github.com/k8stopologyawareschedwg/deployer/pkg/manifests"
 	k8swgobjupdate "github.com/k8stopologyawareschedwg/deployer/pkg/objectupdate"
@@ -38,10 +43,31 @@ const (
 	MainContainerName = "secondary-scheduler"
 )
 
+const (
+	// CacheResyncRequestAnnotation records on the pod template the last time a cache resync was requested
+	CacheResyncRequestAnnotation = "numaresourcesscheduler.nodetopology.openshift.io/cache-resync-requested"
+)
+
 const (
 	PFPStatusDumpEnvVar = "PFP_STATUS_DUMP"
 
 	PFPStatusDir = "/run/pfpstatus"
+
+	PFPStatusVolumeName = "runpfpstatus"
+)
+
+const (
+	// PFPStatusServerContainerName is the sidecar serving over HTTP the status files dumped by the scheduler
+	PFPStatusServerContainerName = "pfpstatus-server"
+	// PFPStatusServerPort is the port the status server listens on
+	PFPStatusServerPort = 8090
+	// PFPStatusServerCommand is the operator binary, which runs the status server
+	PFPStatusServerCommand = "/bin/numaresources-operator"
+	// PFPStatusSecretName is the service-serving cert secret the status service asks for
+	PFPStatusSecretName = "secondary-scheduler-pfpstatus-cert"
+
+	pfpStatusCertsVolumeName = "pfpstatus-cert"
+	pfpStatusCertsDir        = "/etc/secrets/pfpstatus"
 )
 
 // TODO: we should inject also the mount point. As it is now, the information is split between the manifest
@@ -65,13 +91,147 @@ func DeploymentEnvVarSettings(dp *appsv1.Deployment, spec nropv1.NUMAResourcesSc
 	}
 
 	cacheResyncDebug := *spec.CacheResyncDebug
-	if cacheResyncDebug == nropv1.CacheResyncDebugDumpJSONFile {
+	// the status server serves the same files the scheduler dumps
+	if cacheResyncDebug == nropv1.CacheResyncDebugDumpJSONFile || cacheResyncDebug == nropv1.CacheResyncDebugServeHTTP {
 		setContainerEnvVar(cnt, PFPStatusDumpEnvVar, PFPStatusDir)
 	} else {
 		deleteContainerEnvVar(cnt, PFPStatusDumpEnvVar)
 	}
 }
 
+// DeploymentStatusServerSettings adds the status server sidecar if the scheduler cache state is served over HTTP,
+// or removes it otherwise. The sidecar runs the given image, which must be the operator image.
+func DeploymentStatusServerSettings(dp *appsv1.Deployment, spec nropv1.NUMAResourcesSchedulerSpec, imageSpec string) {
+	podSpec := &dp.Spec.Template.Spec // shortcut
+	containers := make([]corev1.Container, 0, len(podSpec.Containers))
+	for _, cnt := range podSpec.Containers {
+		if cnt.Name == PFPStatusServerContainerName {
+			continue
+		}
+		containers = append(containers, cnt)
+	}
+	podSpec.Containers = containers
+	volumes := make([]corev1.Volume, 0, len(podSpec.Volumes))
+	for _, vol := range podSpec.Volumes {
+		if vol.Name == pfpStatusCertsVolumeName {
+			continue
+		}
+		volumes = append(volumes, vol)
+	}
+	podSpec.Volumes = volumes
+
+	if *spec.CacheResyncDebug != nropv1.CacheResyncDebugServeHTTP {
+		return
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: pfpStatusCertsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: PFPStatusSecretName,
+			},
+		},
+	})
+
+	allowPrivilegeEscalation := false
+	podSpec.Containers = append(podSpec.Containers, corev1.Container{
+		Name:    PFPStatusServerContainerName,
+		Image:   imageSpec,
+		Command: []string{PFPStatusServerCommand},
+		Args: []string{
+			"--serve-pfpstatus",
+			fmt.Sprintf("--pfpstatus-dir=%s", PFPStatusDir),
+			fmt.Sprintf("--pfpstatus-bind-address=:%d", PFPStatusServerPort),
+			fmt.Sprintf("--pfpstatus-tls-cert-file=%s/tls.crt", pfpStatusCertsDir),
+			fmt.Sprintf("--pfpstatus-tls-key-file=%s/tls.key", pfpStatusCertsDir),
+		},
+		// the server authorizes the callers which can proxy to this very pod
+		Env: []corev1.EnvVar{
+			{
+				Name: "POD_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+				},
+			},
+			{
+				Name: "POD_NAMESPACE",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+				},
+			},
+		},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "pfpstatus",
+				ContainerPort: PFPStatusServerPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+				corev1.ResourceMemory: resource.MustParse("32Mi"),
+			},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      PFPStatusVolumeName,
+				MountPath: PFPStatusDir,
+				ReadOnly:  true,
+			},
+			{
+				Name:      pfpStatusCertsVolumeName,
+				MountPath: pfpStatusCertsDir,
+				ReadOnly:  true,
+			},
+		},
+	})
+	klog.V(3).InfoS("Scheduler status server", "image", imageSpec, "port", PFPStatusServerPort)
+}
+
+// DeploymentPlacementSettings applies the user-provided placement of the scheduler replicas, keeping the defaults for the unset fields.
+func DeploymentPlacementSettings(dp *appsv1.Deployment, spec nropv1.NUMAResourcesSchedulerSpec) {
+	podSpec := &dp.Spec.Template.Spec // shortcut
+	if len(spec.NodeSelector) > 0 || spec.Affinity != nil {
+		// the default affinity pins the replicas on the control plane nodes, so it must not be merged with a custom placement
+		podSpec.NodeSelector = nil
+		if len(spec.NodeSelector) > 0 {
+			podSpec.NodeSelector = make(map[string]string, len(spec.NodeSelector))
+			for key, val := range spec.NodeSelector {
+				podSpec.NodeSelector[key] = val
+			}
+		}
+		podSpec.Affinity = spec.Affinity.DeepCopy()
+	}
+	if len(spec.Tolerations) > 0 {
+		podSpec.Tolerations = nropv1.CloneTolerations(spec.Tolerations)
+	}
+	if spec.PriorityClassName != "" {
+		podSpec.PriorityClassName = spec.PriorityClassName
+	}
+	klog.V(3).InfoS("Scheduler placement", "nodeSelector", podSpec.NodeSelector, "tolerations", len(podSpec.Tolerations), "priorityClassName", podSpec.PriorityClassName)
+}
+
+// DeploymentResourcesSettings applies the user-provided compute resources of the scheduler container, if any.
+func DeploymentResourcesSettings(dp *appsv1.Deployment, resources *corev1.ResourceRequirements) {
+	if resources == nil {
+		return
+	}
+	cnt := k8swgobjupdate.FindContainerByName(dp.Spec.Template.Spec.Containers, MainContainerName)
+	if cnt == nil {
+		klog.ErrorS(nil, "cannot find container", "name", MainContainerName)
+		return
+	}
+	cnt.Resources = *resources.DeepCopy()
+	klog.V(3).InfoS("Scheduler resources", "reason", "user-provided", "requests", cnt.Resources.Requests, "limits", cnt.Resources.Limits)
+}
+
 func setContainerEnvVar(cnt *corev1.Container, name, value string) {
 	if env := FindEnvVarByName(cnt.Env, name); env != nil {
 		klog.V(2).InfoS("overriding existing environment variable", "name", name, "oldValue", env.Value, "newValue", value)
@@ -118,6 +278,20 @@ func DeploymentConfigMapSettings(dp *appsv1.Deployment, cmName, cmHash string) {
 	template.Annotations[hash.ConfigMapAnnotation] = cmHash
 }
 
+// DeploymentCacheResyncSettings renders the cache resync request annotation from the last cache desync remediation, if any.
+// Changing the annotation makes all the scheduler replicas roll out with a fresh cache.
+func DeploymentCacheResyncSettings(dp *appsv1.Deployment, lastRemediation *nropv1.CacheDesyncRemediationStatus) {
+	template := &dp.Spec.Template // shortcut
+	if lastRemediation == nil || lastRemediation.Mode != nropv1.CacheDesyncRemediationForceResync {
+		delete(template.Annotations, CacheResyncRequestAnnotation)
+		return
+	}
+	if template.Annotations == nil {
+		template.Annotations = map[string]string{}
+	}
+	template.Annotations[CacheResyncRequestAnnotation] = lastRemediation.Time.UTC().Format(time.RFC3339)
+}
+
 func SchedulerConfig(cm *corev1.ConfigMap, name string, params *k8swgmanifests.ConfigParams) error {
 	if cm.Data == nil {
 		return fmt.Errorf("no data found in ConfigMap: %s/%s", cm.Namespace, cm.Name)
@@ -139,3 +313,208 @@ func SchedulerConfig(cm *corev1.ConfigMap, name string, params *k8swgmanifests.C
 	cm.Data[schedstate.SchedulerConfigFileName] = string(newData)
 	return nil
 }
+
+// SchedulerProfilesConfig renders a scheduler profile for each of the given params, all based on the profile named
+// templateName, which is replaced. The params with empty ProfileName keep the template name.
+func SchedulerProfilesConfig(cm *corev1.ConfigMap, templateName string, allParams []k8swgmanifests.ConfigParams) error {
+	if cm.Data == nil {
+		return fmt.Errorf("no data found in ConfigMap: %s/%s", cm.Namespace, cm.Name)
+	}
+
+	data, ok := cm.Data[schedstate.SchedulerConfigFileName]
+	if !ok {
+		return fmt.Errorf("no data key named: %s found in ConfigMap: %s/%s", schedstate.SchedulerConfigFileName, cm.Namespace, cm.Name)
+	}
+
+	var conf map[string]interface{}
+	if err := yaml.Unmarshal([]byte(data), &conf); err != nil {
+		return err
+	}
+
+	profiles, _, err := unstructured.NestedSlice(conf, "profiles")
+	if err != nil {
+		return err
+	}
+	var template map[string]interface{}
+	for _, prof := range profiles {
+		profile, ok := prof.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(profile, "schedulerName"); name == templateName {
+			template = profile
+			break
+		}
+	}
+	if template == nil {
+		return fmt.Errorf("cannot find the scheduler profile %q in ConfigMap: %s/%s", templateName, cm.Namespace, cm.Name)
+	}
+
+	profileNames := make([]string, 0, len(allParams))
+	newProfiles := make([]interface{}, 0, len(allParams))
+	for _, params := range allParams {
+		profileName := params.ProfileName
+		if profileName == "" {
+			profileName = templateName
+		}
+		profile := runtime.DeepCopyJSON(template)
+		if err := unstructured.SetNestedField(profile, profileName, "schedulerName"); err != nil {
+			return err
+		}
+		profileNames = append(profileNames, profileName)
+		newProfiles = append(newProfiles, profile)
+	}
+	if err := unstructured.SetNestedSlice(conf, newProfiles, "profiles"); err != nil {
+		return err
+	}
+
+	newData, err := yaml.Marshal(conf)
+	if err != nil {
+		return err
+	}
+	for idx := range allParams {
+		newData, _, err = k8swgschedupdate.RenderConfig(newData, profileNames[idx], &allParams[idx])
+		if err != nil {
+			return err
+		}
+	}
+	klog.V(4).InfoS("scheduler profiles", "profiles", profileNames)
+
+	cm.Data[schedstate.SchedulerConfigFileName] = string(newData)
+	return nil
+}
+
+// SchedulerRequestedToCapacityRatioConfig sets the RequestedToCapacityRatio scoring strategy with the given shape
+// in the NodeResourceTopologyMatch args of the profile named profileName. The deployer can't render this strategy yet,
+// so it must be called after the profile is rendered.
+func SchedulerRequestedToCapacityRatioConfig(cm *corev1.ConfigMap, profileName string, rtcr *nropv1.RequestedToCapacityRatioParams) error {
+	if cm.Data == nil {
+		return fmt.Errorf("no data found in ConfigMap: %s/%s", cm.Namespace, cm.Name)
+	}
+
+	data, ok := cm.Data[schedstate.SchedulerConfigFileName]
+	if !ok {
+		return fmt.Errorf("no data key named: %s found in ConfigMap: %s/%s", schedstate.SchedulerConfigFileName, cm.Namespace, cm.Name)
+	}
+	if rtcr == nil || len(rtcr.Shape) == 0 {
+		return fmt.Errorf("missing shape for the %s scoring strategy of scheduler profile %q", nropv1.RequestedToCapacityRatio, profileName)
+	}
+
+	var conf map[string]interface{}
+	if err := yaml.Unmarshal([]byte(data), &conf); err != nil {
+		return err
+	}
+
+	profiles, _, err := unstructured.NestedSlice(conf, "profiles")
+	if err != nil {
+		return err
+	}
+	var args map[string]interface{}
+	var pluginConf map[string]interface{}
+	var profile map[string]interface{}
+	for _, prof := range profiles {
+		profile, ok = prof.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, _, _ := unstructured.NestedString(profile, "schedulerName"); name != profileName {
+			continue
+		}
+		pluginConfigs, _, err := unstructured.NestedSlice(profile, "pluginConfig")
+		if err != nil {
+			return err
+		}
+		for _, plConf := range pluginConfigs {
+			pluginConf, ok = plConf.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if name, _, _ := unstructured.NestedString(pluginConf, "name"); name != k8swgmanifests.SchedulerPluginName {
+				continue
+			}
+			args, _, err = unstructured.NestedMap(pluginConf, "args")
+			if err != nil {
+				return err
+			}
+			break
+		}
+		if args == nil {
+			return fmt.Errorf("cannot find the %s args in scheduler profile %q", k8swgmanifests.SchedulerPluginName, profileName)
+		}
+
+		shape := make([]interface{}, 0, len(rtcr.Shape))
+		for _, point := range rtcr.Shape {
+			shape = append(shape, map[string]interface{}{
+				"utilization": int64(point.Utilization),
+				"score":       int64(point.Score),
+			})
+		}
+		if err := unstructured.SetNestedField(args, string(nropv1.RequestedToCapacityRatio), "scoringStrategy", "type"); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedSlice(args, shape, "scoringStrategy", "requestedToCapacityRatio", "shape"); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedMap(pluginConf, args, "args"); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedSlice(profile, pluginConfigs, "pluginConfig"); err != nil {
+			return err
+		}
+		break
+	}
+	if args == nil {
+		return fmt.Errorf("cannot find the scheduler profile %q in ConfigMap: %s/%s", profileName, cm.Namespace, cm.Name)
+	}
+	if err := unstructured.SetNestedSlice(conf, profiles, "profiles"); err != nil {
+		return err
+	}
+
+	newData, err := yaml.Marshal(conf)
+	if err != nil {
+		return err
+	}
+	klog.V(4).InfoS("scheduler scoring strategy", "profileName", profileName, "type", nropv1.RequestedToCapacityRatio, "shapePoints", len(rtcr.Shape))
+
+	cm.Data[schedstate.SchedulerConfigFileName] = string(newData)
+	return nil
+}
+
+// SchedulerLeaderElectionConfig sets the leader election in the scheduler config. When enabled, the replicas
+// elect a leader using the given lease; otherwise the single replica runs unconditionally.
+func SchedulerLeaderElectionConfig(cm *corev1.ConfigMap, leaderElect bool, lease nropv1.NamespacedName) error {
+	if cm.Data == nil {
+		return fmt.Errorf("no data found in ConfigMap: %s/%s", cm.Namespace, cm.Name)
+	}
+
+	data, ok := cm.Data[schedstate.SchedulerConfigFileName]
+	if !ok {
+		return fmt.Errorf("no data key named: %s found in ConfigMap: %s/%s", schedstate.SchedulerConfigFileName, cm.Namespace, cm.Name)
+	}
+
+	var conf map[string]interface{}
+	if err := yaml.Unmarshal([]byte(data), &conf); err != nil {
+		return err
+	}
+
+	leaderElection := map[string]interface{}{
+		"leaderElect": leaderElect,
+	}
+	if leaderElect {
+		leaderElection["resourceLock"] = "leases"
+		leaderElection["resourceNamespace"] = lease.Namespace
+		leaderElection["resourceName"] = lease.Name
+	}
+	if err := unstructured.SetNestedField(conf, leaderElection, "leaderElection"); err != nil {
+		return err
+	}
+
+	newData, err := yaml.Marshal(conf)
+	if err != nil {
+		return err
+	}
+	klog.V(4).InfoS("scheduler leader election", "leaderElect", leaderElect, "lease", lease.String())
+
+	cm.Data[schedstate.SchedulerConfigFileName] = string(newData)
+	return nil
+}
@@ -25,7 +25,9 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 
 	k8swgmanifests "github.com/k8stopologyawareschedwg/deployer/pkg/manifests"
 
@@ -319,6 +321,200 @@ func TestUpdateSchedulerConfig(t *testing.T) {
 	}
 }
 
+func TestSchedulerProfilesConfig(t *testing.T) {
+	resyncPeriod := int64(5)
+	mostAllocated := k8swgmanifests.ScoringStrategyMostAllocated
+
+	testCases := []struct {
+		name          string
+		templateName  string
+		allParams     []k8swgmanifests.ConfigParams
+		expectedNames []string
+		expectedTypes []string
+		isErrExpected bool
+	}{
+		{
+			name:         "single profile",
+			templateName: "test-topo-aware-sched",
+			allParams: []k8swgmanifests.ConfigParams{
+				{
+					ProfileName: "test-topo-aware-sched",
+				},
+			},
+			expectedNames: []string{"test-topo-aware-sched"},
+			expectedTypes: []string{k8swgmanifests.ScoringStrategyLeastAllocated},
+		},
+		{
+			name:         "renamed main profile",
+			templateName: "test-topo-aware-sched",
+			allParams: []k8swgmanifests.ConfigParams{
+				{
+					ProfileName: "renamed-sched",
+				},
+			},
+			expectedNames: []string{"renamed-sched"},
+			expectedTypes: []string{k8swgmanifests.ScoringStrategyLeastAllocated},
+		},
+		{
+			name:         "additional profile",
+			templateName: "test-topo-aware-sched",
+			allParams: []k8swgmanifests.ConfigParams{
+				{
+					ProfileName: "test-topo-aware-sched",
+				},
+				{
+					ProfileName: "packing-sched",
+					Cache: &k8swgmanifests.ConfigCacheParams{
+						ResyncPeriodSeconds: &resyncPeriod,
+					},
+					ScoringStrategy: &k8swgmanifests.ScoringStrategyParams{
+						Type: mostAllocated,
+					},
+				},
+			},
+			expectedNames: []string{"test-topo-aware-sched", "packing-sched"},
+			expectedTypes: []string{k8swgmanifests.ScoringStrategyLeastAllocated, k8swgmanifests.ScoringStrategyMostAllocated},
+		},
+		{
+			name:         "missing template profile",
+			templateName: "missing-sched",
+			allParams: []k8swgmanifests.ConfigParams{
+				{
+					ProfileName: "test-topo-aware-sched",
+				},
+			},
+			isErrExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cm := corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cm",
+					Namespace: "test-ns",
+				},
+				Data: map[string]string{
+					"config.yaml": schedConfig,
+				},
+			}
+
+			err := SchedulerProfilesConfig(&cm, tc.templateName, tc.allParams)
+			if tc.isErrExpected {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := k8swgmanifests.DecodeSchedulerProfilesFromData([]byte(cm.Data[schedstate.SchedulerConfigFileName]))
+			if err != nil {
+				t.Fatalf("cannot decode the rendered config: %v", err)
+			}
+			if len(got) != len(tc.expectedNames) {
+				t.Fatalf("unexpected profiles count: got=%d expected=%d", len(got), len(tc.expectedNames))
+			}
+			for idx, params := range got {
+				if params.ProfileName != tc.expectedNames[idx] {
+					t.Errorf("profile %d: unexpected name: got=%q expected=%q", idx, params.ProfileName, tc.expectedNames[idx])
+				}
+				if params.ScoringStrategy == nil || params.ScoringStrategy.Type != tc.expectedTypes[idx] {
+					t.Errorf("profile %d: unexpected scoring strategy: got=%+v expected=%q", idx, params.ScoringStrategy, tc.expectedTypes[idx])
+				}
+			}
+		})
+	}
+}
+
+func TestSchedulerRequestedToCapacityRatioConfig(t *testing.T) {
+	rtcr := &nropv1.RequestedToCapacityRatioParams{
+		Shape: []nropv1.UtilizationShapePoint{
+			{Utilization: 0, Score: 0},
+			{Utilization: 100, Score: 10},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		profileName   string
+		rtcr          *nropv1.RequestedToCapacityRatioParams
+		isErrExpected bool
+	}{
+		{
+			name:        "set shape",
+			profileName: "test-topo-aware-sched",
+			rtcr:        rtcr,
+		},
+		{
+			name:          "missing shape",
+			profileName:   "test-topo-aware-sched",
+			isErrExpected: true,
+		},
+		{
+			name:          "missing profile",
+			profileName:   "missing-sched",
+			rtcr:          rtcr,
+			isErrExpected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cm := corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cm",
+					Namespace: "test-ns",
+				},
+				Data: map[string]string{
+					"config.yaml": schedConfig,
+				},
+			}
+
+			err := SchedulerRequestedToCapacityRatioConfig(&cm, tc.profileName, tc.rtcr)
+			if tc.isErrExpected {
+				if err == nil {
+					t.Fatalf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var conf struct {
+				Profiles []struct {
+					PluginConfig []struct {
+						Name string `json:"name"`
+						Args struct {
+							ScoringStrategy struct {
+								Type                     string                                `json:"type"`
+								Resources                []nropv1.ResourceSpecParams           `json:"resources"`
+								RequestedToCapacityRatio nropv1.RequestedToCapacityRatioParams `json:"requestedToCapacityRatio"`
+							} `json:"scoringStrategy"`
+						} `json:"args"`
+					} `json:"pluginConfig"`
+				} `json:"profiles"`
+			}
+			if err := yaml.Unmarshal([]byte(cm.Data[schedstate.SchedulerConfigFileName]), &conf); err != nil {
+				t.Fatalf("cannot decode the rendered config: %v", err)
+			}
+			got := conf.Profiles[0].PluginConfig[0].Args.ScoringStrategy
+			if got.Type != string(nropv1.RequestedToCapacityRatio) {
+				t.Errorf("unexpected scoring strategy type: %q", got.Type)
+			}
+			if !reflect.DeepEqual(got.RequestedToCapacityRatio, *tc.rtcr) {
+				t.Errorf("unexpected shape: got=%+v expected=%+v", got.RequestedToCapacityRatio, *tc.rtcr)
+			}
+			if len(got.Resources) != 2 {
+				t.Errorf("unexpected resources: %+v", got.Resources)
+			}
+		})
+	}
+}
+
 // TODO: the test depends on the order of the env vars
 func TestDeploymentEnvVarSettings(t *testing.T) {
 	cacheResyncDebugEnabled := nropv1.CacheResyncDebugDumpJSONFile
@@ -422,3 +618,240 @@ func toJSON(obj interface{}) string {
 	}
 	return string(data)
 }
+
+func TestSchedulerLeaderElectionConfig(t *testing.T) {
+	lease := nropv1.NamespacedName{
+		Namespace: "test-ns",
+		Name:      "test-lease",
+	}
+
+	type testCase struct {
+		name        string
+		leaderElect bool
+		expected    map[string]interface{}
+	}
+
+	testCases := []testCase{
+		{
+			name:        "single replica",
+			leaderElect: false,
+			expected: map[string]interface{}{
+				"leaderElect": false,
+			},
+		},
+		{
+			name:        "multiple replicas",
+			leaderElect: true,
+			expected: map[string]interface{}{
+				"leaderElect":       true,
+				"resourceLock":      "leases",
+				"resourceNamespace": "test-ns",
+				"resourceName":      "test-lease",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cm := corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-cm",
+					Namespace: "test-ns",
+				},
+				Data: map[string]string{
+					"config.yaml": schedConfig,
+				},
+			}
+
+			if err := SchedulerLeaderElectionConfig(&cm, tc.leaderElect, lease); err != nil {
+				t.Fatalf("SchedulerLeaderElectionConfig failed: %v", err)
+			}
+
+			var conf map[string]interface{}
+			if err := yaml.Unmarshal([]byte(cm.Data[schedstate.SchedulerConfigFileName]), &conf); err != nil {
+				t.Fatalf("malformed config: %v", err)
+			}
+			if got := conf["leaderElection"]; !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("leaderElection got=%v expected=%v", got, tc.expected)
+			}
+			if _, ok := conf["profiles"]; !ok {
+				t.Errorf("profiles lost")
+			}
+		})
+	}
+}
+
+func TestDeploymentPlacementSettings(t *testing.T) {
+	defaultAffinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{
+								Key:      "node-role.kubernetes.io/control-plane",
+								Operator: corev1.NodeSelectorOpExists,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	defaultTolerations := []corev1.Toleration{
+		{
+			Key:    "node-role.kubernetes.io/control-plane",
+			Effect: corev1.TaintEffectNoSchedule,
+		},
+	}
+	infraTolerations := []corev1.Toleration{
+		{
+			Key:    "node-role.kubernetes.io/infra",
+			Effect: corev1.TaintEffectNoSchedule,
+		},
+	}
+	infraSelector := map[string]string{
+		"node-role.kubernetes.io/infra": "",
+	}
+	infraAffinity := &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: 100,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						TopologyKey: "kubernetes.io/hostname",
+					},
+				},
+			},
+		},
+	}
+
+	type testCase struct {
+		name                 string
+		spec                 nropv1.NUMAResourcesSchedulerSpec
+		expectedNodeSelector map[string]string
+		expectedAffinity     *corev1.Affinity
+		expectedTolerations  []corev1.Toleration
+		expectedPriority     string
+	}
+
+	testCases := []testCase{
+		{
+			name:                "defaults",
+			expectedAffinity:    defaultAffinity,
+			expectedTolerations: defaultTolerations,
+		},
+		{
+			name: "node selector replaces the default affinity",
+			spec: nropv1.NUMAResourcesSchedulerSpec{
+				NodeSelector: infraSelector,
+				Tolerations:  infraTolerations,
+			},
+			expectedNodeSelector: infraSelector,
+			expectedTolerations:  infraTolerations,
+		},
+		{
+			name: "custom affinity",
+			spec: nropv1.NUMAResourcesSchedulerSpec{
+				Affinity: infraAffinity,
+			},
+			expectedAffinity:    infraAffinity,
+			expectedTolerations: defaultTolerations,
+		},
+		{
+			name: "priority class",
+			spec: nropv1.NUMAResourcesSchedulerSpec{
+				PriorityClassName: "system-cluster-critical",
+			},
+			expectedAffinity:    defaultAffinity,
+			expectedTolerations: defaultTolerations,
+			expectedPriority:    "system-cluster-critical",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dp := dpMinimal.DeepCopy()
+			dp.Spec.Template.Spec.Affinity = defaultAffinity.DeepCopy()
+			dp.Spec.Template.Spec.Tolerations = nropv1.CloneTolerations(defaultTolerations)
+
+			DeploymentPlacementSettings(dp, tc.spec)
+
+			podSpec := &dp.Spec.Template.Spec
+			if !reflect.DeepEqual(podSpec.NodeSelector, tc.expectedNodeSelector) {
+				t.Errorf("nodeSelector got=%v expected=%v", podSpec.NodeSelector, tc.expectedNodeSelector)
+			}
+			if !reflect.DeepEqual(podSpec.Affinity, tc.expectedAffinity) {
+				t.Errorf("affinity got=%v expected=%v", podSpec.Affinity, tc.expectedAffinity)
+			}
+			if !reflect.DeepEqual(podSpec.Tolerations, tc.expectedTolerations) {
+				t.Errorf("tolerations got=%v expected=%v", podSpec.Tolerations, tc.expectedTolerations)
+			}
+			if podSpec.PriorityClassName != tc.expectedPriority {
+				t.Errorf("priorityClassName got=%q expected=%q", podSpec.PriorityClassName, tc.expectedPriority)
+			}
+		})
+	}
+}
+
+func TestDeploymentResourcesSettings(t *testing.T) {
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+		},
+	}
+
+	dp := dpMinimal.DeepCopy()
+	DeploymentResourcesSettings(dp, nil)
+	if got := dp.Spec.Template.Spec.Containers[0].Resources; !reflect.DeepEqual(got, corev1.ResourceRequirements{}) {
+		t.Errorf("unexpected resources with no override: %v", got)
+	}
+
+	DeploymentResourcesSettings(dp, &resources)
+	if got := dp.Spec.Template.Spec.Containers[0].Resources; !reflect.DeepEqual(got, resources) {
+		t.Errorf("resources got=%v expected=%v", got, resources)
+	}
+}
+
+func TestDeploymentStatusServerSettings(t *testing.T) {
+	serveHTTP := nropv1.CacheResyncDebugServeHTTP
+	dumpJSONFile := nropv1.CacheResyncDebugDumpJSONFile
+
+	dp := dpMinimal.DeepCopy()
+	DeploymentStatusServerSettings(dp, nropv1.NUMAResourcesSchedulerSpec{CacheResyncDebug: &serveHTTP}, "quay.io/foo/operator:v1")
+	cnts := dp.Spec.Template.Spec.Containers
+	if len(cnts) != 2 {
+		t.Fatalf("expected the status server sidecar, got containers %s", toJSON(cnts))
+	}
+	if cnts[0].Name != "secondary-scheduler" {
+		t.Errorf("unexpected main container: %q", cnts[0].Name)
+	}
+	sidecar := cnts[1]
+	if sidecar.Name != PFPStatusServerContainerName || sidecar.Image != "quay.io/foo/operator:v1" {
+		t.Errorf("unexpected sidecar: %s", toJSON(sidecar))
+	}
+	if len(sidecar.Ports) != 1 || sidecar.Ports[0].ContainerPort != PFPStatusServerPort {
+		t.Errorf("unexpected sidecar ports: %s", toJSON(sidecar.Ports))
+	}
+	if len(sidecar.VolumeMounts) != 2 || sidecar.VolumeMounts[0].MountPath != PFPStatusDir || !sidecar.VolumeMounts[0].ReadOnly {
+		t.Errorf("unexpected sidecar volume mounts: %s", toJSON(sidecar.VolumeMounts))
+	}
+	if vols := dp.Spec.Template.Spec.Volumes; len(vols) != len(dpMinimal.Spec.Template.Spec.Volumes)+1 || vols[len(vols)-1].Secret == nil || vols[len(vols)-1].Secret.SecretName != PFPStatusSecretName {
+		t.Errorf("unexpected volumes: %s", toJSON(vols))
+	}
+
+	// must be idempotent
+	DeploymentStatusServerSettings(dp, nropv1.NUMAResourcesSchedulerSpec{CacheResyncDebug: &serveHTTP}, "quay.io/foo/operator:v1")
+	if len(dp.Spec.Template.Spec.Containers) != 2 {
+		t.Errorf("unexpected containers after update: %s", toJSON(dp.Spec.Template.Spec.Containers))
+	}
+	if len(dp.Spec.Template.Spec.Volumes) != len(dpMinimal.Spec.Template.Spec.Volumes)+1 {
+		t.Errorf("unexpected volumes after update: %s", toJSON(dp.Spec.Template.Spec.Volumes))
+	}
+
+	DeploymentStatusServerSettings(dp, nropv1.NUMAResourcesSchedulerSpec{CacheResyncDebug: &dumpJSONFile}, "quay.io/foo/operator:v1")
+	if !reflect.DeepEqual(*dp, *dpMinimal) {
+		t.Errorf("got=%s expected %s", toJSON(dp), toJSON(dpMinimal))
+	}
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package status
+
+import (
+	"errors"
+)
+
+// The condition reasons are machine-readable and part of the API: tools and alerts key on them,
+// so they must not change. The messages are meant for humans and can change at any time.
+
+// generic reasons
+const (
+	// ReasonInternalError is the fallback reason for the failures not otherwise classified
+	ReasonInternalError = "InternalError"
+	// ReasonAsExpected is the reason of the conditions reporting the expected state
+	ReasonAsExpected = "AsExpected"
+)
+
+// reasons for the NUMAResourcesOperator conditions
+const (
+	ReasonMachineConfigPoolUpdating         = "MachineConfigPoolUpdating"
+	ReasonMachineConfigSyncFailed           = "MachineConfigSyncFailed"
+	ReasonNodeResourceTopologyAPISyncFailed = "NodeResourceTopologyAPISyncFailed"
+	ReasonDaemonSetSyncFailed               = "DaemonSetSyncFailed"
+	ReasonDaemonSetNotReady                 = "DaemonSetNotReady"
+	ReasonRTEMetricsSyncFailed              = "RTEMetricsSyncFailed"
+)
+
+// reasons for the node group conditions
+const (
+	ReasonDaemonSetNotFound      = "DaemonSetNotFound"
+	ReasonDaemonSetRollingOut    = "DaemonSetRollingOut"
+	ReasonNodeTopologyMissing    = "NodeTopologyMissing"
+	ReasonNodeGroupStatusUnknown = "NodeGroupStatusUnknown"
+)
+
+// reasons for the NodeResourceTopologyStale condition
+const (
+	ReasonNodeTopologyStale    = "NodeTopologyStale"
+	ReasonNodeTopologyUpToDate = "NodeTopologyUpToDate"
+)
+
+// reasons for the events about the nodes excluded from NUMA-aware scheduling, besides ReasonNodeTopologyStale
+const (
+	ReasonNodeTopologyRecovered = "NodeTopologyRecovered"
+)
+
+// reasons for the NUMAResourcesScheduler conditions
+const (
+	ReasonSchedulerConfigRenderFailed = "SchedulerConfigRenderFailed"
+	ReasonSchedulerConfigOverlayError = "SchedulerConfigOverlayError"
+	ReasonSchedulerSyncFailed         = "SchedulerSyncFailed"
+	ReasonDeploymentNotReady          = "DeploymentNotReady"
+)
+
+// reasons for the Upgradeable condition, besides the ones of the Degraded condition which also block the upgrades
+const (
+	ReasonDeprecatedVersionStored = "DeprecatedVersionStored"
+	ReasonSchedulerNotAvailable   = "SchedulerNotAvailable"
+)
+
+// reasons for the CacheDesynced condition
+const (
+	ReasonCacheNodesDesynced = "NodesDesynced"
+	ReasonCacheNodesSynced   = "NodesSynced"
+)
+
+// reasons for the events about the KubeletConfig processing
+const (
+	ReasonKubeletConfigProcessed     = "ProcessOK"
+	ReasonKubeletConfigSkipped       = "ProcessSkip"
+	ReasonKubeletConfigProcessFailed = "ProcessFailed"
+	ReasonKubeletConfigMismatch      = "KubeletConfigMismatch"
+	ReasonKubeletConfigzUnreachable  = "KubeletConfigzUnreachable"
+)
+
+// reasons for the events about the objects managed by the NUMAResourcesOperator controller
+const (
+	ReasonMachineConfigCreated      = "MachineConfigCreated"
+	ReasonMachineConfigUpdated      = "MachineConfigUpdated"
+	ReasonMachineConfigDeleted      = "MachineConfigDeleted"
+	ReasonMachineConfigDeleteFailed = "MachineConfigDeleteFailed"
+	ReasonDaemonSetCreated          = "DaemonSetCreated"
+	ReasonDaemonSetUpdated          = "DaemonSetUpdated"
+	ReasonDaemonSetDeleted          = "DaemonSetDeleted"
+	ReasonDaemonSetDeleteFailed     = "DaemonSetDeleteFailed"
+)
+
+// reasons for the events about the objects managed by the NUMAResourcesScheduler controller
+const (
+	ReasonSchedulerConfigUpdated = "SchedulerConfigUpdated"
+)
+
+// ReasonedError is an error annotated with the reason to report in the conditions
+type ReasonedError struct {
+	Reason string
+	Err    error
+}
+
+func (e ReasonedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e ReasonedError) Unwrap() error {
+	return e.Err
+}
+
+// NewReasonedError annotates err with the given reason. Returns nil if err is nil.
+func NewReasonedError(reason string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return ReasonedError{
+		Reason: reason,
+		Err:    err,
+	}
+}
+
+// ReasonFromError returns the reason carried by err or by any error it wraps, or defaultReason if there is none.
+// If more errors in the chain carry a reason, the innermost, thus the most specific, wins.
+func ReasonFromError(err error, defaultReason string) string {
+	reason := defaultReason
+	for err != nil {
+		var rErr ReasonedError
+		if !errors.As(err, &rErr) {
+			break
+		}
+		reason = rErr.Reason
+		err = rErr.Err
+	}
+	return reason
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package status
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestReasonFromError(t *testing.T) {
+	baseErr := errors.New("fake failure")
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "nil error",
+			err:      nil,
+			expected: ReasonInternalError,
+		},
+		{
+			name:     "plain error",
+			err:      baseErr,
+			expected: ReasonInternalError,
+		},
+		{
+			name:     "reasoned error",
+			err:      NewReasonedError(ReasonDaemonSetSyncFailed, baseErr),
+			expected: ReasonDaemonSetSyncFailed,
+		},
+		{
+			name:     "wrapped reasoned error",
+			err:      fmt.Errorf("wrapped: %w", NewReasonedError(ReasonDaemonSetSyncFailed, baseErr)),
+			expected: ReasonDaemonSetSyncFailed,
+		},
+		{
+			name:     "nested reasoned errors",
+			err:      NewReasonedError(ReasonSchedulerSyncFailed, fmt.Errorf("wrapped: %w", NewReasonedError(ReasonSchedulerConfigOverlayError, baseErr))),
+			expected: ReasonSchedulerConfigOverlayError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ReasonFromError(tc.err, ReasonInternalError)
+			if got != tc.expected {
+				t.Errorf("got reason %q expected %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestReasonedErrorMessage(t *testing.T) {
+	baseErr := errors.New("fake failure")
+	err := NewReasonedError(ReasonMachineConfigSyncFailed, baseErr)
+	if err.Error() != baseErr.Error() {
+		t.Errorf("got message %q expected %q", err.Error(), baseErr.Error())
+	}
+	if !errors.Is(err, baseErr) {
+		t.Errorf("reasoned error does not wrap the original error")
+	}
+	if NewReasonedError(ReasonMachineConfigSyncFailed, nil) != nil {
+		t.Errorf("reasoned error from nil error is not nil")
+	}
+}
@@ -35,14 +35,64 @@ const (
 
 const (
 	ConditionTypeIncorrectNUMAResourcesOperatorResourceName = "IncorrectNUMAResourcesOperatorResourceName"
+	ConditionTypeNodeResourceTopologyStale                  = "NodeResourceTopologyStale"
+	ConditionTypeCacheDesynced                              = "CacheDesynced"
 )
 
-func GetUpdatedConditions(currentConditions []metav1.Condition, condition string, reason string, message string) ([]metav1.Condition, bool) {
+// GetUpdatedConditions computes the base conditions from the given condition, observed at the given object generation.
+// The object is reported as upgradeable only if available; use GetUpdatedConditionsWithUpgradeable
+// to report the upgradeability evaluated by the caller.
+// The base conditions whose status did not change keep their LastTransitionTime. Conditions not
+// belonging to the base set are carried over untouched from currentConditions.
+func GetUpdatedConditions(currentConditions []metav1.Condition, condition string, reason string, message string, generation int64) ([]metav1.Condition, bool) {
+	return updateConditions(currentConditions, NewConditions(condition, reason, message), generation)
+}
+
+// GetUpdatedConditionsWithUpgradeable is like GetUpdatedConditions, but reports the given Upgradeable condition,
+// see NewUpgradeableCondition.
+func GetUpdatedConditionsWithUpgradeable(currentConditions []metav1.Condition, condition string, reason string, message string, upgradeable metav1.Condition, generation int64) ([]metav1.Condition, bool) {
 	conditions := NewConditions(condition, reason, message)
+	cond := FindCondition(conditions, ConditionUpgradeable)
+	cond.Status = upgradeable.Status
+	cond.Reason = upgradeable.Reason
+	cond.Message = upgradeable.Message
+	return updateConditions(currentConditions, conditions, generation)
+}
+
+// NewUpgradeableCondition returns the Upgradeable condition. The object is upgradeable if reason is empty,
+// otherwise reason and message tell what blocks the upgrade.
+func NewUpgradeableCondition(reason, message string) metav1.Condition {
+	if reason == "" {
+		return metav1.Condition{
+			Type:   ConditionUpgradeable,
+			Status: metav1.ConditionTrue,
+			Reason: ReasonAsExpected,
+		}
+	}
+	return metav1.Condition{
+		Type:    ConditionUpgradeable,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+func updateConditions(currentConditions, conditions []metav1.Condition, generation int64) ([]metav1.Condition, bool) {
+	for idx := range conditions {
+		cond := &conditions[idx] // shortcut
+		cond.ObservedGeneration = generation
+		if curCond := FindCondition(currentConditions, cond.Type); curCond != nil && curCond.Status == cond.Status {
+			cond.LastTransitionTime = curCond.LastTransitionTime
+		}
+	}
+	for _, cond := range currentConditions {
+		if !isBaseCondition(cond.Type) {
+			conditions = append(conditions, cond)
+		}
+	}
 
 	options := []cmp.Option{
 		cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime"),
-		cmpopts.IgnoreFields(metav1.Condition{}, "ObservedGeneration"),
 	}
 
 	if cmp.Equal(conditions, currentConditions, options...) {
@@ -79,6 +129,14 @@ func NewConditions(condition string, reason string, message string) []metav1.Con
 	return conditions
 }
 
+func isBaseCondition(condition string) bool {
+	switch condition {
+	case ConditionAvailable, ConditionUpgradeable, ConditionProgressing, ConditionDegraded:
+		return true
+	}
+	return false
+}
+
 func newBaseConditions() []metav1.Condition {
 	now := time.Now()
 	return []metav1.Condition{
@@ -109,6 +167,48 @@ func newBaseConditions() []metav1.Condition {
 	}
 }
 
+// ConditionInfo describes the base condition to report, along with its reason and message
+type ConditionInfo struct {
+	Type    string
+	Reason  string
+	Message string
+}
+
+// Available returns the ConditionInfo reporting the Available condition
+func Available() ConditionInfo {
+	return ConditionInfo{
+		Type: ConditionAvailable,
+	}
+}
+
+// Progressing returns the ConditionInfo reporting the Progressing condition with the given reason and message
+func Progressing(reason, message string) ConditionInfo {
+	return ConditionInfo{
+		Type:    ConditionProgressing,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// Degraded returns the ConditionInfo reporting the Degraded condition with the given reason and message
+func Degraded(reason, message string) ConditionInfo {
+	return ConditionInfo{
+		Type:    ConditionDegraded,
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// DegradedFromError returns the ConditionInfo reporting the Degraded condition caused by err.
+// The reason is the one carried by err (see NewReasonedError), or defaultReason if err carries none.
+func DegradedFromError(err error, defaultReason string) ConditionInfo {
+	return ConditionInfo{
+		Type:    ConditionDegraded,
+		Reason:  ReasonFromError(err, defaultReason),
+		Message: err.Error(),
+	}
+}
+
 type ErrResourcesNotReady struct {
 	Message string
 }
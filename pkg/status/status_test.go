@@ -19,6 +19,7 @@ package status
 import (
 	"context"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -39,7 +40,7 @@ func TestUpdate(t *testing.T) {
 	nro := testobjs.NewNUMAResourcesOperator("test-nro", nil)
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(nro).Build()
 
-	nro.Status.Conditions, _ = GetUpdatedConditions(nro.Status.Conditions, ConditionProgressing, "testReason", "test message")
+	nro.Status.Conditions, _ = GetUpdatedConditions(nro.Status.Conditions, ConditionProgressing, "testReason", "test message", 1)
 	err = fakeClient.Update(context.TODO(), nro)
 	if err != nil {
 		t.Errorf("Update() failed with: %v", err)
@@ -67,14 +68,98 @@ func TestUpdateIfNeeded(t *testing.T) {
 	nro := testobjs.NewNUMAResourcesOperator("test-nro", nil)
 
 	var ok bool
-	nro.Status.Conditions, ok = GetUpdatedConditions(nro.Status.Conditions, ConditionAvailable, "", "")
+	nro.Status.Conditions, ok = GetUpdatedConditions(nro.Status.Conditions, ConditionAvailable, "", "", 1)
 	if !ok {
 		t.Errorf("Update did not change status, but it should")
 	}
 
 	// same status twice in a row. We should not overwrite identical status to save transactions.
-	_, ok = GetUpdatedConditions(nro.Status.Conditions, ConditionAvailable, "", "")
+	_, ok = GetUpdatedConditions(nro.Status.Conditions, ConditionAvailable, "", "", 1)
 	if ok {
 		t.Errorf("Update did change status, but it should not")
 	}
 }
+
+func TestUpdateKeepsExtraConditions(t *testing.T) {
+	conditions := NewConditions(ConditionAvailable, "", "")
+	conditions = append(conditions, metav1.Condition{
+		Type:   ConditionTypeNodeResourceTopologyStale,
+		Status: metav1.ConditionTrue,
+		Reason: ReasonNodeTopologyStale,
+	})
+
+	updated, ok := GetUpdatedConditions(conditions, ConditionAvailable, "", "", 0)
+	if ok {
+		t.Errorf("Update did change status, but it should not")
+	}
+	if len(updated) != len(conditions) {
+		t.Errorf("unexpected conditions count: expected %d got %d", len(conditions), len(updated))
+	}
+
+	updated, ok = GetUpdatedConditions(conditions, ConditionProgressing, "testReason", "test message", 0)
+	if !ok {
+		t.Errorf("Update did not change status, but it should")
+	}
+	stale := FindCondition(updated, ConditionTypeNodeResourceTopologyStale)
+	if stale == nil || stale.Status != metav1.ConditionTrue {
+		t.Errorf("Update lost the %q condition: %+v", ConditionTypeNodeResourceTopologyStale, updated)
+	}
+}
+
+func TestUpdateKeepsTransitionTime(t *testing.T) {
+	conditions, _ := GetUpdatedConditions(nil, ConditionProgressing, "testReason", "test message", 1)
+	past := metav1.NewTime(time.Now().Add(-time.Hour))
+	for idx := range conditions {
+		conditions[idx].LastTransitionTime = past
+	}
+
+	updated, ok := GetUpdatedConditions(conditions, ConditionAvailable, "", "", 1)
+	if !ok {
+		t.Errorf("Update did not change status, but it should")
+	}
+	cond := FindCondition(updated, ConditionDegraded)
+	if !cond.LastTransitionTime.Equal(&past) {
+		t.Errorf("condition %q changed transition time but its status did not change", ConditionDegraded)
+	}
+	for _, condType := range []string{ConditionAvailable, ConditionProgressing} {
+		cond := FindCondition(updated, condType)
+		if cond.LastTransitionTime.Equal(&past) {
+			t.Errorf("condition %q kept transition time but its status changed", condType)
+		}
+	}
+}
+
+func TestUpdateObservedGeneration(t *testing.T) {
+	conditions, _ := GetUpdatedConditions(nil, ConditionAvailable, "", "", 1)
+
+	updated, ok := GetUpdatedConditions(conditions, ConditionAvailable, "", "", 2)
+	if !ok {
+		t.Errorf("Update did not change status, but it should")
+	}
+	for _, cond := range updated {
+		if cond.ObservedGeneration != 2 {
+			t.Errorf("condition %q unexpected observed generation: expected 2 got %d", cond.Type, cond.ObservedGeneration)
+		}
+	}
+}
+
+func TestUpdateWithUpgradeable(t *testing.T) {
+	upgradeable := NewUpgradeableCondition(ReasonMachineConfigPoolUpdating, "test message")
+	conditions, ok := GetUpdatedConditionsWithUpgradeable(nil, ConditionAvailable, "", "", upgradeable, 1)
+	if !ok {
+		t.Errorf("Update did not change status, but it should")
+	}
+	cond := FindCondition(conditions, ConditionUpgradeable)
+	if cond.Status != metav1.ConditionFalse || cond.Reason != ReasonMachineConfigPoolUpdating {
+		t.Errorf("unexpected Upgradeable condition: %+v", cond)
+	}
+
+	conditions, ok = GetUpdatedConditionsWithUpgradeable(conditions, ConditionAvailable, "", "", NewUpgradeableCondition("", ""), 1)
+	if !ok {
+		t.Errorf("Update did not change status, but it should")
+	}
+	cond = FindCondition(conditions, ConditionUpgradeable)
+	if cond.Status != metav1.ConditionTrue || cond.Reason != ReasonAsExpected {
+		t.Errorf("unexpected Upgradeable condition: %+v", cond)
+	}
+}
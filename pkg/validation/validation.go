@@ -18,9 +18,17 @@ package validation
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/k8stopologyawareschedwg/deployer/pkg/deployer/platform"
 
 	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
 	nodegroupv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1/helper/nodegroup"
@@ -29,10 +37,13 @@ import (
 const (
 	// NodeGroupsError specifies the condition reason when node groups failed to pass validation
 	NodeGroupsError = "ValidationErrorUnderNodeGroups"
+	// SchedulerSpecError specifies the condition reason when the scheduler spec failed to pass validation
+	SchedulerSpecError = "ValidationErrorUnderSchedulerSpec"
+	// OperatorSpecError specifies the condition reason when the operator spec settings out of the node groups failed to pass validation
+	OperatorSpecError = "ValidationErrorUnderOperatorSpec"
 )
 
 // MachineConfigPoolDuplicates validates selected MCPs for duplicates
-// TODO: move it under the validation webhook once we will have one
 func MachineConfigPoolDuplicates(trees []nodegroupv1.Tree) error {
 	duplicates := map[string]int{}
 	for _, tree := range trees {
@@ -56,9 +67,13 @@ func MachineConfigPoolDuplicates(trees []nodegroupv1.Tree) error {
 }
 
 // NodeGroups validates the node groups for nil values and duplicates.
-// TODO: move it under the validation webhook once we will have one
+// The validating webhook runs it too, but the webhook is optional, so the controller must keep running it.
 func NodeGroups(nodeGroups []nropv1.NodeGroup) error {
-	if err := nodeGroupsMachineConfigPoolSelector(nodeGroups); err != nil {
+	if err := nodeGroupsSelector(nodeGroups); err != nil {
+		return err
+	}
+
+	if err := nodeGroupsNames(nodeGroups); err != nil {
 		return err
 	}
 
@@ -66,28 +81,284 @@ func NodeGroups(nodeGroups []nropv1.NodeGroup) error {
 		return err
 	}
 
-	if err := nodeGroupMachineConfigPoolSelector(nodeGroups); err != nil {
+	if err := nodeGroupSelectors(nodeGroups); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// TODO: move it under the validation webhook once we will have one
-func nodeGroupsMachineConfigPoolSelector(nodeGroups []nropv1.NodeGroup) error {
+// NodeGroupsPlatform validates the node groups can be used on the given platform.
+// MachineConfigPools are available only on OpenShift, so on other platforms
+// the node groups must be defined using NodeSelector.
+func NodeGroupsPlatform(nodeGroups []nropv1.NodeGroup, plat platform.Platform) error {
+	if plat == platform.OpenShift {
+		return nil
+	}
 	for _, nodeGroup := range nodeGroups {
-		if nodeGroup.MachineConfigPoolSelector == nil {
-			return fmt.Errorf("one of the node groups does not have machineConfigPoolSelector")
+		if nodeGroup.MachineConfigPoolSelector != nil {
+			return fmt.Errorf("the node group with the machineConfigPoolSelector %q is not supported on platform %q, use nodeSelector instead", nodeGroup.MachineConfigPoolSelector.String(), plat)
+		}
+	}
+	return nil
+}
+
+// RTEMetricsPlatform validates the RTE metrics can be served on the given platform.
+// The metrics server takes its certificate from the OpenShift service-ca, and the network policy
+// lets in the OpenShift monitoring stack, so they can't be used elsewhere.
+func RTEMetricsPlatform(mode nropv1.RTEMetricsMode, plat platform.Platform) error {
+	if plat == platform.OpenShift || mode != nropv1.RTEMetricsEnabled {
+		return nil
+	}
+	return fmt.Errorf("rteMetrics %q is not supported on platform %q", mode, plat)
+}
+
+// NodeSelectorGroups validates the node groups defined by NodeSelector against the other node groups:
+// their names must not clash with the selected MachineConfigPools, and the nodes they select must not
+// be selected by any other node group.
+func NodeSelectorGroups(trees []nodegroupv1.Tree, nodes []corev1.Node) error {
+	mcpNames := sets.New[string]()
+	for _, tree := range trees {
+		for _, mcp := range tree.MachineConfigPools {
+			mcpNames.Insert(mcp.Name)
+		}
+	}
+
+	type matchedPool struct {
+		name         string
+		nodeSelector bool
+	}
+	var pools []matchedPool
+	var selectors []labels.Selector
+	for _, tree := range trees {
+		isNodeSelector := tree.NodeGroup != nil && tree.NodeGroup.NodeSelector != nil
+		if isNodeSelector && mcpNames.Has(tree.NodeGroup.Name) {
+			return fmt.Errorf("the node group name %q clashes with the selected MachineConfigPool name", tree.NodeGroup.Name)
+		}
+		for _, pool := range tree.Pools() {
+			if pool.NodeSelector == nil {
+				continue
+			}
+			selector, err := metav1.LabelSelectorAsSelector(pool.NodeSelector)
+			if err != nil {
+				return err
+			}
+			pools = append(pools, matchedPool{name: pool.Name, nodeSelector: isNodeSelector})
+			selectors = append(selectors, selector)
+		}
+	}
+
+	var overlapErrors []string
+	for idx := range nodes {
+		node := &nodes[idx] // shortcut
+		nodeLabels := labels.Set(node.Labels)
+
+		var matched []string
+		involvesNodeSelector := false
+		for poolIdx, selector := range selectors {
+			if !selector.Matches(nodeLabels) {
+				continue
+			}
+			matched = append(matched, pools[poolIdx].name)
+			involvesNodeSelector = involvesNodeSelector || pools[poolIdx].nodeSelector
+		}
+		// overlapping MachineConfigPools are handled by the machine config operator
+		if len(matched) > 1 && involvesNodeSelector {
+			overlapErrors = append(overlapErrors, fmt.Sprintf("the node %q is selected by more than one node group: %s", node.Name, strings.Join(matched, ", ")))
+		}
+	}
+
+	if len(overlapErrors) > 0 {
+		return fmt.Errorf(strings.Join(overlapErrors, "; "))
+	}
+
+	return nil
+}
+
+// NodeGroupsSelectorsNotEmpty validates the node group selectors are not empty: an empty selector
+// matches all the MachineConfigPools or all the nodes, including the control plane ones.
+func NodeGroupsSelectorsNotEmpty(nodeGroups []nropv1.NodeGroup) error {
+	for _, nodeGroup := range nodeGroups {
+		if isEmptySelector(nodeGroup.MachineConfigPoolSelector) {
+			return fmt.Errorf("the node group %q has an empty machineConfigPoolSelector, which selects all the MachineConfigPools", nodeGroup.Name)
+		}
+		if isEmptySelector(nodeGroup.NodeSelector) {
+			return fmt.Errorf("the node group %q has an empty nodeSelector, which selects all the nodes", nodeGroup.Name)
+		}
+	}
+	return nil
+}
+
+// NodeGroupsConfig validates the config of every node group, see NodeGroupConfig.
+func NodeGroupsConfig(nodeGroups []nropv1.NodeGroup) error {
+	for idx := range nodeGroups {
+		if err := NodeGroupConfig(nodeGroups[idx].Config); err != nil {
+			return fmt.Errorf("nodeGroups[%d].config: %w", idx, err)
+		}
+	}
+	return nil
+}
+
+// NodeGroupConfig validates the settings of a node group which can't work together.
+// A nil config is valid, and means the defaults.
+func NodeGroupConfig(conf *nropv1.NodeGroupConfig) error {
+	if conf == nil {
+		return nil
+	}
+	if conf.InfoRefreshPeriod != nil {
+		if conf.InfoRefreshPeriod.Duration < 0 {
+			return fmt.Errorf("infoRefreshPeriod %v must not be negative", conf.InfoRefreshPeriod.Duration)
+		}
+		mode := nropv1.InfoRefreshPeriodic
+		if conf.InfoRefreshMode != nil {
+			mode = *conf.InfoRefreshMode
 		}
+		if mode == nropv1.InfoRefreshPeriodic && conf.InfoRefreshPeriod.Duration == 0 {
+			return fmt.Errorf("infoRefreshPeriod 0 disables the only refresh mechanism of the %s infoRefreshMode, use infoRefreshPause to stop the updates", mode)
+		}
+	}
+	if err := ResourceExcludes(conf.ResourceExcludes); err != nil {
+		return err
+	}
+	if err := PodExcludes(conf.PodExcludes); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ResourceExcludes validates the node name glob patterns of the resources to exclude.
+// The patterns are matched the same way the resource topology exporter does.
+func ResourceExcludes(resExcludes map[string][]string) error {
+	nodeNames := make([]string, 0, len(resExcludes))
+	for nodeName := range resExcludes {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		if nodeName == "" {
+			return fmt.Errorf("resourceExcludes: empty node name pattern")
+		}
+		if _, err := filepath.Match(nodeName, ""); err != nil {
+			return fmt.Errorf("resourceExcludes: malformed node name pattern %q: %w", nodeName, err)
+		}
+	}
+	return nil
+}
+
+// PodExcludes validates the namespace and name glob patterns of the pods to exclude.
+// The patterns are matched the same way the resource topology exporter does.
+func PodExcludes(podExcludes []nropv1.NamespacedName) error {
+	for idx, pe := range podExcludes {
+		if pe.Namespace == "" || pe.Name == "" {
+			return fmt.Errorf("podExcludes[%d]: both the namespace and the name patterns are required", idx)
+		}
+		if _, err := filepath.Match(pe.Namespace, ""); err != nil {
+			return fmt.Errorf("podExcludes[%d]: malformed namespace pattern %q: %w", idx, pe.Namespace, err)
+		}
+		if _, err := filepath.Match(pe.Name, ""); err != nil {
+			return fmt.Errorf("podExcludes[%d]: malformed name pattern %q: %w", idx, pe.Name, err)
+		}
+	}
+	return nil
+}
+
+// SchedulerScoringStrategies validates the scoring strategy of the main and of the additional scheduler profiles.
+// The validating webhook covers only NUMAResourcesOperator, so only the NUMAResourcesScheduler controller runs it.
+func SchedulerScoringStrategies(spec nropv1.NUMAResourcesSchedulerSpec) error {
+	if err := scoringStrategy(spec.ScoringStrategy); err != nil {
+		return err
+	}
+	for _, profile := range spec.Profiles {
+		if err := scoringStrategy(profile.ScoringStrategy); err != nil {
+			return fmt.Errorf("scheduler profile %q: %w", profile.SchedulerName, err)
+		}
+	}
+	return nil
+}
+
+// SchedulerPlatform validates the scheduler settings can be used on the given platform.
+// The cache state server takes its certificate from the OpenShift service-ca, so it can't run elsewhere.
+func SchedulerPlatform(spec nropv1.NUMAResourcesSchedulerSpec, plat platform.Platform) error {
+	if plat == platform.OpenShift {
+		return nil
+	}
+	if spec.CacheResyncDebug != nil && *spec.CacheResyncDebug == nropv1.CacheResyncDebugServeHTTP {
+		return fmt.Errorf("cacheResyncDebug %q is not supported on platform %q", nropv1.CacheResyncDebugServeHTTP, plat)
+	}
+	return nil
+}
+
+func scoringStrategy(params *nropv1.ScoringStrategyParams) error {
+	if params == nil {
+		return nil
+	}
+	if params.Type != nropv1.RequestedToCapacityRatio {
+		if params.RequestedToCapacityRatio != nil {
+			return fmt.Errorf("requestedToCapacityRatio is set but the scoring strategy is %q", params.Type)
+		}
+		return nil
+	}
+	if params.RequestedToCapacityRatio == nil || len(params.RequestedToCapacityRatio.Shape) == 0 {
+		return fmt.Errorf("the %s scoring strategy requires a shape", params.Type)
+	}
+	for idx, point := range params.RequestedToCapacityRatio.Shape {
+		if point.Utilization < 0 || point.Utilization > 100 {
+			return fmt.Errorf("shape point %d: utilization %d out of range [0, 100]", idx, point.Utilization)
+		}
+		if point.Score < 0 || point.Score > 10 {
+			return fmt.Errorf("shape point %d: score %d out of range [0, 10]", idx, point.Score)
+		}
+		if idx > 0 && point.Utilization <= params.RequestedToCapacityRatio.Shape[idx-1].Utilization {
+			return fmt.Errorf("shape point %d: utilization values must be increasing", idx)
+		}
+	}
+	return nil
+}
+
+func nodeGroupsSelector(nodeGroups []nropv1.NodeGroup) error {
+	for _, nodeGroup := range nodeGroups {
+		if nodeGroup.MachineConfigPoolSelector == nil && nodeGroup.NodeSelector == nil {
+			return fmt.Errorf("one of the node groups does not have machineConfigPoolSelector nor nodeSelector")
+		}
+		if nodeGroup.MachineConfigPoolSelector != nil && nodeGroup.NodeSelector != nil {
+			return fmt.Errorf("the node group %q has both machineConfigPoolSelector and nodeSelector", nodeGroup.Name)
+		}
+	}
+
+	return nil
+}
+
+func nodeGroupsNames(nodeGroups []nropv1.NodeGroup) error {
+	names := sets.New[string]()
+	for _, nodeGroup := range nodeGroups {
+		if nodeGroup.Name == "" {
+			if nodeGroup.NodeSelector != nil {
+				return fmt.Errorf("the node group with the nodeSelector %q does not have a name", nodeGroup.NodeSelector.String())
+			}
+			continue
+		}
+
+		if errs := k8svalidation.IsDNS1123Label(nodeGroup.Name); len(errs) > 0 {
+			return fmt.Errorf("the node group name %q is invalid: %s", nodeGroup.Name, strings.Join(errs, "; "))
+		}
+
+		if names.Has(nodeGroup.Name) {
+			return fmt.Errorf("the node group name %q has duplicates", nodeGroup.Name)
+		}
+		names.Insert(nodeGroup.Name)
 	}
 
 	return nil
 }
 
-// TODO: move it under the validation webhook once we will have one
 func nodeGroupsDuplicates(nodeGroups []nropv1.NodeGroup) error {
 	duplicates := map[string]int{}
+	nodeDuplicates := map[string]int{}
 	for _, nodeGroup := range nodeGroups {
+		if nodeGroup.NodeSelector != nil {
+			nodeDuplicates[nodeGroup.NodeSelector.String()] += 1
+		}
+
 		if nodeGroup.MachineConfigPoolSelector == nil {
 			continue
 		}
@@ -105,6 +376,11 @@ func nodeGroupsDuplicates(nodeGroups []nropv1.NodeGroup) error {
 			duplicateErrors = append(duplicateErrors, fmt.Sprintf("the node group with the machineConfigPoolSelector %q has duplicates", selector))
 		}
 	}
+	for selector, count := range nodeDuplicates {
+		if count > 1 {
+			duplicateErrors = append(duplicateErrors, fmt.Sprintf("the node group with the nodeSelector %q has duplicates", selector))
+		}
+	}
 
 	if len(duplicateErrors) > 0 {
 		return fmt.Errorf(strings.Join(duplicateErrors, "; "))
@@ -113,15 +389,18 @@ func nodeGroupsDuplicates(nodeGroups []nropv1.NodeGroup) error {
 	return nil
 }
 
-// TODO: move it under the validation webhook once we will have one
-func nodeGroupMachineConfigPoolSelector(nodeGroups []nropv1.NodeGroup) error {
+func nodeGroupSelectors(nodeGroups []nropv1.NodeGroup) error {
 	var selectorsErrors []string
 	for _, nodeGroup := range nodeGroups {
-		if nodeGroup.MachineConfigPoolSelector == nil {
+		selector := nodeGroup.MachineConfigPoolSelector
+		if selector == nil {
+			selector = nodeGroup.NodeSelector
+		}
+		if selector == nil {
 			continue
 		}
 
-		if _, err := metav1.LabelSelectorAsSelector(nodeGroup.MachineConfigPoolSelector); err != nil {
+		if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
 			selectorsErrors = append(selectorsErrors, err.Error())
 		}
 	}
@@ -132,3 +411,7 @@ func nodeGroupMachineConfigPoolSelector(nodeGroups []nropv1.NodeGroup) error {
 
 	return nil
 }
+
+func isEmptySelector(selector *metav1.LabelSelector) bool {
+	return selector != nil && len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0
+}
@@ -19,10 +19,14 @@ package validation
 import (
 	"strings"
 	"testing"
+	"time"
 
 	machineconfigv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/k8stopologyawareschedwg/deployer/pkg/deployer/platform"
+
 	nropv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1"
 	nodegroupv1 "github.com/openshift-kni/numaresources-operator/api/numaresourcesoperator/v1/helper/nodegroup"
 
@@ -148,6 +152,140 @@ func TestNodeGroupsSanity(t *testing.T) {
 			expectedError:        true,
 			expectedErrorMessage: "not a valid label selector operator",
 		},
+		{
+			name: "both MCP and node selector",
+			nodeGroups: []nropv1.NodeGroup{
+				{
+					Name: "ng1",
+					MachineConfigPoolSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "has both machineConfigPoolSelector and nodeSelector",
+		},
+		{
+			name: "node selector without name",
+			nodeGroups: []nropv1.NodeGroup{
+				{
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "does not have a name",
+		},
+		{
+			name: "invalid name",
+			nodeGroups: []nropv1.NodeGroup{
+				{
+					Name: "Bad_Name",
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "is invalid",
+		},
+		{
+			name: "duplicate names",
+			nodeGroups: []nropv1.NodeGroup{
+				{
+					Name: "ng1",
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+				},
+				{
+					Name: "ng1",
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test1": "test1",
+						},
+					},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "the node group name \"ng1\" has duplicates",
+		},
+		{
+			name: "duplicate node selectors",
+			nodeGroups: []nropv1.NodeGroup{
+				{
+					Name: "ng1",
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+				},
+				{
+					Name: "ng2",
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "nodeSelector",
+		},
+		{
+			name: "bad node selector",
+			nodeGroups: []nropv1.NodeGroup{
+				{
+					Name: "ng1",
+					NodeSelector: &metav1.LabelSelector{
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{
+								Key:      "test",
+								Operator: "bad-operator",
+								Values:   []string{"test"},
+							},
+						},
+					},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "not a valid label selector operator",
+		},
+		{
+			name: "correct values with node selectors",
+			nodeGroups: []nropv1.NodeGroup{
+				{
+					MachineConfigPoolSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test": "test",
+						},
+					},
+				},
+				{
+					Name: "ng1",
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test1": "test1",
+						},
+					},
+				},
+			},
+		},
 		{
 			name: "correct values",
 			nodeGroups: []nropv1.NodeGroup{
@@ -186,3 +324,608 @@ func TestNodeGroupsSanity(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeSelectorGroups(t *testing.T) {
+	mcp := testobjs.NewMachineConfigPool("worker-cnf", nil, nil, &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"node-role.kubernetes.io/worker-cnf": "",
+		},
+	})
+	mcpWorker := testobjs.NewMachineConfigPool("worker", nil, nil, &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"node-role.kubernetes.io/worker": "",
+		},
+	})
+
+	nodes := []corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-0",
+				Labels: map[string]string{
+					"node-role.kubernetes.io/worker":     "",
+					"node-role.kubernetes.io/worker-cnf": "",
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "node-1",
+				Labels: map[string]string{
+					"node-role.kubernetes.io/worker": "",
+					"numa":                           "aware",
+				},
+			},
+		},
+	}
+
+	nodeSelectorTree := func(name string, matchLabels map[string]string) nodegroupv1.Tree {
+		return nodegroupv1.Tree{
+			NodeGroup: &nropv1.NodeGroup{
+				Name: name,
+				NodeSelector: &metav1.LabelSelector{
+					MatchLabels: matchLabels,
+				},
+			},
+		}
+	}
+
+	type testCase struct {
+		name                 string
+		trees                []nodegroupv1.Tree
+		expectedErrorMessage string
+	}
+
+	testCases := []testCase{
+		{
+			name: "overlapping MCPs only",
+			trees: []nodegroupv1.Tree{
+				{MachineConfigPools: []*machineconfigv1.MachineConfigPool{mcp}},
+				{MachineConfigPools: []*machineconfigv1.MachineConfigPool{mcpWorker}},
+			},
+		},
+		{
+			name: "no overlap",
+			trees: []nodegroupv1.Tree{
+				{MachineConfigPools: []*machineconfigv1.MachineConfigPool{mcp}},
+				nodeSelectorTree("ng-numa", map[string]string{"numa": "aware"}),
+			},
+		},
+		{
+			name: "node selector overlapping MCP",
+			trees: []nodegroupv1.Tree{
+				{MachineConfigPools: []*machineconfigv1.MachineConfigPool{mcpWorker}},
+				nodeSelectorTree("ng-numa", map[string]string{"numa": "aware"}),
+			},
+			expectedErrorMessage: "the node \"node-1\" is selected by more than one node group: worker, ng-numa",
+		},
+		{
+			name: "node selectors overlapping",
+			trees: []nodegroupv1.Tree{
+				nodeSelectorTree("ng-numa", map[string]string{"numa": "aware"}),
+				nodeSelectorTree("ng-worker", map[string]string{"node-role.kubernetes.io/worker": ""}),
+			},
+			expectedErrorMessage: "is selected by more than one node group",
+		},
+		{
+			name: "name clashing with MCP",
+			trees: []nodegroupv1.Tree{
+				{MachineConfigPools: []*machineconfigv1.MachineConfigPool{mcp}},
+				nodeSelectorTree("worker-cnf", map[string]string{"numa": "aware"}),
+			},
+			expectedErrorMessage: "clashes with the selected MachineConfigPool name",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NodeSelectorGroups(tc.trees, nodes)
+			if tc.expectedErrorMessage == "" {
+				if err != nil {
+					t.Errorf("expected success, failed: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected error, succeeded")
+			}
+			if !strings.Contains(err.Error(), tc.expectedErrorMessage) {
+				t.Errorf("unexpected error: %v (expected %q)", err, tc.expectedErrorMessage)
+			}
+		})
+	}
+}
+
+func TestNodeGroupsPlatform(t *testing.T) {
+	mcpNodeGroup := nropv1.NodeGroup{
+		MachineConfigPoolSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"test": "test",
+			},
+		},
+	}
+	nodeSelectorNodeGroup := nropv1.NodeGroup{
+		Name: "ng1",
+		NodeSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"test": "test",
+			},
+		},
+	}
+
+	type testCase struct {
+		name          string
+		plat          platform.Platform
+		nodeGroups    []nropv1.NodeGroup
+		expectedError bool
+	}
+
+	testCases := []testCase{
+		{
+			name:       "openshift with MCP selector",
+			plat:       platform.OpenShift,
+			nodeGroups: []nropv1.NodeGroup{mcpNodeGroup},
+		},
+		{
+			name:       "openshift with node selector",
+			plat:       platform.OpenShift,
+			nodeGroups: []nropv1.NodeGroup{nodeSelectorNodeGroup},
+		},
+		{
+			name:          "kubernetes with MCP selector",
+			plat:          platform.Kubernetes,
+			nodeGroups:    []nropv1.NodeGroup{nodeSelectorNodeGroup, mcpNodeGroup},
+			expectedError: true,
+		},
+		{
+			name:       "kubernetes with node selector",
+			plat:       platform.Kubernetes,
+			nodeGroups: []nropv1.NodeGroup{nodeSelectorNodeGroup},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NodeGroupsPlatform(tc.nodeGroups, tc.plat)
+			if err == nil && tc.expectedError {
+				t.Errorf("expected error, succeeded")
+			}
+			if err != nil && !tc.expectedError {
+				t.Errorf("expected success, failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestRTEMetricsPlatform(t *testing.T) {
+	testCases := []struct {
+		name          string
+		plat          platform.Platform
+		mode          nropv1.RTEMetricsMode
+		expectedError bool
+	}{
+		{
+			name: "openshift with defaults",
+			plat: platform.OpenShift,
+		},
+		{
+			name: "openshift with metrics enabled",
+			plat: platform.OpenShift,
+			mode: nropv1.RTEMetricsEnabled,
+		},
+		{
+			name: "kubernetes with metrics disabled",
+			plat: platform.Kubernetes,
+			mode: nropv1.RTEMetricsDisabled,
+		},
+		{
+			name:          "kubernetes with metrics enabled",
+			plat:          platform.Kubernetes,
+			mode:          nropv1.RTEMetricsEnabled,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := RTEMetricsPlatform(tc.mode, tc.plat)
+			if err == nil && tc.expectedError {
+				t.Errorf("expected error, succeeded")
+			}
+			if err != nil && !tc.expectedError {
+				t.Errorf("expected success, failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestSchedulerPlatform(t *testing.T) {
+	debugServeHTTP := nropv1.CacheResyncDebugServeHTTP
+	debugDumpJSONFile := nropv1.CacheResyncDebugDumpJSONFile
+
+	testCases := []struct {
+		name          string
+		plat          platform.Platform
+		spec          nropv1.NUMAResourcesSchedulerSpec
+		expectedError bool
+	}{
+		{
+			name: "openshift with defaults",
+			plat: platform.OpenShift,
+		},
+		{
+			name: "openshift serving the cache state over HTTP",
+			plat: platform.OpenShift,
+			spec: nropv1.NUMAResourcesSchedulerSpec{
+				CacheResyncDebug: &debugServeHTTP,
+			},
+		},
+		{
+			name: "kubernetes with defaults",
+			plat: platform.Kubernetes,
+		},
+		{
+			name: "kubernetes dumping the cache state on files",
+			plat: platform.Kubernetes,
+			spec: nropv1.NUMAResourcesSchedulerSpec{
+				CacheResyncDebug: &debugDumpJSONFile,
+			},
+		},
+		{
+			name: "kubernetes serving the cache state over HTTP",
+			plat: platform.Kubernetes,
+			spec: nropv1.NUMAResourcesSchedulerSpec{
+				CacheResyncDebug: &debugServeHTTP,
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := SchedulerPlatform(tc.spec, tc.plat)
+			if err == nil && tc.expectedError {
+				t.Errorf("expected error, succeeded")
+			}
+			if err != nil && !tc.expectedError {
+				t.Errorf("expected success, failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestSchedulerScoringStrategies(t *testing.T) {
+	shape := []nropv1.UtilizationShapePoint{
+		{Utilization: 0, Score: 10},
+		{Utilization: 100, Score: 0},
+	}
+
+	testCases := []struct {
+		name                 string
+		spec                 nropv1.NUMAResourcesSchedulerSpec
+		expectedError        bool
+		expectedErrorMessage string
+	}{
+		{
+			name: "default",
+			spec: nropv1.NUMAResourcesSchedulerSpec{},
+		},
+		{
+			name: "requested to capacity ratio",
+			spec: nropv1.NUMAResourcesSchedulerSpec{
+				ScoringStrategy: &nropv1.ScoringStrategyParams{
+					Type: nropv1.RequestedToCapacityRatio,
+					RequestedToCapacityRatio: &nropv1.RequestedToCapacityRatioParams{
+						Shape: shape,
+					},
+				},
+			},
+		},
+		{
+			name: "requested to capacity ratio without shape",
+			spec: nropv1.NUMAResourcesSchedulerSpec{
+				ScoringStrategy: &nropv1.ScoringStrategyParams{
+					Type: nropv1.RequestedToCapacityRatio,
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "requires a shape",
+		},
+		{
+			name: "shape with another strategy",
+			spec: nropv1.NUMAResourcesSchedulerSpec{
+				ScoringStrategy: &nropv1.ScoringStrategyParams{
+					Type: nropv1.MostAllocated,
+					RequestedToCapacityRatio: &nropv1.RequestedToCapacityRatioParams{
+						Shape: shape,
+					},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "requestedToCapacityRatio is set",
+		},
+		{
+			name: "shape not sorted",
+			spec: nropv1.NUMAResourcesSchedulerSpec{
+				ScoringStrategy: &nropv1.ScoringStrategyParams{
+					Type: nropv1.RequestedToCapacityRatio,
+					RequestedToCapacityRatio: &nropv1.RequestedToCapacityRatioParams{
+						Shape: []nropv1.UtilizationShapePoint{
+							{Utilization: 50, Score: 10},
+							{Utilization: 50, Score: 0},
+						},
+					},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "must be increasing",
+		},
+		{
+			name: "score out of range",
+			spec: nropv1.NUMAResourcesSchedulerSpec{
+				ScoringStrategy: &nropv1.ScoringStrategyParams{
+					Type: nropv1.RequestedToCapacityRatio,
+					RequestedToCapacityRatio: &nropv1.RequestedToCapacityRatioParams{
+						Shape: []nropv1.UtilizationShapePoint{
+							{Utilization: 0, Score: 100},
+						},
+					},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "out of range",
+		},
+		{
+			name: "invalid additional profile",
+			spec: nropv1.NUMAResourcesSchedulerSpec{
+				Profiles: []nropv1.SchedulerProfile{
+					{
+						SchedulerName: "test-sched",
+						ScoringStrategy: &nropv1.ScoringStrategyParams{
+							Type: nropv1.RequestedToCapacityRatio,
+						},
+					},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "scheduler profile \"test-sched\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := SchedulerScoringStrategies(tc.spec)
+			if err == nil && tc.expectedError {
+				t.Errorf("expected error, succeeded")
+			}
+			if err != nil && !tc.expectedError {
+				t.Errorf("expected success, failed: %v", err)
+			}
+			if tc.expectedError && err != nil && !strings.Contains(err.Error(), tc.expectedErrorMessage) {
+				t.Errorf("unexpected error message: got=%q expected to contain=%q", err.Error(), tc.expectedErrorMessage)
+			}
+		})
+	}
+}
+
+func TestNodeGroupsSelectorsNotEmpty(t *testing.T) {
+	testCases := []struct {
+		name          string
+		nodeGroups    []nropv1.NodeGroup
+		expectedError bool
+	}{
+		{
+			name: "non empty selectors",
+			nodeGroups: []nropv1.NodeGroup{
+				{MachineConfigPoolSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"test": "test"}}},
+				{Name: "ng1", NodeSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "test", Operator: metav1.LabelSelectorOpExists},
+					},
+				}},
+			},
+		},
+		{
+			name: "empty machine config pool selector",
+			nodeGroups: []nropv1.NodeGroup{
+				{MachineConfigPoolSelector: &metav1.LabelSelector{}},
+			},
+			expectedError: true,
+		},
+		{
+			name: "empty node selector",
+			nodeGroups: []nropv1.NodeGroup{
+				{Name: "ng1", NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{}}},
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NodeGroupsSelectorsNotEmpty(tc.nodeGroups)
+			if err == nil && tc.expectedError {
+				t.Errorf("expected error, succeeded")
+			}
+			if err != nil && !tc.expectedError {
+				t.Errorf("expected success, failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestNodeGroupConfig(t *testing.T) {
+	periodic := nropv1.InfoRefreshPeriodic
+	events := nropv1.InfoRefreshEvents
+	periodicAndEvents := nropv1.InfoRefreshPeriodicAndEvents
+
+	testCases := []struct {
+		name                 string
+		conf                 *nropv1.NodeGroupConfig
+		expectedError        bool
+		expectedErrorMessage string
+	}{
+		{
+			name: "nil config",
+		},
+		{
+			name: "defaults",
+			conf: &nropv1.NodeGroupConfig{},
+		},
+		{
+			name: "periodic with period",
+			conf: &nropv1.NodeGroupConfig{
+				InfoRefreshMode:   &periodic,
+				InfoRefreshPeriod: &metav1.Duration{Duration: 5 * time.Second},
+			},
+		},
+		{
+			name: "periodic with zero period",
+			conf: &nropv1.NodeGroupConfig{
+				InfoRefreshMode:   &periodic,
+				InfoRefreshPeriod: &metav1.Duration{},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "infoRefreshPeriod 0",
+		},
+		{
+			name: "default mode with zero period",
+			conf: &nropv1.NodeGroupConfig{
+				InfoRefreshPeriod: &metav1.Duration{},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "infoRefreshPeriod 0",
+		},
+		{
+			name: "events with zero period",
+			conf: &nropv1.NodeGroupConfig{
+				InfoRefreshMode:   &events,
+				InfoRefreshPeriod: &metav1.Duration{},
+			},
+		},
+		{
+			name: "periodic and events with zero period",
+			conf: &nropv1.NodeGroupConfig{
+				InfoRefreshMode:   &periodicAndEvents,
+				InfoRefreshPeriod: &metav1.Duration{},
+			},
+		},
+		{
+			name: "negative period",
+			conf: &nropv1.NodeGroupConfig{
+				InfoRefreshMode:   &periodicAndEvents,
+				InfoRefreshPeriod: &metav1.Duration{Duration: -1 * time.Second},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "must not be negative",
+		},
+		{
+			name: "valid pod excludes",
+			conf: &nropv1.NodeGroupConfig{
+				PodExcludes: []nropv1.NamespacedName{
+					{Namespace: "kube-*", Name: "*"},
+					{Namespace: "openshift-?", Name: "pod-[0-9]"},
+				},
+			},
+		},
+		{
+			name: "malformed pod excludes name",
+			conf: &nropv1.NodeGroupConfig{
+				PodExcludes: []nropv1.NamespacedName{
+					{Namespace: "kube-*", Name: "*"},
+					{Namespace: "test", Name: "pod-[0-9"},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "podExcludes[1]: malformed name pattern",
+		},
+		{
+			name: "missing pod excludes namespace",
+			conf: &nropv1.NodeGroupConfig{
+				PodExcludes: []nropv1.NamespacedName{
+					{Name: "*"},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "both the namespace and the name patterns are required",
+		},
+		{
+			name: "valid resource excludes",
+			conf: &nropv1.NodeGroupConfig{
+				ResourceExcludes: map[string][]string{
+					"*":                    {"hugepages-2Mi"},
+					"worker-0.example.com": {"memory"},
+				},
+			},
+		},
+		{
+			name: "glob resource excludes",
+			conf: &nropv1.NodeGroupConfig{
+				ResourceExcludes: map[string][]string{
+					"worker-*": {"memory"},
+				},
+			},
+		},
+		{
+			name: "malformed resource excludes",
+			conf: &nropv1.NodeGroupConfig{
+				ResourceExcludes: map[string][]string{
+					"worker-[": {"memory"},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: `malformed node name pattern "worker-["`,
+		},
+		{
+			name: "empty resource excludes node name",
+			conf: &nropv1.NodeGroupConfig{
+				ResourceExcludes: map[string][]string{
+					"": {"memory"},
+				},
+			},
+			expectedError:        true,
+			expectedErrorMessage: "empty node name pattern",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NodeGroupConfig(tc.conf)
+			if err == nil && tc.expectedError {
+				t.Fatalf("expected error, succeeded")
+			}
+			if err != nil && !tc.expectedError {
+				t.Fatalf("expected success, failed: %v", err)
+			}
+			if tc.expectedErrorMessage != "" && !strings.Contains(err.Error(), tc.expectedErrorMessage) {
+				t.Errorf("unexpected error message: %v", err)
+			}
+		})
+	}
+}
+
+func TestNodeGroupsConfig(t *testing.T) {
+	nodeGroups := []nropv1.NodeGroup{
+		{
+			Name: "ng-valid",
+		},
+		{
+			Name: "ng-malformed",
+			Config: &nropv1.NodeGroupConfig{
+				PodExcludes: []nropv1.NamespacedName{
+					{Namespace: "openshift-*"},
+				},
+			},
+		},
+	}
+
+	if err := NodeGroupsConfig(nodeGroups[:1]); err != nil {
+		t.Errorf("expected success, failed: %v", err)
+	}
+	err := NodeGroupsConfig(nodeGroups)
+	if err == nil {
+		t.Fatalf("expected error, succeeded")
+	}
+	if !strings.Contains(err.Error(), "nodeGroups[1].config") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
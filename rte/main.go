@@ -15,30 +15,37 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"runtime"
-	"sort"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 
 	"github.com/k8stopologyawareschedwg/podfingerprint"
 	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/k8shelpers"
+	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/kubeconf"
 	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/metrics"
 	metricssrv "github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/metrics/server"
+	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/notification"
 	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/nrtupdater"
+	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/podreadiness"
 	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/podres"
 	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/podres/middleware/podexclude"
 	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/podres/middleware/sharedcpuspool"
+	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/ratelimiter"
 	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/resourcemonitor"
 	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/resourcetopologyexporter"
 
 	"github.com/openshift-kni/numaresources-operator/pkg/version"
 
 	"github.com/openshift-kni/numaresources-operator/rte/pkg/config"
+	"github.com/openshift-kni/numaresources-operator/rte/pkg/heartbeat"
+	rtemetrics "github.com/openshift-kni/numaresources-operator/rte/pkg/metrics"
 )
 
 const (
@@ -49,8 +56,10 @@ const (
 )
 
 type localArgs struct {
-	ConfigPath  string
-	PodExcludes podexclude.List
+	ConfigPath       string
+	PodExcludes      podexclude.List
+	MetricsTLS       rtemetrics.TLSConfig
+	ExposeLastUpdate bool
 }
 
 type ProgArgs struct {
@@ -102,7 +111,7 @@ func main() {
 	if err != nil {
 		klog.Fatalf("failed to setup metrics: %v", err)
 	}
-	err = metricssrv.Setup(parsedArgs.RTE.MetricsMode, metricssrv.NewDefaultConfig())
+	err = rtemetrics.Setup(parsedArgs.RTE.MetricsMode, metricssrv.NewDefaultConfig(), parsedArgs.LocalArgs.MetricsTLS)
 	if err != nil {
 		klog.Fatalf("failed to setup metrics server: %v", err)
 	}
@@ -113,11 +122,103 @@ func main() {
 		PodResCli: cli,
 		K8SCli:    k8scli,
 	}
-	err = resourcetopologyexporter.Execute(hnd, parsedArgs.NRTupdater, parsedArgs.Resourcemonitor, parsedArgs.RTE)
+	err = execute(hnd, parsedArgs)
 	// must never execute; if it does, we want to know
 	klog.Fatalf("failed to execute: %v", err)
 }
 
+// execute runs the same pipeline as resourcetopologyexporter.Execute, which offers no way to stamp the updates.
+func execute(hnd resourcemonitor.Handle, pArgs ProgArgs) error {
+	rteArgs := pArgs.RTE
+	tmConf, err := getTopologyManagerConfig(rteArgs)
+	if err != nil {
+		return err
+	}
+
+	var nodeGetter nrtupdater.NodeGetter
+	if rteArgs.AddNRTOwnerEnable {
+		nodeGetter, err = nrtupdater.NewCachedNodeGetter(hnd.K8SCli, context.Background())
+		if err != nil {
+			return fmt.Errorf("cannot enable 'add-nrt-owner': %w", err)
+		}
+	} else {
+		nodeGetter = &nrtupdater.DisabledNodeGetter{}
+	}
+
+	var condChan chan v1.PodCondition
+	if rteArgs.PodReadinessEnable {
+		condChan = make(chan v1.PodCondition)
+		condIn, err := podreadiness.NewConditionInjector(hnd.K8SCli)
+		if err != nil {
+			return err
+		}
+		condIn.Run(condChan)
+	}
+
+	eventSource, err := createEventSource(rteArgs)
+	if err != nil {
+		return err
+	}
+
+	resObs, err := resourcetopologyexporter.NewResourceObserver(hnd, pArgs.Resourcemonitor)
+	if err != nil {
+		return err
+	}
+	go resObs.Run(eventSource.Events(), condChan)
+
+	infos := resObs.Infos
+	if pArgs.LocalArgs.ExposeLastUpdate {
+		infos = heartbeat.Stamp(infos, time.Now)
+	}
+	upd := nrtupdater.NewNRTUpdater(nodeGetter, pArgs.NRTupdater, tmConf)
+	go upd.Run(infos, condChan)
+
+	go eventSource.Run()
+
+	eventSource.Wait()  // will never return
+	eventSource.Close() // still we try to clean after ourselves :)
+	return nil          // unreachable
+}
+
+func createEventSource(rteArgs resourcetopologyexporter.Args) (notification.EventSource, error) {
+	eventSource, err := notification.NewUnlimitedEventSource()
+	if err != nil {
+		return nil, err
+	}
+	if err := eventSource.SetInterval(rteArgs.SleepInterval); err != nil {
+		return nil, err
+	}
+	if err := eventSource.AddFile(rteArgs.NotifyFilePath); err != nil {
+		return nil, err
+	}
+	if rteArgs.MaxEventsPerTimeUnit > 0 && rteArgs.TimeUnitToLimitEvents > 0 {
+		return ratelimiter.NewRateLimitedEventSource(eventSource, uint64(rteArgs.MaxEventsPerTimeUnit), rteArgs.TimeUnitToLimitEvents)
+	}
+	return eventSource, nil
+}
+
+func getTopologyManagerConfig(rteArgs resourcetopologyexporter.Args) (nrtupdater.TMConfig, error) {
+	if rteArgs.TopologyManagerPolicy != "" && rteArgs.TopologyManagerScope != "" {
+		klog.Infof("using given Topology Manager policy %q scope %q", rteArgs.TopologyManagerPolicy, rteArgs.TopologyManagerScope)
+		return nrtupdater.TMConfig{
+			Policy: rteArgs.TopologyManagerPolicy,
+			Scope:  rteArgs.TopologyManagerScope,
+		}, nil
+	}
+	if rteArgs.KubeletConfigFile != "" {
+		klConfig, err := kubeconf.GetKubeletConfigFromLocalFile(rteArgs.KubeletConfigFile)
+		if err != nil {
+			return nrtupdater.TMConfig{}, fmt.Errorf("error getting topology Manager Policy: %w", err)
+		}
+		klog.Infof("using detected Topology Manager policy %q scope %q", klConfig.TopologyManagerPolicy, klConfig.TopologyManagerScope)
+		return nrtupdater.TMConfig{
+			Policy: klConfig.TopologyManagerPolicy,
+			Scope:  klConfig.TopologyManagerScope,
+		}, nil
+	}
+	return nrtupdater.TMConfig{}, fmt.Errorf("cannot find the kubelet Topology Manager policy")
+}
+
 // The args is passed only for testing purposes.
 func parseArgs(args ...string) (ProgArgs, error) {
 	pArgs := ProgArgs{}
@@ -137,6 +238,7 @@ func parseArgs(args ...string) (ProgArgs, error) {
 	flags.StringVar(&pArgs.Resourcemonitor.SysfsRoot, "sysfs", "/sys", "Top-level component path of sysfs.")
 	flags.BoolVar(&pArgs.Resourcemonitor.PodSetFingerprint, "pods-fingerprint", false, "If enable, compute and report the pod set fingerprint.")
 	flags.BoolVar(&pArgs.Resourcemonitor.ExposeTiming, "expose-timing", false, "If enable, expose expected and actual sleep interval as annotations.")
+	flags.BoolVar(&pArgs.LocalArgs.ExposeLastUpdate, "expose-last-update", false, "If enable, expose the time of the last update as annotation.")
 	flags.BoolVar(&pArgs.Resourcemonitor.RefreshNodeResources, "refresh-node-resources", false, "If enable, track changes in node's resources")
 	flags.StringVar(&pArgs.Resourcemonitor.PodSetFingerprintStatusFile, "pods-fingerprint-status-file", "", "File to dump the pods fingerprint status. Use \"\" to disable.")
 	flags.StringVar(&pfpMethod, "pods-fingerprint-method", podfingerprint.MethodAll, fmt.Sprintf("Select the method to compute the pods fingerprint. Valid options: %s.", resourcemonitor.PFPMethodSupported()))
@@ -151,7 +253,9 @@ func parseArgs(args ...string) (ProgArgs, error) {
 	flags.StringVar(&pArgs.RTE.PodResourcesSocketPath, "podresources-socket", "unix:///podresources/kubelet.sock", "Pod Resource Socket path to use.")
 	flags.BoolVar(&pArgs.RTE.PodReadinessEnable, "podreadiness", true, "Custom condition injection using Podreadiness.")
 	flags.BoolVar(&pArgs.RTE.AddNRTOwnerEnable, "add-nrt-owner", true, "RTE will inject NRT's related node as OwnerReference to ensure cleanup if the node is deleted.")
-	flags.StringVar(&metricsMode, "metrics-mode", metricssrv.ServingDisabled, fmt.Sprintf("Select the mode to expose metrics endpoint. Valid options: %s", metricssrv.ServingModeSupported()))
+	flags.StringVar(&metricsMode, "metrics-mode", metricssrv.ServingDisabled, fmt.Sprintf("Select the mode to expose metrics endpoint. Valid options: %s", rtemetrics.ServingModeSupported()))
+	flags.StringVar(&pArgs.LocalArgs.MetricsTLS.CertFile, "metrics-tls-cert-file", rtemetrics.DefaultCertFile, "Certificate file path to serve the metrics endpoint over TLS.")
+	flags.StringVar(&pArgs.LocalArgs.MetricsTLS.KeyFile, "metrics-tls-key-file", rtemetrics.DefaultKeyFile, "Private key file path to serve the metrics endpoint over TLS.")
 
 	refCnt := flags.String("reference-container", "", "Reference container, used to learn about the shared cpu pool\n See: https://github.com/kubernetes/kubernetes/issues/102190\n format of spec is namespace/podname/containername.\n Alternatively, you can use the env vars REFERENCE_NAMESPACE, REFERENCE_POD_NAME, REFERENCE_CONTAINER_NAME.")
 
@@ -182,7 +286,7 @@ func parseArgs(args ...string) (ProgArgs, error) {
 		pArgs.RTE.ReferenceContainer = sharedcpuspool.ContainerIdentFromEnv()
 	}
 
-	pArgs.RTE.MetricsMode, err = metricssrv.ServingModeIsSupported(metricsMode)
+	pArgs.RTE.MetricsMode, err = rtemetrics.ServingModeIsSupported(metricsMode)
 	if err != nil {
 		return pArgs, err
 	}
@@ -196,12 +300,12 @@ func parseArgs(args ...string) (ProgArgs, error) {
 	if err != nil {
 		return pArgs, fmt.Errorf("error getting exclude list from the configuration: %v", err)
 	}
-	if len(conf.ExcludeList) != 0 {
-		pArgs.Resourcemonitor.ResourceExclude = conf.ExcludeList
+	if excludeList := conf.GetExcludeList(pArgs.NRTupdater.Hostname); len(excludeList) != 0 {
+		pArgs.Resourcemonitor.ResourceExclude = excludeList
 		klog.V(2).Infof("using exclude list:\n%s", pArgs.Resourcemonitor.ResourceExclude.String())
 	}
 
-	pArgs.LocalArgs.PodExcludes = makePodExcludeList(conf.PodExcludes)
+	pArgs.LocalArgs.PodExcludes = makePodExcludeList(conf.GetPodExcludes())
 
 	err = setupTopologyManagerConfig(&pArgs, conf)
 	if err != nil {
@@ -211,19 +315,12 @@ func parseArgs(args ...string) (ProgArgs, error) {
 	return pArgs, nil
 }
 
-func makePodExcludeList(excludes map[string]string) podexclude.List {
-	keys := []string{}
-	for key := range excludes {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-
+func makePodExcludeList(excludes config.PodExcludeList) podexclude.List {
 	exList := podexclude.List{}
-	for _, key := range keys {
-		val := excludes[key]
+	for _, ex := range excludes {
 		exList = append(exList, podexclude.Item{
-			NamespacePattern: key,
-			NamePattern:      val,
+			NamespacePattern: ex.NamespacePattern,
+			NamePattern:      ex.NamePattern,
 		})
 	}
 	return exList
@@ -20,6 +20,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,13 +38,94 @@ const (
 	LabelNodeGroupName string = "nodegroup.nodetopology.openshift.io"
 
 	LabelNodeGroupKindMachineConfigPool string = "machineconfigpool"
+	LabelNodeGroupKindNodeGroup         string = "nodegroup"
 )
 
 type Config struct {
 	ExcludeList           map[string][]string `json:"excludeList,omitempty"`
 	TopologyManagerPolicy string              `json:"topologyManagerPolicy,omitempty"`
 	TopologyManagerScope  string              `json:"topologyManagerScope,omitempty"`
-	PodExcludes           map[string]string   `json:"podExcludes"`
+	// PodExcludes is the legacy namespace->name map, still written for the RTEs which don't know PodExcludeList
+	PodExcludes    map[string]string `json:"podExcludes"`
+	PodExcludeList PodExcludeList    `json:"podExcludeList,omitempty"`
+}
+
+// PodExclude is a namespace/name glob patterns pair matching the pods to ignore
+type PodExclude struct {
+	NamespacePattern string `json:"namespacePattern"`
+	NamePattern      string `json:"namePattern"`
+}
+
+// PodExcludeList is the ordered list of pods to ignore
+type PodExcludeList []PodExclude
+
+// legacy returns the legacy namespace->name map holding the same pods to ignore,
+// or false if the map can't hold them because a namespace pattern has more than one name pattern.
+func (pel PodExcludeList) legacy() (map[string]string, bool) {
+	ret := make(map[string]string, len(pel))
+	for _, item := range pel {
+		if _, ok := ret[item.NamespacePattern]; ok {
+			return nil, false
+		}
+		ret[item.NamespacePattern] = item.NamePattern
+	}
+	return ret, true
+}
+
+// GetPodExcludes returns the pods to ignore, falling back to the legacy map if the list is not set.
+// Legacy entries are sorted by namespace pattern to get a stable ordering.
+func (conf Config) GetPodExcludes() PodExcludeList {
+	if len(conf.PodExcludeList) > 0 || len(conf.PodExcludes) == 0 {
+		return conf.PodExcludeList
+	}
+	keys := make([]string, 0, len(conf.PodExcludes))
+	for key := range conf.PodExcludes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	items := make(PodExcludeList, 0, len(conf.PodExcludes))
+	for _, key := range keys {
+		items = append(items, PodExclude{
+			NamespacePattern: key,
+			NamePattern:      conf.PodExcludes[key],
+		})
+	}
+	return items
+}
+
+// GetExcludeList returns the resources to exclude on the given node, matching the keys of the exclude list
+// as node name glob patterns. The result is keyed by "*", because it already applies only to the given node.
+func (conf Config) GetExcludeList(nodeName string) map[string][]string {
+	patterns := make([]string, 0, len(conf.ExcludeList))
+	for pattern := range conf.ExcludeList {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	var resNames []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matched, err := filepath.Match(pattern, nodeName)
+		if err != nil {
+			klog.Warningf("ignoring the malformed node name pattern %q: %v", pattern, err)
+			continue
+		}
+		if !matched {
+			continue
+		}
+		for _, resName := range conf.ExcludeList[pattern] {
+			if seen[resName] {
+				continue
+			}
+			seen[resName] = true
+			resNames = append(resNames, resName)
+		}
+	}
+	if len(resNames) == 0 {
+		return nil
+	}
+	return map[string][]string{
+		"*": resNames,
+	}
 }
 
 func ReadFile(configPath string) (Config, error) {
@@ -61,13 +144,20 @@ func ReadFile(configPath string) (Config, error) {
 	return conf, err
 }
 
-func Render(klConfig *kubeletconfigv1beta1.KubeletConfiguration, podExcludes map[string]string) (string, error) {
+func Render(klConfig *kubeletconfigv1beta1.KubeletConfiguration, podExcludes PodExcludeList, resourceExcludes map[string][]string) (string, error) {
 	conf := Config{
 		TopologyManagerPolicy: klConfig.TopologyManagerPolicy,
 		TopologyManagerScope:  klConfig.TopologyManagerScope,
 	}
 	if len(podExcludes) > 0 {
-		conf.PodExcludes = podExcludes
+		conf.PodExcludeList = podExcludes
+		// older RTEs read only the legacy map, so keep it whenever it can hold the same data
+		if legacy, ok := podExcludes.legacy(); ok {
+			conf.PodExcludes = legacy
+		}
+	}
+	if len(resourceExcludes) > 0 {
+		conf.ExcludeList = resourceExcludes
 	}
 	data, err := yaml.Marshal(conf)
 	return string(data), err
@@ -106,6 +196,16 @@ func AddSoftRefLabels(cm *corev1.ConfigMap, instanceName, mcpName string) *corev
 	return cm
 }
 
+// AddNodeGroupSoftRefLabels is the counterpart of AddSoftRefLabels for node groups not backed by a MachineConfigPool
+func AddNodeGroupSoftRefLabels(cm *corev1.ConfigMap, instanceName, nodeGroupName string) *corev1.ConfigMap {
+	if cm.Labels == nil {
+		cm.Labels = make(map[string]string)
+	}
+	cm.Labels[LabelOperatorName] = instanceName
+	cm.Labels[LabelNodeGroupName+"/"+LabelNodeGroupKindNodeGroup] = nodeGroupName
+	return cm
+}
+
 func UnpackConfigMap(cm *corev1.ConfigMap) (string, error) {
 	if cm == nil {
 		return "", fmt.Errorf("nil config map")
@@ -18,7 +18,12 @@ package config
 
 import (
 	"os"
+	"reflect"
 	"testing"
+
+	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+
+	"sigs.k8s.io/yaml"
 )
 
 func TestReadNonExistent(t *testing.T) {
@@ -67,6 +72,188 @@ func TestReadValidData(t *testing.T) {
 	}
 }
 
+func TestGetExcludeList(t *testing.T) {
+	conf := Config{
+		ExcludeList: map[string][]string{
+			"*":                    {"hugepages-1Gi"},
+			"worker-*":             {"memory", "hugepages-1Gi"},
+			"worker-0.example.com": {"cpu"},
+			"master-*":             {"example.com/dev"},
+			"worker-[":             {"example.com/bad"},
+		},
+	}
+
+	testCases := []struct {
+		nodeName string
+		expected map[string][]string
+	}{
+		{
+			nodeName: "worker-0.example.com",
+			expected: map[string][]string{
+				"*": {"hugepages-1Gi", "memory", "cpu"},
+			},
+		},
+		{
+			nodeName: "worker-1.example.com",
+			expected: map[string][]string{
+				"*": {"hugepages-1Gi", "memory"},
+			},
+		},
+		{
+			nodeName: "infra-0.example.com",
+			expected: map[string][]string{
+				"*": {"hugepages-1Gi"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.nodeName, func(t *testing.T) {
+			got := conf.GetExcludeList(tc.nodeName)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("unexpected exclude list: got=%#v expected=%#v", got, tc.expected)
+			}
+		})
+	}
+
+	if got := (Config{}).GetExcludeList("worker-0"); got != nil {
+		t.Errorf("unexpected exclude list from an empty configuration: %#v", got)
+	}
+}
+
+func TestRenderUnrender(t *testing.T) {
+	klConfig := &kubeletconfigv1beta1.KubeletConfiguration{
+		TopologyManagerPolicy: "single-numa-node",
+		TopologyManagerScope:  "container",
+	}
+	podExcludes := PodExcludeList{
+		{NamespacePattern: "openshift-*", NamePattern: "foo-*"},
+		{NamespacePattern: "openshift-*", NamePattern: "bar-*"},
+	}
+	resExcludes := map[string][]string{
+		"*":        {"hugepages-1Gi"},
+		"worker-0": {"memory"},
+	}
+
+	data, err := Render(klConfig, podExcludes, resExcludes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg, err := Unrender(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.TopologyManagerPolicy != "single-numa-node" || cfg.TopologyManagerScope != "container" {
+		t.Errorf("unexpected values: %#v", cfg)
+	}
+	if !reflect.DeepEqual(cfg.GetPodExcludes(), podExcludes) {
+		t.Errorf("unexpected pod excludes: %#v", cfg.GetPodExcludes())
+	}
+	if cfg.PodExcludes != nil {
+		t.Errorf("unexpected lossy legacy pod excludes: %#v", cfg.PodExcludes)
+	}
+	if !reflect.DeepEqual(cfg.ExcludeList, resExcludes) {
+		t.Errorf("unexpected exclude list: %#v", cfg.ExcludeList)
+	}
+}
+
+func TestRenderLegacyPodExcludes(t *testing.T) {
+	klConfig := &kubeletconfigv1beta1.KubeletConfiguration{
+		TopologyManagerPolicy: "single-numa-node",
+		TopologyManagerScope:  "container",
+	}
+	podExcludes := PodExcludeList{
+		{NamespacePattern: "openshift-*", NamePattern: "foo-*"},
+		{NamespacePattern: "infra", NamePattern: "*"},
+	}
+
+	data, err := Render(klConfig, podExcludes, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the configuration as read by the RTEs which know only the legacy map
+	var legacyCfg struct {
+		PodExcludes map[string]string `json:"podExcludes"`
+	}
+	if err := yaml.Unmarshal([]byte(data), &legacyCfg); err != nil {
+		t.Fatalf("unexpected error decoding the legacy pod excludes: %v", err)
+	}
+	expected := map[string]string{
+		"openshift-*": "foo-*",
+		"infra":       "*",
+	}
+	if !reflect.DeepEqual(legacyCfg.PodExcludes, expected) {
+		t.Errorf("unexpected legacy pod excludes: got=%#v expected=%#v", legacyCfg.PodExcludes, expected)
+	}
+
+	cfg, err := Unrender(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg.GetPodExcludes(), podExcludes) {
+		t.Errorf("unexpected pod excludes: got=%#v expected=%#v", cfg.GetPodExcludes(), podExcludes)
+	}
+}
+
+func TestUnrenderPodExcludes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		expected PodExcludeList
+	}{
+		{
+			name: "missing",
+			data: "topologyManagerPolicy: none\n",
+		},
+		{
+			name: "null",
+			data: "podExcludes: null\n",
+		},
+		{
+			name: "legacy map",
+			data: "podExcludes:\n  ns-b: bar\n  ns-a: foo\n",
+			expected: PodExcludeList{
+				{NamespacePattern: "ns-a", NamePattern: "foo"},
+				{NamespacePattern: "ns-b", NamePattern: "bar"},
+			},
+		},
+		{
+			name: "list over legacy map",
+			data: "podExcludes:\n  ns-a: foo\npodExcludeList:\n- namespacePattern: ns-b\n  namePattern: bar\n",
+			expected: PodExcludeList{
+				{NamespacePattern: "ns-b", NamePattern: "bar"},
+			},
+		},
+		{
+			name: "list",
+			data: "podExcludeList:\n- namespacePattern: ns-b\n  namePattern: bar\n- namespacePattern: ns-b\n  namePattern: foo\n",
+			expected: PodExcludeList{
+				{NamespacePattern: "ns-b", NamePattern: "bar"},
+				{NamespacePattern: "ns-b", NamePattern: "foo"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := Unrender(tc.data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := cfg.GetPodExcludes(); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("unexpected pod excludes: got=%#v expected=%#v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestUnrenderMalformedPodExcludes(t *testing.T) {
+	_, err := Unrender("podExcludes: 42\n")
+	if err == nil {
+		t.Errorf("unexpected success decoding malformed pod excludes")
+	}
+}
+
 const testData string = `resources:
   reservedcpus: "0"
   resourcemapping:
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package heartbeat stamps the NRT objects published by RTE with the time of the update,
+// so the consumers can tell an idle node from a node whose RTE stopped refreshing its data.
+package heartbeat
+
+import (
+	"time"
+
+	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/nrtupdater"
+)
+
+// LastUpdateAnnotation holds the time, in RFC3339 format, of the last update RTE published.
+// Unlike the topology data it changes at every update, so the updates of idle nodes are never dropped as no-op.
+const LastUpdateAnnotation = "nodetopology.openshift.io/rte-last-update"
+
+// Stamp forwards the monitor infos read from infos, adding the LastUpdateAnnotation set to the time given by now.
+func Stamp(infos <-chan nrtupdater.MonitorInfo, now func() time.Time) <-chan nrtupdater.MonitorInfo {
+	stamped := make(chan nrtupdater.MonitorInfo)
+	go func() {
+		for info := range infos {
+			annotations := make(map[string]string, len(info.Annotations)+1)
+			for key, val := range info.Annotations {
+				annotations[key] = val
+			}
+			annotations[LastUpdateAnnotation] = now().UTC().Format(time.RFC3339)
+			info.Annotations = annotations
+			stamped <- info
+		}
+		close(stamped)
+	}()
+	return stamped
+}
+
+// LastUpdate returns the time of the last update RTE published, or false if it is not known.
+func LastUpdate(annotations map[string]string) (time.Time, bool) {
+	val, ok := annotations[LastUpdateAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	ts, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ts, true
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package heartbeat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/k8sannotations"
+	"github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/nrtupdater"
+)
+
+func TestStamp(t *testing.T) {
+	ts := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	infos := make(chan nrtupdater.MonitorInfo)
+	stamped := Stamp(infos, func() time.Time { return ts })
+
+	orig := map[string]string{k8sannotations.SleepDuration: "10s"}
+	infos <- nrtupdater.MonitorInfo{Timer: true, Annotations: orig}
+	info := <-stamped
+	if !info.Timer {
+		t.Errorf("lost the monitor info data")
+	}
+	if info.Annotations[k8sannotations.SleepDuration] != "10s" {
+		t.Errorf("lost the existing annotations: %v", info.Annotations)
+	}
+	if _, ok := orig[LastUpdateAnnotation]; ok {
+		t.Errorf("modified the annotations of the monitor info")
+	}
+	got, ok := LastUpdate(info.Annotations)
+	if !ok || !got.Equal(ts) {
+		t.Errorf("unexpected last update: got=%v,%v expected=%v", got, ok, ts)
+	}
+
+	infos <- nrtupdater.MonitorInfo{}
+	info = <-stamped
+	if _, ok := LastUpdate(info.Annotations); !ok {
+		t.Errorf("missing last update on monitor info without annotations")
+	}
+
+	close(infos)
+	if _, ok := <-stamped; ok {
+		t.Errorf("stamped infos not closed")
+	}
+}
+
+func TestLastUpdate(t *testing.T) {
+	for _, annotations := range []map[string]string{
+		nil,
+		{},
+		{LastUpdateAnnotation: "10s"},
+	} {
+		if ts, ok := LastUpdate(annotations); ok {
+			t.Errorf("unexpected last update %v from %v", ts, annotations)
+		}
+	}
+}
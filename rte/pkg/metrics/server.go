@@ -0,0 +1,164 @@
+/*
+ * Copyright 2024 Red Hat, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package metrics extends the RTE metrics serving modes with serving over TLS,
+// using the certificate the operator provides through a service-serving cert secret.
+package metrics
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+
+	metricssrv "github.com/k8stopologyawareschedwg/resource-topology-exporter/pkg/metrics/server"
+)
+
+const (
+	ServingHTTPTLS = "httptls"
+)
+
+const (
+	DefaultCertFile = "/etc/secrets/rte/tls.crt"
+	DefaultKeyFile  = "/etc/secrets/rte/tls.key"
+)
+
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+}
+
+func ServingModeIsSupported(value string) (string, error) {
+	val := strings.ToLower(value)
+	if val == ServingHTTPTLS {
+		return val, nil
+	}
+	return metricssrv.ServingModeIsSupported(value)
+}
+
+func ServingModeSupported() string {
+	return metricssrv.ServingModeSupported() + "," + ServingHTTPTLS
+}
+
+func Setup(mode string, conf metricssrv.Config, tlsConf TLSConfig) error {
+	if mode != ServingHTTPTLS {
+		return metricssrv.Setup(mode, conf)
+	}
+
+	if envValue, ok := os.LookupEnv("METRICS_PORT"); ok {
+		port, err := strconv.Atoi(envValue)
+		if err != nil {
+			return fmt.Errorf("the env variable METRICS_PORT has incorrect value %q: %w", envValue, err)
+		}
+		klog.V(2).InfoS("overriding metrics port", "from", conf.Port, "to", port)
+		conf.Port = port
+	}
+
+	if ip, ok := os.LookupEnv("METRICS_ADDRESS"); ok {
+		klog.V(2).InfoS("overriding metrics address", "from", conf.IPAddress, "to", ip)
+		conf.IPAddress = ip
+	}
+
+	if err := conf.Validate(); err != nil {
+		return err
+	}
+	return SetupHTTPTLS(conf, tlsConf)
+}
+
+func SetupHTTPTLS(conf metricssrv.Config, tlsConf TLSConfig) error {
+	kpr := &keyPairReloader{
+		certFile: tlsConf.CertFile,
+		keyFile:  tlsConf.KeyFile,
+	}
+	// fail early if the certificate is not there yet
+	if _, err := kpr.GetCertificate(nil); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.InstrumentMetricHandler(
+		conf.Registerer, promhttp.HandlerFor(conf.Gatherer, promhttp.HandlerOpts{}),
+	))
+
+	srv := &http.Server{
+		Addr:              conf.Address(),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: kpr.GetCertificate,
+		},
+	}
+
+	go func() {
+		// the certificate is provided by TLSConfig.GetCertificate
+		err := srv.ListenAndServeTLS("", "")
+		if err != nil {
+			klog.Fatalf("failed to run prometheus server; %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// keyPairReloader loads again the key pair when the certificate file changes,
+// because the service-serving certificates are rotated periodically.
+type keyPairReloader struct {
+	certFile string
+	keyFile  string
+
+	lock    sync.Mutex
+	modTime time.Time
+	cert    *tls.Certificate
+}
+
+func (kpr *keyPairReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	kpr.lock.Lock()
+	defer kpr.lock.Unlock()
+
+	st, err := os.Stat(kpr.certFile)
+	if err != nil {
+		if kpr.cert != nil {
+			// keep serving with the last good certificate
+			klog.ErrorS(err, "cannot check the metrics certificate", "path", kpr.certFile)
+			return kpr.cert, nil
+		}
+		return nil, err
+	}
+	if kpr.cert != nil && st.ModTime().Equal(kpr.modTime) {
+		return kpr.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(kpr.certFile, kpr.keyFile)
+	if err != nil {
+		if kpr.cert != nil {
+			klog.ErrorS(err, "cannot reload the metrics certificate", "path", kpr.certFile)
+			return kpr.cert, nil
+		}
+		return nil, err
+	}
+	klog.InfoS("loaded the metrics certificate", "path", kpr.certFile)
+	kpr.cert = &cert
+	kpr.modTime = st.ModTime()
+	return kpr.cert, nil
+}
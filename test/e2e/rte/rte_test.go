@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -38,6 +39,7 @@ import (
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/k8stopologyawareschedwg/deployer/pkg/deployer/platform"
 	"github.com/k8stopologyawareschedwg/deployer/pkg/flagcodec"
 	k8swgobjupdate "github.com/k8stopologyawareschedwg/deployer/pkg/objectupdate"
 
@@ -48,11 +50,13 @@ import (
 	"github.com/openshift-kni/numaresources-operator/internal/machineconfigpools"
 	"github.com/openshift-kni/numaresources-operator/internal/podlist"
 	"github.com/openshift-kni/numaresources-operator/internal/remoteexec"
+	"github.com/openshift-kni/numaresources-operator/pkg/kubeletconfig"
 	"github.com/openshift-kni/numaresources-operator/pkg/loglevel"
 	"github.com/openshift-kni/numaresources-operator/pkg/objectnames"
 	rteupdate "github.com/openshift-kni/numaresources-operator/pkg/objectupdate/rte"
 	rteconfig "github.com/openshift-kni/numaresources-operator/rte/pkg/config"
 	"github.com/openshift-kni/numaresources-operator/test/utils/clients"
+	"github.com/openshift-kni/numaresources-operator/test/utils/configuration"
 	"github.com/openshift-kni/numaresources-operator/test/utils/objects"
 	operatorv1 "github.com/openshift/api/operator/v1"
 	mcov1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
@@ -139,8 +143,13 @@ var _ = ginkgo.Describe("with a running cluster with all the components", func()
 	})
 
 	ginkgo.When("[config][kubelet][rte] Kubelet Config includes reservations", func() {
-		ginkgo.It("should configure RTE accordingly", func() {
-			nropObj := &nropv1.NUMAResourcesOperator{}
+		var nropObj *nropv1.NUMAResourcesOperator
+		var namespace string
+		// the kubelet configuration the RTE ConfigMaps are expected to reflect, by ConfigMap name
+		var kubeletConfs map[string]*kubeletconfigv1beta1.KubeletConfiguration
+
+		ginkgo.BeforeEach(func() {
+			nropObj = &nropv1.NUMAResourcesOperator{}
 			err := clients.Client.Get(context.TODO(), client.ObjectKey{Name: objectnames.DefaultNUMAResourcesOperatorCrName}, nropObj)
 			gomega.Expect(err).ToNot(gomega.HaveOccurred())
 			gomega.Expect(nropObj.Status.DaemonSets).ToNot(gomega.BeEmpty())
@@ -148,31 +157,19 @@ var _ = ginkgo.Describe("with a running cluster with all the components", func()
 
 			// NROP guarantees all the daemonsets are in the same namespace,
 			// so we pick the first for the sake of brevity
-			namespace := nropObj.Status.DaemonSets[0].Namespace
+			namespace = nropObj.Status.DaemonSets[0].Namespace
 			klog.Infof("namespace %q", namespace)
 
-			mcpList := &mcov1.MachineConfigPoolList{}
-			err = clients.Client.List(context.TODO(), mcpList)
-			gomega.Expect(err).ToNot(gomega.HaveOccurred())
-			klog.Infof("MCPs count: %d", len(mcpList.Items))
-
-			mcoKcList := &mcov1.KubeletConfigList{}
-			err = clients.Client.List(context.TODO(), mcoKcList)
-			gomega.Expect(err).ToNot(gomega.HaveOccurred())
-			for _, mcoKc := range mcoKcList.Items {
-				ginkgo.By(fmt.Sprintf("Considering MCO KubeletConfig %q", mcoKc.Name))
-
-				kc, err := mcoKubeletConfToKubeletConf(&mcoKc)
-				gomega.Expect(err).ToNot(gomega.HaveOccurred())
-
-				mcps, err := nodegroupv1.FindMachineConfigPools(mcpList, nropObj.Spec.NodeGroups)
-				gomega.Expect(err).ToNot(gomega.HaveOccurred())
-
-				mcp, err := machineconfigpools.FindBySelector(mcps, mcoKc.Spec.MachineConfigPoolSelector)
-				ginkgo.By(fmt.Sprintf("Considering MCP %q", mcp.Name))
-				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			// on OpenShift the MCO KubeletConfigs are authoritative, elsewhere the operator reads the kubelet configz of the nodes
+			if configuration.Plat == platform.OpenShift {
+				kubeletConfs = kubeletConfsFromMCO(nropObj)
+			} else {
+				kubeletConfs = kubeletConfsFromConfigz(nropObj)
+			}
+		})
 
-				generatedName := objectnames.GetComponentName(nropObj.Name, mcp.Name)
+		ginkgo.It("should configure RTE accordingly", func() {
+			for generatedName, kc := range kubeletConfs {
 				klog.Infof("generated config map name: %q", generatedName)
 				cm, err := clients.K8sClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), generatedName, metav1.GetOptions{})
 				gomega.Expect(err).ToNot(gomega.HaveOccurred())
@@ -187,38 +184,15 @@ var _ = ginkgo.Describe("with a running cluster with all the components", func()
 		})
 
 		ginkgo.It("should keep the ConfigMap aligned with the KubeletConfig info", func() {
-			nropObj := &nropv1.NUMAResourcesOperator{}
-			err := clients.Client.Get(context.TODO(), client.ObjectKey{Name: objectnames.DefaultNUMAResourcesOperatorCrName}, nropObj)
-			gomega.Expect(err).ToNot(gomega.HaveOccurred())
-			gomega.Expect(nropObj.Status.DaemonSets).ToNot(gomega.BeEmpty())
-			klog.Infof("NRO %q", nropObj.Name)
-
-			// NROP guarantees all the daemonsets are in the same namespace,
-			// so we pick the first for the sake of brevity
-			namespace := nropObj.Status.DaemonSets[0].Namespace
-			klog.Infof("namespace %q", namespace)
-
-			mcpList := &mcov1.MachineConfigPoolList{}
-			err = clients.Client.List(context.TODO(), mcpList)
-			gomega.Expect(err).ToNot(gomega.HaveOccurred())
-			klog.Infof("MCPs count: %d", len(mcpList.Items))
-
-			mcoKcList := &mcov1.KubeletConfigList{}
-			err = clients.Client.List(context.TODO(), mcoKcList)
-			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(kubeletConfs).ToNot(gomega.BeEmpty())
+			generatedNames := make([]string, 0, len(kubeletConfs))
+			for generatedName := range kubeletConfs {
+				generatedNames = append(generatedNames, generatedName)
+			}
+			sort.Strings(generatedNames)
 
 			// pick the first for the sake of brevity
-			mcoKc := mcoKcList.Items[0]
-			ginkgo.By(fmt.Sprintf("Considering MCO KubeletConfig %q", mcoKc.Name))
-
-			mcps, err := nodegroupv1.FindMachineConfigPools(mcpList, nropObj.Spec.NodeGroups)
-			gomega.Expect(err).ToNot(gomega.HaveOccurred())
-
-			mcp, err := machineconfigpools.FindBySelector(mcps, mcoKc.Spec.MachineConfigPoolSelector)
-			ginkgo.By(fmt.Sprintf("Considering MCP %q", mcp.Name))
-			gomega.Expect(err).ToNot(gomega.HaveOccurred())
-
-			generatedName := objectnames.GetComponentName(nropObj.Name, mcp.Name)
+			generatedName := generatedNames[0]
 			klog.Infof("generated config map name: %q", generatedName)
 			cm, err := clients.K8sClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), generatedName, metav1.GetOptions{})
 			gomega.Expect(err).ToNot(gomega.HaveOccurred())
@@ -326,6 +300,65 @@ func matchLogLevelToKlog(cnt *corev1.Container, level operatorv1.LogLevel) (bool
 	return found, val.Data == kLvl.String()
 }
 
+// kubeletConfsFromMCO returns the kubelet configuration of the MCO KubeletConfigs, by RTE ConfigMap name.
+func kubeletConfsFromMCO(nropObj *nropv1.NUMAResourcesOperator) map[string]*kubeletconfigv1beta1.KubeletConfiguration {
+	mcpList := &mcov1.MachineConfigPoolList{}
+	err := clients.Client.List(context.TODO(), mcpList)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+	klog.Infof("MCPs count: %d", len(mcpList.Items))
+
+	mcoKcList := &mcov1.KubeletConfigList{}
+	err = clients.Client.List(context.TODO(), mcoKcList)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+	kubeletConfs := make(map[string]*kubeletconfigv1beta1.KubeletConfiguration)
+	for _, mcoKc := range mcoKcList.Items {
+		ginkgo.By(fmt.Sprintf("Considering MCO KubeletConfig %q", mcoKc.Name))
+
+		kc, err := mcoKubeletConfToKubeletConf(&mcoKc)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+		mcps, err := nodegroupv1.FindMachineConfigPools(mcpList, nropObj.Spec.NodeGroups)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+		mcp, err := machineconfigpools.FindBySelector(mcps, mcoKc.Spec.MachineConfigPoolSelector)
+		gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		ginkgo.By(fmt.Sprintf("Considering MCP %q", mcp.Name))
+
+		kubeletConfs[objectnames.GetComponentName(nropObj.Name, mcp.Name)] = kc
+	}
+	return kubeletConfs
+}
+
+// kubeletConfsFromConfigz returns the kubelet configuration reported by the nodes through /configz, by RTE ConfigMap name.
+// The nodes of a node group are expected to share the same configuration, so the first one is used.
+func kubeletConfsFromConfigz(nropObj *nropv1.NUMAResourcesOperator) map[string]*kubeletconfigv1beta1.KubeletConfiguration {
+	// node groups must use NodeSelector outside OpenShift, so no MachineConfigPools are involved
+	trees, err := nodegroupv1.FindTrees(&mcov1.MachineConfigPoolList{}, nropObj.Spec.NodeGroups)
+	gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+	kubeletConfs := make(map[string]*kubeletconfigv1beta1.KubeletConfiguration)
+	for _, tree := range trees {
+		for _, pool := range tree.Pools() {
+			ginkgo.By(fmt.Sprintf("Considering node group %q", pool.Name))
+
+			sel, err := metav1.LabelSelectorAsSelector(pool.NodeSelector)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			nodes := &corev1.NodeList{}
+			err = clients.Client.List(context.TODO(), nodes, &client.ListOptions{LabelSelector: sel})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			gomega.Expect(nodes.Items).ToNot(gomega.BeEmpty(), "no nodes in node group %q", pool.Name)
+
+			kc, err := kubeletconfig.FetchFromConfigz(context.TODO(), clients.K8sClient.CoreV1().RESTClient(), nodes.Items[0].Name)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			kubeletConfs[objectnames.GetComponentName(nropObj.Name, pool.Name)] = kc
+		}
+	}
+	return kubeletConfs
+}
+
 func mcoKubeletConfToKubeletConf(mcoKc *mcov1.KubeletConfig) (*kubeletconfigv1beta1.KubeletConfiguration, error) {
 	kc := &kubeletconfigv1beta1.KubeletConfiguration{}
 	err := json.Unmarshal(mcoKc.Spec.KubeletConfig.Raw, kc)
@@ -70,6 +70,14 @@ var _ = Describe("[Uninstall]", func() {
 				return
 			}
 
+			if configuration.Plat == platform.Kubernetes {
+				// no MachineConfigPools nor KubeletConfigs to take care of
+				if err := e2eclient.Client.Delete(context.TODO(), nroObj); err != nil {
+					klog.Warningf("failed to delete the numaresourcesoperators %q", nroObj.Name)
+				}
+				return
+			}
+
 			unpause, err := e2epause.MachineConfigPoolsByNodeGroups(nroObj.Spec.NodeGroups)
 			Expect(err).NotTo(HaveOccurred())
 
@@ -85,13 +93,6 @@ var _ = Describe("[Uninstall]", func() {
 			err = unpause()
 			Expect(err).NotTo(HaveOccurred())
 
-			if configuration.Plat == platform.Kubernetes {
-				mcpObj := objects.TestMCP()
-				if err := e2eclient.Client.Delete(context.TODO(), mcpObj); err != nil {
-					klog.Warningf("failed to delete the machine config pool %q", mcpObj.Name)
-				}
-			}
-
 			if configuration.Plat == platform.OpenShift {
 				Eventually(func() bool {
 					mcps, err := nropmcp.GetListByNodeGroupsV1(context.TODO(), e2eclient.Client, nroObj.Spec.NodeGroups)
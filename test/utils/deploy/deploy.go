@@ -62,18 +62,13 @@ type NroDeploymentWithSched struct {
 func OverallDeployment() NroDeployment {
 	GinkgoHelper()
 
-	var matchLabels map[string]string
-	var deployedObj NroDeployment
-
 	if configuration.Plat == platform.Kubernetes {
-		mcpObj := objects.TestMCP()
-		By(fmt.Sprintf("creating the machine config pool object: %s", mcpObj.Name))
-		err := e2eclient.Client.Create(context.TODO(), mcpObj)
-		Expect(err).NotTo(HaveOccurred())
-		deployedObj.McpObj = mcpObj
-		matchLabels = map[string]string{"test": "test"}
+		return overallDeploymentWithNodeSelector()
 	}
 
+	var matchLabels map[string]string
+	var deployedObj NroDeployment
+
 	if configuration.Plat == platform.OpenShift {
 		// TODO: should this be configurable?
 		matchLabels = objects.OpenshiftMatchLabels()
@@ -112,6 +107,26 @@ func OverallDeployment() NroDeployment {
 	return deployedObj
 }
 
+// overallDeploymentWithNodeSelector deploys on platforms without MachineConfigPools and KubeletConfigs:
+// the node group selects the nodes directly, and the RTE reads the kubelet configuration from the host.
+func overallDeploymentWithNodeSelector() NroDeployment {
+	GinkgoHelper()
+
+	var deployedObj NroDeployment
+
+	nroObj := objects.TestNROWithNodeSelector(objects.KubernetesNodeGroupName, objects.KubernetesMatchLabels())
+
+	By(fmt.Sprintf("creating the NRO object: %s", nroObj.Name))
+	err := e2eclient.Client.Create(context.TODO(), nroObj)
+	Expect(err).NotTo(HaveOccurred())
+
+	err = e2eclient.Client.Get(context.TODO(), client.ObjectKeyFromObject(nroObj), nroObj)
+	Expect(err).NotTo(HaveOccurred())
+	deployedObj.NroObj = nroObj
+
+	return deployedObj
+}
+
 func GetDeploymentWithSched() (NroDeploymentWithSched, error) {
 	sd := NroDeploymentWithSched{}
 
@@ -89,6 +89,26 @@ func TestNRO(matchLabels map[string]string) *nropv1.NUMAResourcesOperator {
 	}
 }
 
+// KubernetesNodeGroupName is the name of the node group used on platforms without MachineConfigPools
+const KubernetesNodeGroupName = "worker"
+
+func KubernetesMatchLabels() map[string]string {
+	return map[string]string{"node-role.kubernetes.io/worker": ""}
+}
+
+func TestNROWithNodeSelector(name string, matchLabels map[string]string) *nropv1.NUMAResourcesOperator {
+	nro := TestNRO(nil)
+	nro.Spec.NodeGroups = []nropv1.NodeGroup{
+		{
+			Name: name,
+			NodeSelector: &metav1.LabelSelector{
+				MatchLabels: matchLabels,
+			},
+		},
+	}
+	return nro
+}
+
 func TestMCP() *machineconfigv1.MachineConfigPool {
 	return &machineconfigv1.MachineConfigPool{
 		TypeMeta: metav1.TypeMeta{